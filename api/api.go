@@ -24,6 +24,7 @@ import (
 	"github.com/mantlenetworkio/lithosphere/config"
 	"github.com/mantlenetworkio/lithosphere/database"
 	metrics2 "github.com/mantlenetworkio/lithosphere/metrics"
+	"github.com/mantlenetworkio/lithosphere/synchronizer/node"
 )
 
 const ethereumAddressRegex = `^0x[a-fA-F0-9]{40}$`
@@ -32,6 +33,10 @@ const (
 	MetricsNamespace = "lithosphere_api"
 	idParam          = "{id}"
 	indexParam       = "{index}"
+	hashParam        = "{hash}"
+	numberParam      = "{number}"
+	proofSuffix      = "/proof"
+	timelineSuffix   = "/timeline"
 
 	HealthPath           = "/healthz"
 	MetricsPath          = "/api/metrics"
@@ -42,6 +47,20 @@ const (
 	DataStoreTxByIDPath  = "/api/v1/datastore/transaction/id/"
 	StateRootListPath    = "/api/v1/stateroot/list"
 	StateRootByIndexPath = "/api/v1/stateroot/index/"
+
+	ERC721DepositsPath      = "/api/v1/erc721/deposits"
+	ERC721WithdrawalsPath   = "/api/v1/erc721/withdrawals"
+	ERC721ByMessageHashPath = "/api/v1/erc721/message/"
+
+	SystemConfigUpdatesPath   = "/api/v1/systemconfig/updates"
+	SystemConfigAtL1BlockPath = "/api/v1/systemconfig/l1/"
+	SystemConfigAtL2BlockPath = "/api/v1/systemconfig/l2/"
+
+	FeeVaultBalancesPath    = "/api/v1/feevault/balances"
+	FeeVaultWithdrawalsPath = "/api/v1/feevault/withdrawals"
+	FeeVaultRevenuePath     = "/api/v1/feevault/revenue"
+
+	WithdrawalIncidentsPath = "/api/v1/incidents"
 )
 
 type APIConfig struct {
@@ -56,6 +75,7 @@ type API struct {
 	apiServer       *httputil.HTTPServer
 	metricsServer   *httputil.HTTPServer
 	db              *database.DB
+	l2Client        node.EthClient
 	stopped         atomic.Bool
 }
 
@@ -80,6 +100,9 @@ func (a *API) initFromConfig(ctx context.Context, cfg *config.Config, log log.Lo
 	if err := a.startMetricsServer(cfg.MetricsServer); err != nil {
 		return fmt.Errorf("failed to start metrics server: %w", err)
 	}
+	if err := a.initL2Client(ctx, cfg, log); err != nil {
+		return fmt.Errorf("failed to dial L2: %w", err)
+	}
 	a.initRouter(cfg.HTTPServer, cfg)
 	if err := a.startServer(cfg.HTTPServer); err != nil {
 		return fmt.Errorf("failed to start API server: %w", err)
@@ -95,7 +118,7 @@ func (a *API) initRouter(conf config.ServerConfig, cfg *config.Config) {
 		lruCache = cache.NewLruCache(cfg.CacheConfig)
 	}
 
-	svc := service.New(v, a.db.DataStore, a.db.L1ToL2, a.db.L2ToL1, a.db.Blocks, a.db.StateRoots, a.log)
+	svc := service.New(v, a.db.DataStore, a.db.L1ToL2, a.db.L2ToL1, a.db.Blocks, a.db.SyncCursors, a.db.StateRoots, a.db.ERC721Bridge, a.db.SystemConfig, a.db.FeeVault, a.db.RelayAttempt, a.db.WithdrawalIncident, a.db.BridgeStatus, a.l2Client, cfg.Chain.L2Contracts.L2ToL1MessagePasser, a.log)
 	apiRouter := chi.NewRouter()
 	h := routes.NewRoutes(a.log, apiRouter, svc, cfg.ApiCacheEnable, lruCache)
 
@@ -111,10 +134,23 @@ func (a *API) initRouter(conf config.ServerConfig, cfg *config.Config) {
 	apiRouter.Get(fmt.Sprintf(DataStoreListPath), h.DataStoreListHandler)
 	apiRouter.Get(fmt.Sprintf(DepositsV1Path), h.L1ToL2ListHandler)
 	apiRouter.Get(fmt.Sprintf(WithdrawalsV1Path), h.L2ToL1ListHandler)
+	apiRouter.Get(fmt.Sprintf(WithdrawalsV1Path+"/"+hashParam+proofSuffix), h.WithdrawalProofHandler)
+	apiRouter.Get(fmt.Sprintf(WithdrawalsV1Path+"/"+hashParam+timelineSuffix), h.WithdrawalTimelineHandler)
+	apiRouter.Get(fmt.Sprintf(DepositsV1Path+"/"+hashParam+timelineSuffix), h.DepositTimelineHandler)
 	apiRouter.Get(fmt.Sprintf(DataStoreByIDPath+idParam), h.DataStoreByIdHandler)
 	apiRouter.Get(fmt.Sprintf(DataStoreTxByIDPath+idParam), h.DataStoreBlockByIDHandler)
 	apiRouter.Get(fmt.Sprintf(StateRootListPath), h.StateRootListHandler)
 	apiRouter.Get(fmt.Sprintf(StateRootByIndexPath+indexParam), h.StateRootByIndexHandler)
+	apiRouter.Get(fmt.Sprintf(ERC721DepositsPath), h.ERC721DepositListHandler)
+	apiRouter.Get(fmt.Sprintf(ERC721WithdrawalsPath), h.ERC721WithdrawalListHandler)
+	apiRouter.Get(fmt.Sprintf(ERC721ByMessageHashPath+hashParam), h.ERC721BridgeByMessageHashHandler)
+	apiRouter.Get(fmt.Sprintf(SystemConfigUpdatesPath), h.SystemConfigUpdateListHandler)
+	apiRouter.Get(fmt.Sprintf(SystemConfigAtL1BlockPath+numberParam), h.SystemConfigAtL1BlockHandler)
+	apiRouter.Get(fmt.Sprintf(SystemConfigAtL2BlockPath+numberParam), h.SystemConfigAtL2BlockHandler)
+	apiRouter.Get(fmt.Sprintf(FeeVaultBalancesPath), h.FeeVaultBalancesHandler)
+	apiRouter.Get(fmt.Sprintf(FeeVaultWithdrawalsPath), h.FeeVaultWithdrawalListHandler)
+	apiRouter.Get(fmt.Sprintf(FeeVaultRevenuePath), h.FeeVaultRevenueHandler)
+	apiRouter.Get(fmt.Sprintf(WithdrawalIncidentsPath), h.WithdrawalIncidentListHandler)
 
 	a.router = apiRouter
 }
@@ -139,6 +175,20 @@ func (a *API) initDB(ctx context.Context, cfg *config.Config, log log.Logger) er
 	return nil
 }
 
+// initL2Client dials the L2 rpc endpoints when configured, the withdrawal proofs are served from them
+func (a *API) initL2Client(ctx context.Context, cfg *config.Config, log log.Logger) error {
+	if len(cfg.RPCs.L2RPCs) == 0 {
+		log.Warn("no L2 rpc endpoint configured, withdrawal proofs are disabled")
+		return nil
+	}
+	l2Client, err := node.DialEthClientPool(ctx, log.New("rpc", "l2"), cfg.RPCs.L2RPCs, cfg.RPCs.L2Quorum, metrics2.NewNodeMetrics(a.metricsRegistry, "l2"))
+	if err != nil {
+		return err
+	}
+	a.l2Client = l2Client
+	return nil
+}
+
 func (a *API) Start(ctx context.Context) error {
 	return nil
 }
@@ -155,6 +205,9 @@ func (a *API) Stop(ctx context.Context) error {
 			result = errors.Join(result, fmt.Errorf("failed to stop metrics server: %w", err))
 		}
 	}
+	if a.l2Client != nil {
+		a.l2Client.Close()
+	}
 	if a.db != nil {
 		if err := a.db.Close(); err != nil {
 			result = errors.Join(result, fmt.Errorf("failed to close DB: %w", err))
@@ -1,6 +1,14 @@
 package models
 
-import "github.com/mantlenetworkio/lithosphere/database/business"
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/mantlenetworkio/lithosphere/database/business"
+	"github.com/mantlenetworkio/lithosphere/database/event"
+)
 
 type QueryDWParams struct {
 	Address  string
@@ -23,18 +31,130 @@ type QueryIndexParams struct {
 	Index uint64
 }
 
+// Deposit is a deposit along with the relays of its message on L2, oldest first
+type Deposit struct {
+	business.L1ToL2
+	RelayAttempts []event.RelayAttempt `json:"relayAttempts"`
+}
+
 type DepositsResponse struct {
-	Current int               `json:"Current"`
-	Size    int               `json:"Size"`
-	Total   int64             `json:"Total"`
-	Records []business.L1ToL2 `json:"Records"`
+	Current int       `json:"Current"`
+	Size    int       `json:"Size"`
+	Total   int64     `json:"Total"`
+	Records []Deposit `json:"Records"`
+}
+
+// Withdrawal is a withdrawal along with the relays of its message on L1, oldest first
+type Withdrawal struct {
+	business.L2ToL1
+	RelayAttempts []event.RelayAttempt `json:"relayAttempts"`
 }
 
 type WithdrawsResponse struct {
-	Current int               `json:"Current"`
-	Size    int               `json:"Size"`
-	Total   int64             `json:"Total"`
-	Records []business.L2ToL1 `json:"Records"`
+	Current int          `json:"Current"`
+	Size    int          `json:"Size"`
+	Total   int64        `json:"Total"`
+	Records []Withdrawal `json:"Records"`
+}
+
+type QueryHashParams struct {
+	Hash common.Hash
+}
+
+type QueryBlockParams struct {
+	Number uint64
+}
+
+type QueryFeeVaultParams struct {
+	Vault    common.Address
+	Page     int
+	PageSize int
+	Order    string
+}
+
+type QueryIncidentParams struct {
+	Kind     string
+	Page     int
+	PageSize int
+	Order    string
+}
+
+type QueryFeeVaultRevenueParams struct {
+	Vault         common.Address
+	Period        string
+	FromTimestamp int64
+	ToTimestamp   int64
+}
+
+type SystemConfigUpdatesResponse struct {
+	Current int                           `json:"Current"`
+	Size    int                           `json:"Size"`
+	Total   int64                         `json:"Total"`
+	Records []business.SystemConfigUpdate `json:"Records"`
+}
+
+// SystemConfigResponse is the SystemConfig effective at a block, along with the updates that set each value
+type SystemConfigResponse struct {
+	L1BlockNumber     uint64                        `json:"l1BlockNumber,omitempty"`
+	L2BlockNumber     uint64                        `json:"l2BlockNumber,omitempty"`
+	L1OriginNumber    uint64                        `json:"l1OriginNumber,omitempty"`
+	BatcherHash       *common.Hash                  `json:"batcherHash"`
+	Overhead          *big.Int                      `json:"overhead"`
+	Scalar            *big.Int                      `json:"scalar"`
+	GasLimit          *big.Int                      `json:"gasLimit"`
+	UnsafeBlockSigner *common.Address               `json:"unsafeBlockSigner"`
+	Updates           []business.SystemConfigUpdate `json:"updates"`
+}
+
+// FeeVaultWithdrawal is a fee vault withdrawal along with the l2_to_l1 record of its bridged fees
+type FeeVaultWithdrawal struct {
+	business.FeeVaultWithdrawal
+	Withdrawal *business.L2ToL1 `json:"withdrawal"`
+}
+
+type FeeVaultWithdrawalsResponse struct {
+	Current int                  `json:"Current"`
+	Size    int                  `json:"Size"`
+	Total   int64                `json:"Total"`
+	Records []FeeVaultWithdrawal `json:"Records"`
+}
+
+type WithdrawalIncidentsResponse struct {
+	Current int                           `json:"Current"`
+	Size    int                           `json:"Size"`
+	Total   int64                         `json:"Total"`
+	Records []business.WithdrawalIncident `json:"Records"`
+}
+
+type FeeVaultBalancesResponse struct {
+	Records []business.FeeVaultBalance `json:"Records"`
+}
+
+// FeeVaultRevenue is the revenue of a fee vault over the period starting at the Period timestamp: the
+// fees withdrawn from the vault plus the growth of its balance
+type FeeVaultRevenue struct {
+	Period         int64    `json:"period"`
+	OpeningBalance *big.Int `json:"openingBalance"`
+	ClosingBalance *big.Int `json:"closingBalance"`
+	Withdrawn      *big.Int `json:"withdrawn"`
+	Revenue        *big.Int `json:"revenue"`
+}
+
+// FeeVaultRevenueResponse is the revenue series of a fee vault. Its balances are only sampled from the
+// first sampled block on, nil while nothing was sampled, the revenue before it is unknown.
+type FeeVaultRevenueResponse struct {
+	VaultAddress              common.Address    `json:"vaultAddress"`
+	Period                    string            `json:"period"`
+	FirstSampledL2BlockNumber *big.Int          `json:"firstSampledL2BlockNumber"`
+	FirstSampledTimestamp     int64             `json:"firstSampledTimestamp"`
+	Records                   []FeeVaultRevenue `json:"Records"`
+}
+
+type ERC721BridgesResponse struct {
+	Current int                     `json:"Current"`
+	Size    int                     `json:"Size"`
+	Total   int64                   `json:"Total"`
+	Records []business.ERC721Bridge `json:"Records"`
 }
 
 type DataStoreListItem struct {
@@ -66,3 +186,26 @@ type StateRootListResponse struct {
 	Total   int64                `json:"Total"`
 	Records []business.StateRoot `json:"Records"`
 }
+
+// OutputRootProof is the preimage of an L2 output root
+type OutputRootProof struct {
+	Version                  common.Hash `json:"version"`
+	StateRoot                common.Hash `json:"stateRoot"`
+	MessagePasserStorageRoot common.Hash `json:"messagePasserStorageRoot"`
+	LatestBlockhash          common.Hash `json:"latestBlockhash"`
+}
+
+// WithdrawalProofResponse holds the output and proofs passed to OptimismPortal.proveWithdrawalTransaction along with the withdrawal
+type WithdrawalProofResponse struct {
+	WithdrawalHash  common.Hash     `json:"withdrawalHash"`
+	L2OutputIndex   *big.Int        `json:"l2OutputIndex"`
+	L2BlockNumber   *big.Int        `json:"l2BlockNumber"`
+	OutputRoot      common.Hash     `json:"outputRoot"`
+	OutputRootProof OutputRootProof `json:"outputRootProof"`
+	WithdrawalProof []hexutil.Bytes `json:"withdrawalProof"`
+}
+
+// BridgeTimelineResponse holds the status transitions of a deposit or withdrawal, oldest first
+type BridgeTimelineResponse struct {
+	Timeline []business.BridgeStatusHistory `json:"timeline"`
+}
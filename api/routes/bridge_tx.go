@@ -1,8 +1,14 @@
 package routes
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/mantlenetworkio/lithosphere/api/models"
+	"github.com/mantlenetworkio/lithosphere/api/service"
 )
 
 // L1ToL2ListHandler ... Handles /api/v1/deposits GET requests
@@ -56,7 +62,7 @@ func (h Routes) L2ToL1ListHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	cacheKey := fmt.Sprintf("l2ToL1List{address:s%,page:%s,pageSize:%s,order:%s}", address, pageQuery, pageSizeQuery, order)
+	cacheKey := fmt.Sprintf("l2ToL1List{address:%s,page:%s,pageSize:%s,order:%s}", address, pageQuery, pageSizeQuery, order)
 	if h.enableCache {
 		response, _ := h.cache.GetL2ToL1List(cacheKey)
 		if response != nil {
@@ -83,3 +89,76 @@ func (h Routes) L2ToL1ListHandler(w http.ResponseWriter, r *http.Request) {
 		h.logger.Error("Error writing response", "err", err.Error())
 	}
 }
+
+// WithdrawalProofHandler ... Handles /api/v1/withdrawals/{hash}/proof GET requests
+func (h Routes) WithdrawalProofHandler(w http.ResponseWriter, r *http.Request) {
+	hashStr := chi.URLParam(r, "hash")
+
+	params, err := h.svc.QueryByHashParams(hashStr)
+	if err != nil {
+		http.Error(w, "invalid query params", http.StatusBadRequest)
+		h.logger.Error("error reading request params", "err", err.Error())
+		return
+	}
+
+	proof, err := h.svc.GetWithdrawalProof(params)
+	switch {
+	case errors.Is(err, service.ErrWithdrawalProofUnavailable):
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	case errors.Is(err, service.ErrWithdrawalNotProvable):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case err != nil:
+		http.Error(w, "Internal server error building withdrawal proof", http.StatusInternalServerError)
+		h.logger.Error("Unable to build withdrawal proof", "hash", params.Hash, "err", err.Error())
+		return
+	}
+	if proof == nil {
+		http.Error(w, "withdrawal not found", http.StatusNotFound)
+		return
+	}
+
+	err = jsonResponse(w, proof, http.StatusOK)
+	if err != nil {
+		h.logger.Error("Error writing response", "err", err.Error())
+	}
+}
+
+// WithdrawalTimelineHandler ... Handles /api/v1/withdrawals/{hash}/timeline GET requests
+func (h Routes) WithdrawalTimelineHandler(w http.ResponseWriter, r *http.Request) {
+	h.bridgeTimeline(w, r, "withdrawal", h.svc.GetWithdrawalTimeline)
+}
+
+// DepositTimelineHandler ... Handles /api/v1/deposits/{hash}/timeline GET requests, the hash being the L1 transaction hash
+func (h Routes) DepositTimelineHandler(w http.ResponseWriter, r *http.Request) {
+	h.bridgeTimeline(w, r, "deposit", h.svc.GetDepositTimeline)
+}
+
+// bridgeTimeline serves the timeline uncached, a reorg may move the bridge back at any time
+func (h Routes) bridgeTimeline(w http.ResponseWriter, r *http.Request, bridge string, timelineFn func(*models.QueryHashParams) (*models.BridgeTimelineResponse, error)) {
+	hashStr := chi.URLParam(r, "hash")
+
+	params, err := h.svc.QueryByHashParams(hashStr)
+	if err != nil {
+		http.Error(w, "invalid query params", http.StatusBadRequest)
+		h.logger.Error("error reading request params", "err", err.Error())
+		return
+	}
+
+	timeline, err := timelineFn(params)
+	if err != nil {
+		http.Error(w, "Internal server error reading "+bridge+" timeline", http.StatusInternalServerError)
+		h.logger.Error("Unable to read "+bridge+" timeline from DB", "hash", params.Hash, "err", err.Error())
+		return
+	}
+	if timeline == nil {
+		http.Error(w, bridge+" not found", http.StatusNotFound)
+		return
+	}
+
+	err = jsonResponse(w, timeline, http.StatusOK)
+	if err != nil {
+		h.logger.Error("Error writing response", "err", err.Error())
+	}
+}
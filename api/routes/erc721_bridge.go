@@ -0,0 +1,104 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/mantlenetworkio/lithosphere/api/models"
+)
+
+// ERC721DepositListHandler ... Handles /api/v1/erc721/deposits GET requests
+func (h Routes) ERC721DepositListHandler(w http.ResponseWriter, r *http.Request) {
+	h.erc721BridgeListHandler(w, r, "erc721DepositList", h.svc.GetERC721DepositList)
+}
+
+// ERC721WithdrawalListHandler ... Handles /api/v1/erc721/withdrawals GET requests
+func (h Routes) ERC721WithdrawalListHandler(w http.ResponseWriter, r *http.Request) {
+	h.erc721BridgeListHandler(w, r, "erc721WithdrawalList", h.svc.GetERC721WithdrawalList)
+}
+
+func (h Routes) erc721BridgeListHandler(w http.ResponseWriter, r *http.Request, name string, list func(*models.QueryDWParams) (*models.ERC721BridgesResponse, error)) {
+	address := r.URL.Query().Get("address")
+	pageQuery := r.URL.Query().Get("page")
+	pageSizeQuery := r.URL.Query().Get("pageSize")
+	order := r.URL.Query().Get("order")
+
+	params, err := h.svc.QueryDWListParams(address, pageQuery, pageSizeQuery, order)
+	if err != nil {
+		http.Error(w, "invalid query params", http.StatusBadRequest)
+		h.logger.Error("error reading request params", "err", err.Error())
+		return
+	}
+
+	cacheKey := fmt.Sprintf("%s{address:%s,page:%s,pageSize:%s,order:%s}", name, address, pageQuery, pageSizeQuery, order)
+	if h.enableCache {
+		response, _ := h.cache.GetERC721List(cacheKey)
+		if response != nil {
+			err = jsonResponse(w, response, http.StatusOK)
+			if err != nil {
+				h.logger.Error("Error writing response", "err", err.Error())
+			}
+			return
+		}
+	}
+
+	erc721Bridges, err := list(params)
+	if err != nil {
+		http.Error(w, "Internal server error reading erc721 bridge list", http.StatusInternalServerError)
+		h.logger.Error("Unable to read erc721 bridge list from DB", "err", err.Error())
+		return
+	}
+	if h.enableCache {
+		h.cache.AddERC721List(cacheKey, erc721Bridges)
+	}
+
+	err = jsonResponse(w, erc721Bridges, http.StatusOK)
+	if err != nil {
+		h.logger.Error("Error writing response", "err", err.Error())
+	}
+}
+
+// ERC721BridgeByMessageHashHandler ... Handles /api/v1/erc721/message/{hash} GET requests
+func (h Routes) ERC721BridgeByMessageHashHandler(w http.ResponseWriter, r *http.Request) {
+	hashStr := chi.URLParam(r, "hash")
+
+	params, err := h.svc.QueryByHashParams(hashStr)
+	if err != nil {
+		http.Error(w, "invalid query params", http.StatusBadRequest)
+		h.logger.Error("error reading request params", "err", err.Error())
+		return
+	}
+
+	cacheKey := fmt.Sprintf("erc721BridgeByMessageHash{hash:%s}", params.Hash.String())
+	if h.enableCache {
+		response, _ := h.cache.GetERC721ByMessageHash(cacheKey)
+		if response != nil {
+			err = jsonResponse(w, response, http.StatusOK)
+			if err != nil {
+				h.logger.Error("Error writing response", "err", err.Error())
+			}
+			return
+		}
+	}
+
+	erc721Bridge, err := h.svc.GetERC721BridgeByMessageHash(params)
+	if err != nil {
+		http.Error(w, "Internal server error reading erc721 bridge", http.StatusInternalServerError)
+		h.logger.Error("Unable to read erc721 bridge from DB", "err", err.Error())
+		return
+	}
+	if erc721Bridge == nil {
+		http.Error(w, "erc721 bridge not found", http.StatusNotFound)
+		return
+	}
+	if h.enableCache {
+		h.cache.AddERC721ByMessageHash(cacheKey, erc721Bridge)
+	}
+
+	err = jsonResponse(w, erc721Bridge, http.StatusOK)
+	if err != nil {
+		h.logger.Error("Error writing response", "err", err.Error())
+	}
+}
@@ -0,0 +1,105 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// FeeVaultBalancesHandler ... Handles /api/v1/feevault/balances GET requests
+func (h Routes) FeeVaultBalancesHandler(w http.ResponseWriter, r *http.Request) {
+	balances, err := h.svc.GetFeeVaultBalances()
+	if err != nil {
+		http.Error(w, "Internal server error reading fee vault balances", http.StatusInternalServerError)
+		h.logger.Error("Unable to read fee vault balances from DB", "err", err.Error())
+		return
+	}
+
+	err = jsonResponse(w, balances, http.StatusOK)
+	if err != nil {
+		h.logger.Error("Error writing response", "err", err.Error())
+	}
+}
+
+// FeeVaultWithdrawalListHandler ... Handles /api/v1/feevault/withdrawals GET requests
+func (h Routes) FeeVaultWithdrawalListHandler(w http.ResponseWriter, r *http.Request) {
+	vault := r.URL.Query().Get("vault")
+	pageQuery := r.URL.Query().Get("page")
+	pageSizeQuery := r.URL.Query().Get("pageSize")
+	order := r.URL.Query().Get("order")
+
+	params, err := h.svc.QueryFeeVaultListParams(vault, pageQuery, pageSizeQuery, order)
+	if err != nil {
+		http.Error(w, "invalid query params", http.StatusBadRequest)
+		h.logger.Error("error reading request params", "err", err.Error())
+		return
+	}
+
+	cacheKey := fmt.Sprintf("feeVaultWithdrawalList{vault:%s,page:%s,pageSize:%s,order:%s}", vault, pageQuery, pageSizeQuery, order)
+	if h.enableCache {
+		response, _ := h.cache.GetFeeVaultList(cacheKey)
+		if response != nil {
+			err = jsonResponse(w, response, http.StatusOK)
+			if err != nil {
+				h.logger.Error("Error writing response", "err", err.Error())
+			}
+			return
+		}
+	}
+
+	withdrawals, err := h.svc.GetFeeVaultWithdrawalList(params)
+	if err != nil {
+		http.Error(w, "Internal server error reading fee vault withdrawal list", http.StatusInternalServerError)
+		h.logger.Error("Unable to read fee vault withdrawal list from DB", "err", err.Error())
+		return
+	}
+	if h.enableCache {
+		h.cache.AddFeeVaultList(cacheKey, withdrawals)
+	}
+
+	err = jsonResponse(w, withdrawals, http.StatusOK)
+	if err != nil {
+		h.logger.Error("Error writing response", "err", err.Error())
+	}
+}
+
+// FeeVaultRevenueHandler ... Handles /api/v1/feevault/revenue GET requests
+func (h Routes) FeeVaultRevenueHandler(w http.ResponseWriter, r *http.Request) {
+	vault := r.URL.Query().Get("vault")
+	period := r.URL.Query().Get("period")
+	from := r.URL.Query().Get("from")
+	to := r.URL.Query().Get("to")
+
+	params, err := h.svc.QueryFeeVaultRevenueParams(vault, period, from, to)
+	if err != nil {
+		http.Error(w, "invalid query params", http.StatusBadRequest)
+		h.logger.Error("error reading request params", "err", err.Error())
+		return
+	}
+
+	cacheKey := fmt.Sprintf("feeVaultRevenue{vault:%s,period:%s,from:%s,to:%s}", vault, period, from, to)
+	if h.enableCache {
+		response, _ := h.cache.GetFeeVaultRevenue(cacheKey)
+		if response != nil {
+			err = jsonResponse(w, response, http.StatusOK)
+			if err != nil {
+				h.logger.Error("Error writing response", "err", err.Error())
+			}
+			return
+		}
+	}
+
+	revenue, err := h.svc.GetFeeVaultRevenue(params)
+	if err != nil {
+		http.Error(w, "Internal server error reading fee vault revenue", http.StatusInternalServerError)
+		h.logger.Error("Unable to read fee vault revenue from DB", "err", err.Error())
+		return
+	}
+	if h.enableCache {
+		h.cache.AddFeeVaultRevenue(cacheKey, revenue)
+	}
+
+	err = jsonResponse(w, revenue, http.StatusOK)
+	if err != nil {
+		h.logger.Error("Error writing response", "err", err.Error())
+	}
+}
@@ -0,0 +1,32 @@
+package routes
+
+import (
+	"net/http"
+)
+
+// WithdrawalIncidentListHandler ... Handles /api/v1/incidents GET requests. The incidents are never cached.
+func (h Routes) WithdrawalIncidentListHandler(w http.ResponseWriter, r *http.Request) {
+	kind := r.URL.Query().Get("kind")
+	pageQuery := r.URL.Query().Get("page")
+	pageSizeQuery := r.URL.Query().Get("pageSize")
+	order := r.URL.Query().Get("order")
+
+	params, err := h.svc.QueryIncidentListParams(kind, pageQuery, pageSizeQuery, order)
+	if err != nil {
+		http.Error(w, "invalid query params", http.StatusBadRequest)
+		h.logger.Error("error reading request params", "err", err.Error())
+		return
+	}
+
+	incidents, err := h.svc.GetWithdrawalIncidentList(params)
+	if err != nil {
+		http.Error(w, "Internal server error reading withdrawal incident list", http.StatusInternalServerError)
+		h.logger.Error("Unable to read withdrawal incident list from DB", "err", err.Error())
+		return
+	}
+
+	err = jsonResponse(w, incidents, http.StatusOK)
+	if err != nil {
+		h.logger.Error("Error writing response", "err", err.Error())
+	}
+}
@@ -0,0 +1,92 @@
+package routes
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/mantlenetworkio/lithosphere/api/models"
+	"github.com/mantlenetworkio/lithosphere/api/service"
+)
+
+// SystemConfigUpdateListHandler ... Handles /api/v1/systemconfig/updates GET requests
+func (h Routes) SystemConfigUpdateListHandler(w http.ResponseWriter, r *http.Request) {
+	pageQuery := r.URL.Query().Get("page")
+	pageSizeQuery := r.URL.Query().Get("pageSize")
+	order := r.URL.Query().Get("order")
+	params, err := h.svc.QueryPageListParams(pageQuery, pageSizeQuery, order)
+	if err != nil {
+		http.Error(w, "invalid query params", http.StatusBadRequest)
+		h.logger.Error("error reading request params", "err", err.Error())
+		return
+	}
+
+	cacheKey := fmt.Sprintf("systemConfigUpdateList{page:%s,pageSize:%s,order:%s}", pageQuery, pageSizeQuery, order)
+	if h.enableCache {
+		response, _ := h.cache.GetSystemConfigList(cacheKey)
+		if response != nil {
+			err = jsonResponse(w, response, http.StatusOK)
+			if err != nil {
+				h.logger.Error("Error writing response", "err", err.Error())
+			}
+			return
+		}
+	}
+	updates, err := h.svc.GetSystemConfigUpdateList(params)
+	if err != nil {
+		http.Error(w, "Internal server error reading system config update list", http.StatusInternalServerError)
+		h.logger.Error("Unable to read system config update list from DB", "err", err.Error())
+		return
+	}
+	if h.enableCache {
+		h.cache.AddSystemConfigList(cacheKey, updates)
+	}
+	err = jsonResponse(w, updates, http.StatusOK)
+	if err != nil {
+		h.logger.Error("Error writing response", "err", err.Error())
+	}
+}
+
+// SystemConfigAtL1BlockHandler ... Handles /api/v1/systemconfig/l1/{number} GET requests
+func (h Routes) SystemConfigAtL1BlockHandler(w http.ResponseWriter, r *http.Request) {
+	h.systemConfigAtBlockHandler(w, r, h.svc.GetSystemConfigAtL1Block)
+}
+
+// SystemConfigAtL2BlockHandler ... Handles /api/v1/systemconfig/l2/{number} GET requests
+func (h Routes) SystemConfigAtL2BlockHandler(w http.ResponseWriter, r *http.Request) {
+	h.systemConfigAtBlockHandler(w, r, h.svc.GetSystemConfigAtL2Block)
+}
+
+// systemConfigAtBlockHandler serves the effective SystemConfig uncached, as it changes
+// until the L1 blocks preceding the requested block have all been indexed
+func (h Routes) systemConfigAtBlockHandler(w http.ResponseWriter, r *http.Request, systemConfigAt func(*models.QueryBlockParams) (*models.SystemConfigResponse, error)) {
+	numberStr := chi.URLParam(r, "number")
+
+	params, err := h.svc.QueryByBlockParams(numberStr)
+	if err != nil {
+		http.Error(w, "invalid query params", http.StatusBadRequest)
+		h.logger.Error("error reading request params", "err", err.Error())
+		return
+	}
+
+	systemConfig, err := systemConfigAt(params)
+	if errors.Is(err, service.ErrL1OriginUnavailable) {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	} else if err != nil {
+		http.Error(w, "Internal server error reading system config", http.StatusInternalServerError)
+		h.logger.Error("Unable to read system config from DB", "err", err.Error())
+		return
+	}
+	if systemConfig == nil {
+		http.Error(w, "block not found", http.StatusNotFound)
+		return
+	}
+
+	err = jsonResponse(w, systemConfig, http.StatusOK)
+	if err != nil {
+		h.logger.Error("Error writing response", "err", err.Error())
+	}
+}
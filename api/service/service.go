@@ -1,31 +1,73 @@
 package service
 
 import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
 	"math/big"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/pkg/errors"
 
+	common2 "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/mantlenetworkio/lithosphere/api/models"
+	common3 "github.com/mantlenetworkio/lithosphere/common"
 	"github.com/mantlenetworkio/lithosphere/database/business"
 	"github.com/mantlenetworkio/lithosphere/database/common"
+	"github.com/mantlenetworkio/lithosphere/database/event"
+	"github.com/mantlenetworkio/lithosphere/event/op-bindings/predeploys"
+	"github.com/mantlenetworkio/lithosphere/synchronizer/node"
 )
 
+var (
+	// ErrWithdrawalProofUnavailable is returned for withdrawal proofs when no L2 rpc endpoint is configured
+	ErrWithdrawalProofUnavailable = errors.New("withdrawal proofs require an L2 rpc endpoint")
+	// ErrWithdrawalNotProvable is returned for withdrawal proofs until a canonical output covers the withdrawal
+	ErrWithdrawalNotProvable = errors.New("withdrawal not covered by a canonical output yet")
+	// ErrL1OriginUnavailable is returned for the SystemConfig of L2 blocks when no L2 rpc endpoint is configured
+	ErrL1OriginUnavailable = errors.New("the L1 origin of L2 blocks requires an L2 rpc endpoint")
+)
+
+// setL1BlockValuesEcotoneSelector is the selector of the packed L1 attributes of the Ecotone L1Block
+var setL1BlockValuesEcotoneSelector = crypto.Keccak256([]byte("setL1BlockValuesEcotone()"))[:4]
+
 type Service interface {
 	GetDepositList(*models.QueryDWParams) (*models.DepositsResponse, error)
 	GetWithdrawalList(params *models.QueryDWParams) (*models.WithdrawsResponse, error)
+	GetWithdrawalProof(*models.QueryHashParams) (*models.WithdrawalProofResponse, error)
+	GetWithdrawalTimeline(*models.QueryHashParams) (*models.BridgeTimelineResponse, error)
+	GetDepositTimeline(*models.QueryHashParams) (*models.BridgeTimelineResponse, error)
 	GetDataStoreList(*models.QueryPageParams) (*models.DataStoresResponse, error)
 	GetDataStoreById(params *models.QueryIdParams) (*business.DataStore, error)
 	GetDataStoreBlockByDataStoreId(params *models.QueryIdParams) ([]business.DataStoreBlock, error)
 	GetStateRootList(*models.QueryPageParams) (*models.StateRootListResponse, error)
 	GetStateRootByIndex(*models.QueryIndexParams) (*business.StateRoot, error)
+	GetERC721DepositList(*models.QueryDWParams) (*models.ERC721BridgesResponse, error)
+	GetERC721WithdrawalList(*models.QueryDWParams) (*models.ERC721BridgesResponse, error)
+	GetERC721BridgeByMessageHash(*models.QueryHashParams) (*business.ERC721Bridge, error)
+	GetSystemConfigUpdateList(*models.QueryPageParams) (*models.SystemConfigUpdatesResponse, error)
+	GetSystemConfigAtL1Block(*models.QueryBlockParams) (*models.SystemConfigResponse, error)
+	GetSystemConfigAtL2Block(*models.QueryBlockParams) (*models.SystemConfigResponse, error)
+	GetFeeVaultBalances() (*models.FeeVaultBalancesResponse, error)
+	GetFeeVaultWithdrawalList(*models.QueryFeeVaultParams) (*models.FeeVaultWithdrawalsResponse, error)
+	GetFeeVaultRevenue(*models.QueryFeeVaultRevenueParams) (*models.FeeVaultRevenueResponse, error)
+	GetWithdrawalIncidentList(*models.QueryIncidentParams) (*models.WithdrawalIncidentsResponse, error)
 
 	QueryDWListParams(address string, page string, pageSize string, order string) (*models.QueryDWParams, error)
 	QueryPageListParams(page string, pageSize string, order string) (*models.QueryPageParams, error)
 	QueryByIdParams(id string) (*models.QueryIdParams, error)
 	QueryByIndexParams(index string) (*models.QueryIndexParams, error)
+	QueryByHashParams(hash string) (*models.QueryHashParams, error)
+	QueryByBlockParams(number string) (*models.QueryBlockParams, error)
+	QueryFeeVaultListParams(vault string, page string, pageSize string, order string) (*models.QueryFeeVaultParams, error)
+	QueryFeeVaultRevenueParams(vault string, period string, from string, to string) (*models.QueryFeeVaultRevenueParams, error)
+	QueryIncidentListParams(kind string, page string, pageSize string, order string) (*models.QueryIncidentParams, error)
 }
 
 type HandlerSvc struct {
@@ -36,9 +78,19 @@ type HandlerSvc struct {
 	l2ToL1View    business.L2ToL1View
 	stateRootView business.StateRootView
 	blocksView    common.BlocksView
+	cursorsView   common.SyncCursorsView
+	erc721View    business.ERC721BridgeView
+	sysConfigView business.SystemConfigView
+	feeVaultView  business.FeeVaultView
+	relayView     event.RelayAttemptView
+	incidentView  business.WithdrawalIncidentView
+	statusView    business.BridgeStatusHistoryView
+	l2Client      node.EthClient
+	messagePasser common2.Address
 }
 
-func New(v *Validator, dsv business.DataStoreView, l1l2v business.L1ToL2View, l2l1v business.L2ToL1View, blv common.BlocksView, srv business.StateRootView, l log.Logger) Service {
+func New(v *Validator, dsv business.DataStoreView, l1l2v business.L1ToL2View, l2l1v business.L2ToL1View, blv common.BlocksView, scsv common.SyncCursorsView, srv business.StateRootView, erc721v business.ERC721BridgeView, scv business.SystemConfigView,
+	fvv business.FeeVaultView, rav event.RelayAttemptView, wiv business.WithdrawalIncidentView, bsv business.BridgeStatusHistoryView, l2Client node.EthClient, messagePasser common2.Address, l log.Logger) Service {
 	return &HandlerSvc{
 		logger:        l,
 		v:             v,
@@ -47,31 +99,222 @@ func New(v *Validator, dsv business.DataStoreView, l1l2v business.L1ToL2View, l2
 		l2ToL1View:    l2l1v,
 		stateRootView: srv,
 		blocksView:    blv,
+		cursorsView:   scsv,
+		erc721View:    erc721v,
+		sysConfigView: scv,
+		feeVaultView:  fvv,
+		relayView:     rav,
+		incidentView:  wiv,
+		statusView:    bsv,
+		l2Client:      l2Client,
+		messagePasser: messagePasser,
 	}
 }
 
 func (h HandlerSvc) GetDepositList(params *models.QueryDWParams) (*models.DepositsResponse, error) {
 	addressToLower := strings.ToLower(params.Address)
 	l1L2List, total := h.l1ToL2View.L1ToL2List(addressToLower, params.Page, params.PageSize, params.Order)
+	messageHashes := make([]common2.Hash, len(l1L2List))
+	for i := range l1L2List {
+		messageHashes[i] = l1L2List[i].MessageHash
+	}
+	attempts, err := h.relayView.RelayAttemptsByMessageHashes(common.SyncCursorLayerL2, messageHashes)
+	if err != nil {
+		return nil, err
+	}
+	records := make([]models.Deposit, len(l1L2List))
+	for i := range l1L2List {
+		records[i] = models.Deposit{L1ToL2: l1L2List[i], RelayAttempts: attempts[l1L2List[i].MessageHash]}
+	}
 	return &models.DepositsResponse{
 		Current: params.Page,
 		Size:    params.PageSize,
 		Total:   total,
-		Records: l1L2List,
+		Records: records,
 	}, nil
 }
 
 func (h HandlerSvc) GetWithdrawalList(params *models.QueryDWParams) (*models.WithdrawsResponse, error) {
 	addressToLower := strings.ToLower(params.Address)
 	l2L1List, total := h.l2ToL1View.L2ToL1List(addressToLower, params.Page, params.PageSize, params.Order)
+	messageHashes := make([]common2.Hash, len(l2L1List))
+	for i := range l2L1List {
+		messageHashes[i] = l2L1List[i].MessageHash
+	}
+	attempts, err := h.relayView.RelayAttemptsByMessageHashes(common.SyncCursorLayerL1, messageHashes)
+	if err != nil {
+		return nil, err
+	}
+	l1Timestamp, err := h.l1Timestamp()
+	if err != nil {
+		return nil, err
+	}
+	records := make([]models.Withdrawal, len(l2L1List))
+	for i := range l2L1List {
+		setChallengeStatus(&l2L1List[i], l1Timestamp)
+		records[i] = models.Withdrawal{L2ToL1: l2L1List[i], RelayAttempts: attempts[l2L1List[i].MessageHash]}
+	}
 	return &models.WithdrawsResponse{
 		Current: params.Page,
 		Size:    params.PageSize,
 		Total:   total,
-		Records: l2L1List,
+		Records: records,
+	}, nil
+}
+
+// GetWithdrawalProof builds the proof of the withdrawal against the first canonical output covering it: the
+// preimage of the output root and the proof of the withdrawal in the sentMessages mapping of the message passer.
+// The output root rebuilt from the L2 node is checked against the proposed one.
+func (h HandlerSvc) GetWithdrawalProof(params *models.QueryHashParams) (*models.WithdrawalProofResponse, error) {
+	if h.l2Client == nil {
+		return nil, ErrWithdrawalProofUnavailable
+	}
+	withdrawal, err := h.l2ToL1View.L2ToL1TransactionWithdrawal(params.Hash)
+	if err != nil {
+		return nil, err
+	} else if withdrawal == nil {
+		return nil, nil
+	}
+	output, err := h.stateRootView.StateRootCoveringL2Block(withdrawal.L2BlockNumber)
+	if err != nil {
+		return nil, err
+	} else if output == nil {
+		return nil, ErrWithdrawalNotProvable
+	}
+
+	header, err := h.l2Client.BlockHeaderByNumber(output.L2BlockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch L2 header %s: %w", output.L2BlockNumber, err)
+	}
+	// sentMessages is the first storage slot of the L2ToL1MessagePasser
+	slot := crypto.Keccak256Hash(params.Hash.Bytes(), common2.Hash{}.Bytes())
+	proof, err := h.l2Client.StorageProof(h.messagePasser, slot, output.L2BlockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch the storage proof of withdrawal %s: %w", params.Hash, err)
+	} else if proof.Value == nil || proof.Value.Sign() == 0 {
+		return nil, fmt.Errorf("withdrawal %s not sent at L2 block %s", params.Hash, output.L2BlockNumber)
+	}
+
+	outputRootProof := models.OutputRootProof{
+		StateRoot:                header.Root,
+		MessagePasserStorageRoot: proof.StorageHash,
+		LatestBlockhash:          header.Hash(),
+	}
+	outputRoot := crypto.Keccak256Hash(outputRootProof.Version[:], outputRootProof.StateRoot[:], outputRootProof.MessagePasserStorageRoot[:], outputRootProof.LatestBlockhash[:])
+	if proposed := common2.HexToHash(output.OutputRoot); outputRoot != proposed {
+		return nil, fmt.Errorf("output root %s of L2 block %s does not match the proposed output root %s", outputRoot, output.L2BlockNumber, proposed)
+	}
+	return &models.WithdrawalProofResponse{
+		WithdrawalHash:  params.Hash,
+		L2OutputIndex:   output.OutputIndex,
+		L2BlockNumber:   output.L2BlockNumber,
+		OutputRoot:      outputRoot,
+		OutputRootProof: outputRootProof,
+		WithdrawalProof: proof.Proof,
 	}, nil
 }
 
+// GetWithdrawalTimeline returns the status transitions of the withdrawal, nil if the withdrawal is not indexed
+func (h HandlerSvc) GetWithdrawalTimeline(params *models.QueryHashParams) (*models.BridgeTimelineResponse, error) {
+	withdrawal, err := h.l2ToL1View.L2ToL1TransactionWithdrawal(params.Hash)
+	if err != nil {
+		return nil, err
+	} else if withdrawal == nil {
+		return nil, nil
+	}
+	timeline, err := h.statusView.BridgeStatusTimeline(business.BridgeWithdrawal, []uuid.UUID{withdrawal.GUID})
+	if err != nil {
+		return nil, err
+	}
+	l1Timestamp, err := h.l1Timestamp()
+	if err != nil {
+		return nil, err
+	}
+	// the end of the challenge period is not stored, its transition is computed like the status
+	if status, _ := challengeStatus(withdrawal.Status, withdrawal.ChallengeDeadline, l1Timestamp); status == common3.L2ToL1ReadyForClaim {
+		from := int64(common3.L2ToL1InChallengePeriod)
+		timeline = append(timeline, business.BridgeStatusHistory{
+			Bridge:     business.BridgeWithdrawal,
+			BridgeGUID: withdrawal.GUID,
+			FromStatus: &from,
+			ToStatus:   common3.L2ToL1ReadyForClaim,
+			Reason:     "challenge period elapsed",
+			Chain:      common.SyncCursorLayerL1,
+			Timestamp:  withdrawal.ChallengeDeadline,
+		})
+	}
+	return &models.BridgeTimelineResponse{Timeline: timeline}, nil
+}
+
+// l1Timestamp is the timestamp of the last L1 header traversed by the synchronizer, the challenge periods are
+// elapsed against it as they are by the OptimismPortal. The headers without indexed logs are not stored, the
+// latest stored header is only a fallback until the cursor has been stamped. Zero when nothing is indexed yet.
+func (h HandlerSvc) l1Timestamp() (int64, error) {
+	cursor, err := h.cursorsView.SyncCursor(common.L1SynchronizerCursor)
+	if err != nil {
+		return 0, err
+	} else if cursor != nil && cursor.BlockTimestamp > 0 {
+		return int64(cursor.BlockTimestamp), nil
+	}
+	header, err := h.blocksView.L1LatestBlockHeader()
+	if err != nil {
+		return 0, err
+	} else if header == nil {
+		return 0, nil
+	}
+	return int64(header.Timestamp), nil
+}
+
+// setChallengeStatus computes the time left in the challenge period of the withdrawal, which is ready for claim once
+// the L1 timestamp is past its challenge deadline
+func setChallengeStatus(withdrawal *business.L2ToL1, now int64) {
+	withdrawal.Status, withdrawal.TimeLeft = challengeStatus(withdrawal.Status, withdrawal.ChallengeDeadline, now)
+}
+
+// setERC721ChallengeStatus is the setChallengeStatus of the NFT withdrawals
+func setERC721ChallengeStatus(withdrawal *business.ERC721Bridge, now int64) {
+	if withdrawal.Direction != business.ERC721Withdrawal {
+		return
+	}
+	withdrawal.Status, withdrawal.TimeLeft = challengeStatus(withdrawal.Status, withdrawal.ChallengeDeadline, now)
+}
+
+// challengeStatus returns the status of a withdrawal along with the time left in its challenge period. The deadline
+// is unknown until the finalization period has been read, the withdrawal stays in its challenge period with no time
+// left meanwhile.
+func challengeStatus(status int64, deadline int64, now int64) (int64, *big.Int) {
+	if status != common3.L2ToL1InChallengePeriod {
+		return status, new(big.Int)
+	}
+	if deadline == 0 {
+		return status, nil
+	}
+	if now > deadline {
+		return common3.L2ToL1ReadyForClaim, new(big.Int)
+	}
+	return status, big.NewInt(deadline - now)
+}
+
+// GetDepositTimeline returns the status transitions of the deposits initiated by the L1 transaction, nil if none
+// is indexed
+func (h HandlerSvc) GetDepositTimeline(params *models.QueryHashParams) (*models.BridgeTimelineResponse, error) {
+	deposits, err := h.l1ToL2View.L1ToL2sByL1TransactionHash(params.Hash)
+	if err != nil {
+		return nil, err
+	} else if len(deposits) == 0 {
+		return nil, nil
+	}
+	guids := make([]uuid.UUID, len(deposits))
+	for i := range deposits {
+		guids[i] = deposits[i].GUID
+	}
+	timeline, err := h.statusView.BridgeStatusTimeline(business.BridgeDeposit, guids)
+	if err != nil {
+		return nil, err
+	}
+	return &models.BridgeTimelineResponse{Timeline: timeline}, nil
+}
+
 func (h HandlerSvc) GetDataStoreList(params *models.QueryPageParams) (*models.DataStoresResponse, error) {
 	dsList, total := h.dataStoreView.DataStoreList(params.Page, params.PageSize, params.Order)
 	items := make([]models.DataStoreList, len(dsList))
@@ -115,6 +358,248 @@ func (h HandlerSvc) GetStateRootByIndex(params *models.QueryIndexParams) (*busin
 	return h.stateRootView.StateRootByIndex(big.NewInt(int64(params.Index)))
 }
 
+func (h HandlerSvc) GetERC721DepositList(params *models.QueryDWParams) (*models.ERC721BridgesResponse, error) {
+	return h.erc721BridgeList(business.ERC721Deposit, params)
+}
+
+func (h HandlerSvc) GetERC721WithdrawalList(params *models.QueryDWParams) (*models.ERC721BridgesResponse, error) {
+	return h.erc721BridgeList(business.ERC721Withdrawal, params)
+}
+
+func (h HandlerSvc) erc721BridgeList(direction string, params *models.QueryDWParams) (*models.ERC721BridgesResponse, error) {
+	addressToLower := strings.ToLower(params.Address)
+	erc721List, total := h.erc721View.ERC721BridgeList(direction, addressToLower, params.Page, params.PageSize, params.Order)
+	l1Timestamp, err := h.l1Timestamp()
+	if err != nil {
+		return nil, err
+	}
+	for i := range erc721List {
+		setERC721ChallengeStatus(&erc721List[i], l1Timestamp)
+	}
+	return &models.ERC721BridgesResponse{
+		Current: params.Page,
+		Size:    params.PageSize,
+		Total:   total,
+		Records: erc721List,
+	}, nil
+}
+
+func (h HandlerSvc) GetERC721BridgeByMessageHash(params *models.QueryHashParams) (*business.ERC721Bridge, error) {
+	bridge, err := h.erc721View.ERC721BridgeByMessageHash(params.Hash)
+	if err != nil || bridge == nil {
+		return bridge, err
+	}
+	l1Timestamp, err := h.l1Timestamp()
+	if err != nil {
+		return nil, err
+	}
+	setERC721ChallengeStatus(bridge, l1Timestamp)
+	return bridge, nil
+}
+
+func (h HandlerSvc) GetSystemConfigUpdateList(params *models.QueryPageParams) (*models.SystemConfigUpdatesResponse, error) {
+	updates, total := h.sysConfigView.SystemConfigUpdateList(params.Page, params.PageSize, params.Order)
+	return &models.SystemConfigUpdatesResponse{
+		Current: params.Page,
+		Size:    params.PageSize,
+		Total:   total,
+		Records: updates,
+	}, nil
+}
+
+func (h HandlerSvc) GetSystemConfigAtL1Block(params *models.QueryBlockParams) (*models.SystemConfigResponse, error) {
+	updates, err := h.sysConfigView.SystemConfigAtL1Block(new(big.Int).SetUint64(params.Number))
+	if err != nil {
+		return nil, err
+	}
+	systemConfig := systemConfigFromUpdates(updates)
+	systemConfig.L1BlockNumber = params.Number
+	return systemConfig, nil
+}
+
+// GetSystemConfigAtL2Block resolves the SystemConfig of an L2 block from the updates of the L1 blocks up to its
+// L1 origin, the updates being applied on L2 from the first block whose origin includes them. Nil is returned when
+// the L2 block has not been indexed.
+func (h HandlerSvc) GetSystemConfigAtL2Block(params *models.QueryBlockParams) (*models.SystemConfigResponse, error) {
+	if h.l2Client == nil {
+		return nil, ErrL1OriginUnavailable
+	}
+	timestamp, err := h.blocksView.L2BlockTimeStampByNum(params.Number)
+	if err != nil {
+		return nil, err
+	} else if timestamp == 0 {
+		return nil, nil
+	}
+	txs, err := h.l2Client.TxsByNumber(params.Number)
+	if err != nil {
+		return nil, err
+	}
+	origin, err := l1OriginNumber(txs)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the L1 origin of L2 block %d: %w", params.Number, err)
+	}
+	updates, err := h.sysConfigView.SystemConfigAtL1Block(new(big.Int).SetUint64(origin))
+	if err != nil {
+		return nil, err
+	}
+	systemConfig := systemConfigFromUpdates(updates)
+	systemConfig.L2BlockNumber = params.Number
+	systemConfig.L1OriginNumber = origin
+	return systemConfig, nil
+}
+
+// l1OriginNumber decodes the number of the L1 origin of an L2 block from the L1 attributes deposited to the
+// L1Block predeploy, the first transaction of every L2 block
+func l1OriginNumber(txs types.Transactions) (uint64, error) {
+	if len(txs) == 0 || txs[0].Type() != types.DepositTxType || txs[0].To() == nil || *txs[0].To() != predeploys.L1BlockAddr {
+		return 0, errors.New("no L1 attributes deposit")
+	}
+	data := txs[0].Data()
+	if len(data) < 4 {
+		return 0, fmt.Errorf("invalid L1 attributes deposit data: %x", data)
+	}
+	if bytes.Equal(data[:4], setL1BlockValuesEcotoneSelector) {
+		// the packed base fee scalar, blob base fee scalar, sequence number and timestamp precede the number
+		if len(data) < 36 {
+			return 0, fmt.Errorf("invalid L1 attributes deposit data: %x", data)
+		}
+		return binary.BigEndian.Uint64(data[28:36]), nil
+	}
+	// the number is the first argument of setL1BlockValues
+	if len(data) < 36 {
+		return 0, fmt.Errorf("invalid L1 attributes deposit data: %x", data)
+	}
+	number := new(big.Int).SetBytes(data[4:36])
+	if !number.IsUint64() {
+		return 0, fmt.Errorf("invalid L1 origin number: %s", number)
+	}
+	return number.Uint64(), nil
+}
+
+func systemConfigFromUpdates(updates []business.SystemConfigUpdate) *models.SystemConfigResponse {
+	systemConfig := &models.SystemConfigResponse{Updates: updates}
+	for i := range updates {
+		switch updates[i].UpdateType {
+		case business.SystemConfigUpdateBatcher:
+			systemConfig.BatcherHash = &updates[i].BatcherHash
+		case business.SystemConfigUpdateGasConfig:
+			systemConfig.Overhead = updates[i].Overhead
+			systemConfig.Scalar = updates[i].Scalar
+		case business.SystemConfigUpdateGasLimit:
+			systemConfig.GasLimit = updates[i].GasLimit
+		case business.SystemConfigUpdateUnsafeBlockSigner:
+			systemConfig.UnsafeBlockSigner = &updates[i].UnsafeBlockSigner
+		}
+	}
+	return systemConfig
+}
+
+func (h HandlerSvc) GetFeeVaultBalances() (*models.FeeVaultBalancesResponse, error) {
+	balances, err := h.feeVaultView.FeeVaultLatestBalances()
+	if err != nil {
+		return nil, err
+	}
+	return &models.FeeVaultBalancesResponse{Records: balances}, nil
+}
+
+// GetFeeVaultWithdrawalList lists the fee vault withdrawals along with the l2_to_l1 record of their bridged fees
+func (h HandlerSvc) GetFeeVaultWithdrawalList(params *models.QueryFeeVaultParams) (*models.FeeVaultWithdrawalsResponse, error) {
+	withdrawals, total := h.feeVaultView.FeeVaultWithdrawalList(params.Vault, params.Page, params.PageSize, params.Order)
+	l1Timestamp, err := h.l1Timestamp()
+	if err != nil {
+		return nil, err
+	}
+	records := make([]models.FeeVaultWithdrawal, len(withdrawals))
+	for i := range withdrawals {
+		records[i].FeeVaultWithdrawal = withdrawals[i]
+		if withdrawals[i].WithdrawalHash == (common2.Hash{}) {
+			continue
+		}
+		l2ToL1, err := h.l2ToL1View.L2ToL1TransactionWithdrawal(withdrawals[i].WithdrawalHash)
+		if err != nil {
+			return nil, err
+		} else if l2ToL1 != nil {
+			setChallengeStatus(l2ToL1, l1Timestamp)
+		}
+		records[i].Withdrawal = l2ToL1
+	}
+	return &models.FeeVaultWithdrawalsResponse{
+		Current: params.Page,
+		Size:    params.PageSize,
+		Total:   total,
+		Records: records,
+	}, nil
+}
+
+func (h HandlerSvc) GetWithdrawalIncidentList(params *models.QueryIncidentParams) (*models.WithdrawalIncidentsResponse, error) {
+	incidents, total := h.incidentView.WithdrawalIncidentList(params.Kind, params.Page, params.PageSize, params.Order)
+	return &models.WithdrawalIncidentsResponse{
+		Current: params.Page,
+		Size:    params.PageSize,
+		Total:   total,
+		Records: incidents,
+	}, nil
+}
+
+// GetFeeVaultRevenue computes the revenue of a fee vault per period from its sampled balances: the closing
+// balance of a period, less the closing balance of the period before, plus the fees withdrawn in between.
+// The opening balance of the first period is the last balance sampled before the range, or the first one
+// sampled within it when the range starts before the vault was indexed. Periods without any sampled
+// balance are folded into the next sampled one. The first sampled block marks where the series starts.
+func (h HandlerSvc) GetFeeVaultRevenue(params *models.QueryFeeVaultRevenueParams) (*models.FeeVaultRevenueResponse, error) {
+	response := &models.FeeVaultRevenueResponse{VaultAddress: params.Vault, Period: params.Period, Records: []models.FeeVaultRevenue{}}
+	first, err := h.feeVaultView.FeeVaultBalanceFrom(params.Vault, 0)
+	if err != nil {
+		return nil, err
+	} else if first == nil {
+		return response, nil
+	}
+	response.FirstSampledL2BlockNumber, response.FirstSampledTimestamp = first.L2BlockNumber, first.Timestamp
+
+	opening, err := h.feeVaultView.FeeVaultBalanceBefore(params.Vault, params.FromTimestamp)
+	if err != nil {
+		return nil, err
+	}
+	withdrawalsFrom := params.FromTimestamp
+	if opening == nil {
+		opening, err = h.feeVaultView.FeeVaultBalanceFrom(params.Vault, params.FromTimestamp)
+		if err != nil {
+			return nil, err
+		} else if opening == nil || opening.Timestamp > params.ToTimestamp {
+			return response, nil
+		}
+		// fees withdrawn up to the first sample are already deducted from its balance
+		withdrawalsFrom = opening.Timestamp + 1
+	}
+
+	closingBalances, err := h.feeVaultView.FeeVaultClosingBalances(params.Vault, params.Period, params.FromTimestamp, params.ToTimestamp)
+	if err != nil {
+		return nil, err
+	}
+	withdrawals, err := h.feeVaultView.FeeVaultPeriodWithdrawals(params.Vault, params.Period, withdrawalsFrom, params.ToTimestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	openingBalance := opening.Balance
+	for i, j := 0, 0; i < len(closingBalances); i++ {
+		withdrawn := new(big.Int)
+		for ; j < len(withdrawals) && withdrawals[j].Period <= closingBalances[i].Period; j++ {
+			withdrawn.Add(withdrawn, withdrawals[j].Amount)
+		}
+		revenue := new(big.Int).Sub(closingBalances[i].Amount, openingBalance)
+		response.Records = append(response.Records, models.FeeVaultRevenue{
+			Period:         closingBalances[i].Period,
+			OpeningBalance: openingBalance,
+			ClosingBalance: closingBalances[i].Amount,
+			Withdrawn:      withdrawn,
+			Revenue:        revenue.Add(revenue, withdrawn),
+		})
+		openingBalance = closingBalances[i].Amount
+	}
+	return response, nil
+}
+
 func (h HandlerSvc) QueryDWListParams(address string, page string, pageSize string, order string) (*models.QueryDWParams, error) {
 	var paraAddress string
 	if address == "0x00" {
@@ -197,3 +682,100 @@ func (h HandlerSvc) QueryByIndexParams(index string) (*models.QueryIndexParams,
 		Index: indexValue,
 	}, nil
 }
+
+func (h HandlerSvc) QueryByHashParams(hash string) (*models.QueryHashParams, error) {
+	hashValue, err := h.v.ParseValidateHash(hash)
+	if err != nil {
+		h.logger.Error("invalid query param", "hash", hash, "err", err)
+		return nil, err
+	}
+	return &models.QueryHashParams{
+		Hash: hashValue,
+	}, nil
+}
+
+func (h HandlerSvc) QueryByBlockParams(number string) (*models.QueryBlockParams, error) {
+	numberValue, err := strconv.ParseUint(number, 10, 64)
+	if err != nil {
+		return nil, errors.New("block number must be an integer value")
+	}
+	return &models.QueryBlockParams{
+		Number: numberValue,
+	}, nil
+}
+
+func (h HandlerSvc) QueryFeeVaultListParams(vault string, page string, pageSize string, order string) (*models.QueryFeeVaultParams, error) {
+	var vaultAddr common2.Address
+	if vault != "" {
+		addr, err := h.v.ParseValidateFeeVault(vault)
+		if err != nil {
+			h.logger.Error("invalid query param", "vault", vault, "err", err)
+			return nil, err
+		}
+		vaultAddr = addr
+	}
+	pageParams, err := h.QueryPageListParams(page, pageSize, order)
+	if err != nil {
+		return nil, err
+	}
+	return &models.QueryFeeVaultParams{
+		Vault:    vaultAddr,
+		Page:     pageParams.Page,
+		PageSize: pageParams.PageSize,
+		Order:    pageParams.Order,
+	}, nil
+}
+
+func (h HandlerSvc) QueryIncidentListParams(kind string, page string, pageSize string, order string) (*models.QueryIncidentParams, error) {
+	kindValue, err := h.v.ValidateIncidentKind(kind)
+	if err != nil {
+		h.logger.Error("invalid query param", "kind", kind, "err", err)
+		return nil, err
+	}
+	pageParams, err := h.QueryPageListParams(page, pageSize, order)
+	if err != nil {
+		return nil, err
+	}
+	return &models.QueryIncidentParams{
+		Kind:     kindValue,
+		Page:     pageParams.Page,
+		PageSize: pageParams.PageSize,
+		Order:    pageParams.Order,
+	}, nil
+}
+
+// QueryFeeVaultRevenueParams parses the revenue series params. The range defaults to the
+// whole indexed history, up to now.
+func (h HandlerSvc) QueryFeeVaultRevenueParams(vault string, period string, from string, to string) (*models.QueryFeeVaultRevenueParams, error) {
+	vaultAddr, err := h.v.ParseValidateFeeVault(vault)
+	if err != nil {
+		h.logger.Error("invalid query param", "vault", vault, "err", err)
+		return nil, err
+	}
+	periodValue, err := h.v.ValidateFeeVaultPeriod(period)
+	if err != nil {
+		h.logger.Error("invalid query param", "period", period, "err", err)
+		return nil, err
+	}
+	var fromValue int64
+	if from != "" {
+		if fromValue, err = strconv.ParseInt(from, 10, 64); err != nil {
+			return nil, errors.New("from must be a unix timestamp")
+		}
+	}
+	toValue := time.Now().Unix()
+	if to != "" {
+		if toValue, err = strconv.ParseInt(to, 10, 64); err != nil {
+			return nil, errors.New("to must be a unix timestamp")
+		}
+	}
+	if fromValue > toValue {
+		return nil, errors.New("from must not be after to")
+	}
+	return &models.QueryFeeVaultRevenueParams{
+		Vault:         vaultAddr,
+		Period:        periodValue,
+		FromTimestamp: fromValue,
+		ToTimestamp:   toValue,
+	}, nil
+}
@@ -0,0 +1,236 @@
+package service
+
+import (
+	"encoding/binary"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/mantlenetworkio/lithosphere/api/models"
+	common3 "github.com/mantlenetworkio/lithosphere/common"
+	"github.com/mantlenetworkio/lithosphere/database/business"
+	common2 "github.com/mantlenetworkio/lithosphere/database/common"
+	"github.com/mantlenetworkio/lithosphere/event/op-bindings/bindings"
+	"github.com/mantlenetworkio/lithosphere/event/op-bindings/predeploys"
+	"github.com/mantlenetworkio/lithosphere/synchronizer/node"
+)
+
+// testL2Blocks serves the timestamps and the L1 attributes deposits of the L2 blocks
+type testL2Blocks struct {
+	common2.BlocksView
+	node.EthClient
+	timestamps map[uint64]int64
+	txs        map[uint64]types.Transactions
+}
+
+func (b *testL2Blocks) L2BlockTimeStampByNum(number uint64) (int64, error) {
+	return b.timestamps[number], nil
+}
+
+func (b *testL2Blocks) TxsByNumber(number uint64) (types.Transactions, error) {
+	return b.txs[number], nil
+}
+
+// testSystemConfig serves the latest updates of every type up to an L1 block
+type testSystemConfig struct {
+	business.SystemConfigView
+	updates []business.SystemConfigUpdate
+}
+
+func (c *testSystemConfig) SystemConfigAtL1Block(l1BlockNumber *big.Int) ([]business.SystemConfigUpdate, error) {
+	latest := make(map[uint8]business.SystemConfigUpdate)
+	for _, update := range c.updates {
+		if update.L1BlockNumber.Cmp(l1BlockNumber) <= 0 {
+			latest[update.UpdateType] = update
+		}
+	}
+	var updates []business.SystemConfigUpdate
+	for _, update := range latest {
+		updates = append(updates, update)
+	}
+	return updates, nil
+}
+
+func l1AttributesDeposit(t *testing.T, origin uint64) *types.Transaction {
+	l1BlockABI, err := bindings.L1BlockMetaData.GetAbi()
+	require.NoError(t, err)
+	data, err := l1BlockABI.Pack("setL1BlockValues", origin, uint64(0), common.Big0, common.Hash{}, uint64(0), common.Hash{}, common.Big0, common.Big0)
+	require.NoError(t, err)
+	return types.NewTx(&types.DepositTx{To: &predeploys.L1BlockAddr, Data: data})
+}
+
+func TestSystemConfigAtL2BlockFollowsL1Origin(t *testing.T) {
+	gasConfig := func(l1BlockNumber int64, timestamp int64, scalar int64) business.SystemConfigUpdate {
+		return business.SystemConfigUpdate{L1BlockNumber: big.NewInt(l1BlockNumber), UpdateType: business.SystemConfigUpdateGasConfig,
+			Overhead: big.NewInt(2100), Scalar: big.NewInt(scalar), Timestamp: timestamp}
+	}
+	sysConfig := &testSystemConfig{updates: []business.SystemConfigUpdate{gasConfig(50, 600, 1), gasConfig(100, 1200, 2)}}
+
+	// L2 block 7 is past the L1 timestamp of the update, its origin still precedes the update block
+	blocks := &testL2Blocks{
+		timestamps: map[uint64]int64{7: 1210, 8: 1212},
+		txs:        map[uint64]types.Transactions{7: {l1AttributesDeposit(t, 99)}, 8: {l1AttributesDeposit(t, 100)}},
+	}
+	svc := &HandlerSvc{blocksView: blocks, sysConfigView: sysConfig, l2Client: blocks}
+
+	systemConfig, err := svc.GetSystemConfigAtL2Block(&models.QueryBlockParams{Number: 7})
+	require.NoError(t, err)
+	require.Equal(t, uint64(99), systemConfig.L1OriginNumber)
+	require.Equal(t, big.NewInt(1), systemConfig.Scalar)
+
+	systemConfig, err = svc.GetSystemConfigAtL2Block(&models.QueryBlockParams{Number: 8})
+	require.NoError(t, err)
+	require.Equal(t, uint64(100), systemConfig.L1OriginNumber)
+	require.Equal(t, big.NewInt(2), systemConfig.Scalar)
+
+	// not indexed
+	systemConfig, err = svc.GetSystemConfigAtL2Block(&models.QueryBlockParams{Number: 9})
+	require.NoError(t, err)
+	require.Nil(t, systemConfig)
+}
+
+func TestL1OriginNumber(t *testing.T) {
+	origin, err := l1OriginNumber(types.Transactions{l1AttributesDeposit(t, 19_000_000)})
+	require.NoError(t, err)
+	require.Equal(t, uint64(19_000_000), origin)
+
+	// the packed Ecotone attributes: scalars, sequence number and timestamp precede the number
+	data := append([]byte{}, crypto.Keccak256([]byte("setL1BlockValuesEcotone()"))[:4]...)
+	data = append(data, make([]byte, 4+4+8+8)...)
+	data = binary.BigEndian.AppendUint64(data, 19_000_001)
+	data = append(data, make([]byte, 32*5)...)
+	origin, err = l1OriginNumber(types.Transactions{types.NewTx(&types.DepositTx{To: &predeploys.L1BlockAddr, Data: data})})
+	require.NoError(t, err)
+	require.Equal(t, uint64(19_000_001), origin)
+
+	_, err = l1OriginNumber(nil)
+	require.Error(t, err)
+	to := common.HexToAddress("0x01")
+	_, err = l1OriginNumber(types.Transactions{types.NewTx(&types.DepositTx{To: &to, Data: data})})
+	require.Error(t, err)
+}
+
+func TestSetChallengeStatus(t *testing.T) {
+	withdrawal := func(status int64, deadline int64) *business.L2ToL1 {
+		return &business.L2ToL1{Status: status, ChallengeDeadline: deadline}
+	}
+
+	inChallenge := withdrawal(common3.L2ToL1InChallengePeriod, 1000)
+	setChallengeStatus(inChallenge, 400)
+	require.Equal(t, int64(common3.L2ToL1InChallengePeriod), inChallenge.Status)
+	require.Equal(t, big.NewInt(600), inChallenge.TimeLeft)
+
+	elapsed := withdrawal(common3.L2ToL1InChallengePeriod, 1000)
+	setChallengeStatus(elapsed, 1001)
+	require.Equal(t, int64(common3.L2ToL1ReadyForClaim), elapsed.Status)
+	require.Equal(t, new(big.Int), elapsed.TimeLeft)
+
+	// the finalization period has not been read yet, the deadline is unknown
+	unknown := withdrawal(common3.L2ToL1InChallengePeriod, 0)
+	setChallengeStatus(unknown, 1001)
+	require.Equal(t, int64(common3.L2ToL1InChallengePeriod), unknown.Status)
+	require.Nil(t, unknown.TimeLeft)
+
+	claimed := withdrawal(common3.L2ToL1Claimed, 1000)
+	setChallengeStatus(claimed, 1001)
+	require.Equal(t, int64(common3.L2ToL1Claimed), claimed.Status)
+	require.Equal(t, new(big.Int), claimed.TimeLeft)
+}
+
+func TestSetERC721ChallengeStatus(t *testing.T) {
+	withdrawal := &business.ERC721Bridge{Direction: business.ERC721Withdrawal, Status: common3.L2ToL1InChallengePeriod, ChallengeDeadline: 1000}
+	setERC721ChallengeStatus(withdrawal, 1001)
+	require.Equal(t, int64(common3.L2ToL1ReadyForClaim), withdrawal.Status)
+	require.Equal(t, new(big.Int), withdrawal.TimeLeft)
+
+	// deposits share the status numbers of the withdrawals but have no challenge period
+	deposit := &business.ERC721Bridge{Direction: business.ERC721Deposit, Status: common3.L2ToL1InChallengePeriod}
+	setERC721ChallengeStatus(deposit, 1001)
+	require.Equal(t, int64(common3.L2ToL1InChallengePeriod), deposit.Status)
+	require.Nil(t, deposit.TimeLeft)
+}
+
+// testFeeVault serves the daily revenue series of the balances sampled for a single vault, in block order
+type testFeeVault struct {
+	business.FeeVaultView
+	balances []business.FeeVaultBalance
+}
+
+func (v *testFeeVault) FeeVaultBalanceBefore(_ common.Address, timestamp int64) (*business.FeeVaultBalance, error) {
+	var before *business.FeeVaultBalance
+	for i := range v.balances {
+		if v.balances[i].Timestamp < timestamp {
+			before = &v.balances[i]
+		}
+	}
+	return before, nil
+}
+
+func (v *testFeeVault) FeeVaultBalanceFrom(_ common.Address, timestamp int64) (*business.FeeVaultBalance, error) {
+	for i := range v.balances {
+		if v.balances[i].Timestamp >= timestamp {
+			return &v.balances[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (v *testFeeVault) FeeVaultClosingBalances(_ common.Address, _ string, fromTimestamp, toTimestamp int64) ([]business.FeeVaultPeriodAmount, error) {
+	var closing []business.FeeVaultPeriodAmount
+	for _, balance := range v.balances {
+		if balance.Timestamp < fromTimestamp || balance.Timestamp > toTimestamp {
+			continue
+		}
+		period := balance.Timestamp - balance.Timestamp%86400
+		if len(closing) > 0 && closing[len(closing)-1].Period == period {
+			closing[len(closing)-1].Amount = balance.Balance
+		} else {
+			closing = append(closing, business.FeeVaultPeriodAmount{Period: period, Amount: balance.Balance})
+		}
+	}
+	return closing, nil
+}
+
+func (v *testFeeVault) FeeVaultPeriodWithdrawals(common.Address, string, int64, int64) ([]business.FeeVaultPeriodAmount, error) {
+	return nil, nil
+}
+
+func TestFeeVaultRevenueFirstSample(t *testing.T) {
+	vault := &testFeeVault{}
+	svc := &HandlerSvc{feeVaultView: vault}
+	params := &models.QueryFeeVaultRevenueParams{Period: business.FeeVaultPeriodDay, FromTimestamp: 0, ToTimestamp: 4 * 86400}
+
+	// nothing sampled yet
+	response, err := svc.GetFeeVaultRevenue(params)
+	require.NoError(t, err)
+	require.Nil(t, response.FirstSampledL2BlockNumber)
+	require.Empty(t, response.Records)
+
+	// the balances are sampled from the third day on, the revenue series starts there
+	vault.balances = []business.FeeVaultBalance{
+		{L2BlockNumber: big.NewInt(50), Balance: big.NewInt(10), Timestamp: 2*86400 + 100},
+		{L2BlockNumber: big.NewInt(60), Balance: big.NewInt(15), Timestamp: 2*86400 + 200},
+		{L2BlockNumber: big.NewInt(80), Balance: big.NewInt(30), Timestamp: 3*86400 + 50},
+	}
+	response, err = svc.GetFeeVaultRevenue(params)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(50), response.FirstSampledL2BlockNumber)
+	require.Equal(t, int64(2*86400+100), response.FirstSampledTimestamp)
+	require.Len(t, response.Records, 2)
+	require.Equal(t, int64(2*86400), response.Records[0].Period)
+	require.Equal(t, big.NewInt(5), response.Records[0].Revenue)
+	require.Equal(t, big.NewInt(15), response.Records[1].Revenue)
+
+	// the first sampled block is reported for a range starting after it as well
+	params.FromTimestamp = 3 * 86400
+	response, err = svc.GetFeeVaultRevenue(params)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(50), response.FirstSampledL2BlockNumber)
+	require.Len(t, response.Records, 1)
+	require.Equal(t, big.NewInt(15), response.Records[0].Revenue)
+}
@@ -3,8 +3,19 @@ package service
 import (
 	"errors"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/mantlenetworkio/lithosphere/database/business"
+	"github.com/mantlenetworkio/lithosphere/event/op-bindings/predeploys"
 )
 
+// feeVaults are the fee vault predeploys, keyed by the name accepted by the API
+var feeVaults = map[string]common.Address{
+	"sequencer": predeploys.SequencerFeeVaultAddr,
+	"base":      predeploys.BaseFeeVaultAddr,
+	"l1":        predeploys.L1FeeVaultAddr,
+}
+
 type Validator struct{}
 
 func (v *Validator) ParseValidateAddress(addr string) (common.Address, error) {
@@ -21,6 +32,40 @@ func (v *Validator) ParseValidateAddress(addr string) (common.Address, error) {
 	return parsedAddr, nil
 }
 
+func (v *Validator) ParseValidateHash(hash string) (common.Hash, error) {
+	hashBytes, err := hexutil.Decode(hash)
+	if err != nil || len(hashBytes) != common.HashLength {
+		return common.Hash{}, errors.New("hash must be represented as a 32 bytes hexadecimal string")
+	}
+	return common.BytesToHash(hashBytes), nil
+}
+
+func (v *Validator) ParseValidateFeeVault(vault string) (common.Address, error) {
+	vaultAddr, ok := feeVaults[vault]
+	if !ok {
+		return common.Address{}, errors.New("vault must be one of sequencer, base or l1")
+	}
+	return vaultAddr, nil
+}
+
+func (v *Validator) ValidateFeeVaultPeriod(period string) (string, error) {
+	switch period {
+	case "", business.FeeVaultPeriodMonth:
+		return business.FeeVaultPeriodMonth, nil
+	case business.FeeVaultPeriodDay:
+		return business.FeeVaultPeriodDay, nil
+	}
+	return "", errors.New("period must be day or month")
+}
+
+func (v *Validator) ValidateIncidentKind(kind string) (string, error) {
+	switch kind {
+	case "", business.IncidentProvenWithoutInitiation, business.IncidentFinalizedWithoutInitiation, business.IncidentAmountMismatch:
+		return kind, nil
+	}
+	return "", errors.New("kind must be one of proven_without_initiation, finalized_without_initiation or amount_mismatch")
+}
+
 func (v *Validator) ValidatePage(page int) int {
 	var validPage int
 	if page <= 0 {
@@ -0,0 +1,87 @@
+package business
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	common3 "github.com/mantlenetworkio/lithosphere/common"
+)
+
+const (
+	// depositConfirmationBatchSize bounds the deposit receipts fetched from L2 on every run
+	depositConfirmationBatchSize = 50
+	// maxSequencerDrift is the max_sequencer_drift of the rollup, in seconds. An L2 block is at most that much
+	// ahead of its L1 origin.
+	maxSequencerDrift = 600
+)
+
+// confirmDeposits fetches the receipt of the deposit transactions derived from the TransactionDeposited events,
+// oldest deposit first. Deposits are included on L2 in order, the first one without a receipt ends the run unless
+// the indexed L2 chain moved past the L1 origin of its deposit: it should have been included by then, it is flagged
+// as missing and skipped. Only the receipts of the L2 blocks already indexed are stored, so that they are rolled
+// back along with a reorg.
+func (bp *BusinessProcessor) confirmDeposits() error {
+	l2Header, err := bp.db.Blocks.L2LatestBlockHeader()
+	if err != nil {
+		return err
+	} else if l2Header == nil {
+		return nil
+	}
+	deposits, err := bp.db.L1ToL2.UnconfirmedL1ToL2Deposits(depositConfirmationBatchSize)
+	if err != nil {
+		return err
+	}
+	confirmed, reverted, missing := 0, 0, 0
+	for i := range deposits {
+		deposit := deposits[i]
+		receipt, err := bp.l2Client.TxReceiptDetailByHash(*deposit.L2DepositTransactionHash)
+		if errors.Is(err, ethereum.NotFound) {
+			if !pastL1Origin(l2Header.Timestamp, uint64(deposit.Timestamp)) {
+				break
+			}
+			bp.log.Error("deposit transaction not found on L2 past the L1 origin of its deposit", "l1_tx_hash", deposit.L1TransactionHash,
+				"l2_tx_hash", deposit.L2DepositTransactionHash, "l1_block_number", deposit.L1BlockNumber, "l2_block_number", l2Header.Number)
+			if err := bp.db.L1ToL2.MarkL1ToL2DepositMissing(deposit.GUID); err != nil {
+				return err
+			}
+			missing++
+			continue
+		} else if err != nil {
+			return fmt.Errorf("unable to fetch the receipt of deposit tx %s: %w", deposit.L2DepositTransactionHash, err)
+		}
+		if receipt.BlockNumber.Cmp(l2Header.Number) > 0 {
+			break
+		}
+		deposit.L2DepositBlockNumber = receipt.BlockNumber
+		deposit.L2DepositGasUsed = new(big.Int).SetUint64(receipt.GasUsed)
+		deposit.L2DepositStatus = common3.DepositSucceeded
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			deposit.L2DepositStatus = common3.DepositReverted
+			reverted++
+			bp.log.Warn("deposit transaction reverted on L2", "l1_tx_hash", deposit.L1TransactionHash, "l2_tx_hash", deposit.L2DepositTransactionHash,
+				"l2_block_number", receipt.BlockNumber)
+		}
+		if err := bp.rejectedTransitions(bp.db.L1ToL2.ConfirmL1ToL2Deposit(deposit)); err != nil {
+			return err
+		}
+		confirmed++
+	}
+	if confirmed > 0 {
+		bp.log.Info("confirmed deposit transactions", "size", confirmed, "reverted", reverted)
+		bp.metrics.RecordConfirmedDeposits(confirmed, reverted)
+	}
+	if missing > 0 {
+		bp.metrics.RecordMissingDeposits(missing)
+	}
+	return nil
+}
+
+// pastL1Origin reports whether the L1 origin of the L2 block is past the L1 block of the deposit, the L2 block
+// being further ahead of the deposit than the sequencer can drift from its origin
+func pastL1Origin(l2Timestamp, l1Timestamp uint64) bool {
+	return l2Timestamp > l1Timestamp+maxSequencerDrift
+}
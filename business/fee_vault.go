@@ -0,0 +1,75 @@
+package business
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/mantlenetworkio/lithosphere/database"
+	"github.com/mantlenetworkio/lithosphere/database/business"
+	common2 "github.com/mantlenetworkio/lithosphere/database/common"
+)
+
+// feeVaultSampleInterval is the interval the balances of the fee vaults are sampled at
+const feeVaultSampleInterval = time.Minute
+
+// sampleFeeVaultBalances samples the balances of the fee vaults at the latest indexed L2 header, unless they
+// have been sampled there already. A failed sample is retried at the next interval, against the header
+// indexed by then. The balances aren't backfilled, the revenue API reports the first sampled block.
+func (bp *BusinessProcessor) sampleFeeVaultBalances() error {
+	header, err := bp.db.Blocks.L2LatestBlockHeader()
+	if err != nil {
+		return err
+	} else if header == nil {
+		return nil
+	}
+	latest, err := bp.db.FeeVault.FeeVaultLatestBalances()
+	if err != nil {
+		return err
+	}
+	for _, balance := range latest {
+		if balance.L2BlockNumber.Cmp(header.Number) >= 0 {
+			return nil
+		}
+	}
+
+	balances := make([]business.FeeVaultBalance, 0, len(bp.feeVaults))
+	for _, vault := range bp.feeVaults {
+		balance, err := bp.l2Client.GetBalanceByBlockNumber(vault.String(), header.Number)
+		if err != nil {
+			bp.metrics.RecordFeeVaultSampleFailure()
+			return fmt.Errorf("unable to fetch the balance of fee vault %s at %s: %w", vault, header.Number, err)
+		}
+		balances = append(balances, business.FeeVaultBalance{
+			VaultAddress:  vault,
+			L2BlockNumber: header.Number,
+			Balance:       balance,
+			Timestamp:     int64(header.Timestamp),
+		})
+	}
+
+	stored := false
+	if err := bp.db.Transaction(func(tx *database.DB) error {
+		// the header may have been rolled back by a reorg while the balances were fetched
+		filter := common2.BlockHeader{Hash: header.Hash}
+		lockedHeader, err := tx.Blocks.L2BlockHeaderWithScope(func(db *gorm.DB) *gorm.DB {
+			return db.Clauses(clause.Locking{Strength: "SHARE"}).Where(&filter)
+		})
+		if err != nil {
+			return err
+		} else if lockedHeader == nil {
+			bp.log.Warn("fee vault balances sampled at a reorged header, skipping sample", "number", header.Number, "hash", header.Hash)
+			return nil
+		}
+		stored = true
+		return tx.FeeVault.StoreFeeVaultBalances(balances)
+	}); err != nil {
+		return err
+	}
+	if stored {
+		bp.metrics.RecordFeeVaultSample(header.Number)
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package business
+
+import (
+	"math/big"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/mantlenetworkio/lithosphere/metrics"
+)
+
+var (
+	MetricsNamespace string = "op_indexer_business"
+)
+
+type Metricer interface {
+	RecordVerifiedOutputRoots(size int)
+	RecordOutputRootMismatch(outputIndex *big.Int)
+	RecordWithdrawalIncidents(kind string, size int)
+	RecordOpenWithdrawalIncidents(size int64)
+	RecordConfirmedDeposits(size int, reverted int)
+	RecordMissingDeposits(size int)
+	RecordFeeVaultSample(l2BlockNumber *big.Int)
+	RecordFeeVaultSampleFailure()
+	RecordRejectedTransition(bridge string, from, to int64)
+}
+
+type businessMetrics struct {
+	verifiedOutputRoots    prometheus.Counter
+	outputRootMismatches   prometheus.Counter
+	latestMismatchedOutput prometheus.Gauge
+
+	withdrawalIncidents     *prometheus.CounterVec
+	openWithdrawalIncidents prometheus.Gauge
+
+	confirmedDeposits *prometheus.CounterVec
+	missingDeposits   prometheus.Counter
+
+	feeVaultSampleHeight   prometheus.Gauge
+	feeVaultSampleFailures prometheus.Counter
+
+	rejectedTransitions *prometheus.CounterVec
+}
+
+func NewMetrics(registry *prometheus.Registry) Metricer {
+	factory := metrics.With(registry)
+	return &businessMetrics{
+		verifiedOutputRoots: factory.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "verified_output_roots_total",
+			Help:      "number of output roots matching the root recomputed from L2",
+		}),
+		outputRootMismatches: factory.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "output_root_mismatches_total",
+			Help:      "number of proposed output roots not matching the root recomputed from L2. Any increase is critical, the proposer misbehaves or the L2 node diverged",
+		}),
+		latestMismatchedOutput: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "output_root_mismatch_index",
+			Help:      "the index of the latest output whose root did not match the root recomputed from L2",
+		}),
+		withdrawalIncidents: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "withdrawal_incidents_total",
+			Help:      "number of L1 proofs and finalizations not matching their L2 withdrawal. Any increase is critical, a withdrawal may have been forged",
+		}, []string{
+			"kind",
+		}),
+		openWithdrawalIncidents: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "open_withdrawal_incidents",
+			Help:      "number of withdrawal incidents not resolved",
+		}),
+		confirmedDeposits: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "confirmed_deposits_total",
+			Help:      "number of deposit transactions whose receipt was found on L2",
+		}, []string{
+			"status",
+		}),
+		missingDeposits: factory.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "missing_deposits_total",
+			Help:      "number of deposit transactions not found on L2 although the L2 chain moved past the L1 origin of their deposit",
+		}),
+		feeVaultSampleHeight: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "fee_vault_sample_height",
+			Help:      "the L2 block number the fee vault balances were last sampled at",
+		}),
+		feeVaultSampleFailures: factory.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "fee_vault_sample_failures_total",
+			Help:      "number of fee vault balance samples that could not be fetched from L2",
+		}),
+		rejectedTransitions: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "rejected_bridge_status_transitions_total",
+			Help:      "number of deposit and withdrawal status transitions not allowed by their lifecycle, the bridge is left in its status",
+		}, []string{
+			"bridge",
+			"from",
+			"to",
+		}),
+	}
+}
+
+func (m *businessMetrics) RecordVerifiedOutputRoots(size int) {
+	m.verifiedOutputRoots.Add(float64(size))
+}
+
+func (m *businessMetrics) RecordOutputRootMismatch(outputIndex *big.Int) {
+	m.outputRootMismatches.Inc()
+	m.latestMismatchedOutput.Set(float64(outputIndex.Uint64()))
+}
+
+func (m *businessMetrics) RecordWithdrawalIncidents(kind string, size int) {
+	m.withdrawalIncidents.WithLabelValues(kind).Add(float64(size))
+}
+
+func (m *businessMetrics) RecordOpenWithdrawalIncidents(size int64) {
+	m.openWithdrawalIncidents.Set(float64(size))
+}
+
+func (m *businessMetrics) RecordConfirmedDeposits(size int, reverted int) {
+	m.confirmedDeposits.WithLabelValues("succeeded").Add(float64(size - reverted))
+	m.confirmedDeposits.WithLabelValues("reverted").Add(float64(reverted))
+}
+
+func (m *businessMetrics) RecordMissingDeposits(size int) {
+	m.missingDeposits.Add(float64(size))
+}
+
+func (m *businessMetrics) RecordFeeVaultSample(l2BlockNumber *big.Int) {
+	m.feeVaultSampleHeight.Set(float64(l2BlockNumber.Uint64()))
+}
+
+func (m *businessMetrics) RecordFeeVaultSampleFailure() {
+	m.feeVaultSampleFailures.Inc()
+}
+
+func (m *businessMetrics) RecordRejectedTransition(bridge string, from, to int64) {
+	m.rejectedTransitions.WithLabelValues(bridge, strconv.FormatInt(from, 10), strconv.FormatInt(to, 10)).Inc()
+}
@@ -3,6 +3,7 @@ package business
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"math/big"
@@ -10,10 +11,13 @@ import (
 	"strings"
 	"time"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
 
 	"github.com/mantlenetworkio/lithosphere/business/mantle_da"
+	common3 "github.com/mantlenetworkio/lithosphere/common"
 	"github.com/mantlenetworkio/lithosphere/common/tasks"
 	"github.com/mantlenetworkio/lithosphere/config"
 	"github.com/mantlenetworkio/lithosphere/database"
@@ -21,8 +25,17 @@ import (
 	"github.com/mantlenetworkio/lithosphere/database/event"
 	"github.com/mantlenetworkio/lithosphere/database/exporter"
 	"github.com/mantlenetworkio/lithosphere/synchronizer/node"
+	"github.com/mantlenetworkio/lithosphere/synchronizer/retry"
 )
 
+// BridgeNotifier notifies of every run of the bridge processor stages
+type BridgeNotifier interface {
+	Subscribe() <-chan struct{}
+}
+
+// processFallbackInterval is the interval the bridge dependent tasks run at when no bridge notification arrives
+const processFallbackInterval = 5 * time.Second
+
 type BusinessProcessor struct {
 	log                      log.Logger
 	db                       *database.DB
@@ -31,16 +44,25 @@ type BusinessProcessor struct {
 	tasks                    tasks.Group
 	l1Client                 node.EthClient
 	l2Client                 node.EthClient
+	metrics                  Metricer
 	mantleDA                 *mantle_da.MantleDataStore
 	startDataStoreId         uint32
-	fraudProofWindows        uint64
+	optimismPortal           common.Address
+	finalizationPeriod       uint64
 	L1AccountCheckingAddress string
 	L2AccountCheckingAddress string
 	L1StandardBridge         common.Address
+	L2ToL1MessagePasser      common.Address
+	feeVaults                []common.Address
 	tokenListUrl             string
+	withdrawalGracePeriod    time.Duration
+	withdrawalProposalLag    time.Duration
+	withdrawalStartHeight    *big.Int
+	bridgeNotifier           BridgeNotifier
 }
 
-func NewBusinessProcessor(logger log.Logger, db *database.DB, l1Client node.EthClient, l2Client node.EthClient, da *mantle_da.MantleDataStore, cfg config.Config, shutdown context.CancelCauseFunc) *BusinessProcessor {
+func NewBusinessProcessor(logger log.Logger, db *database.DB, metrics Metricer, l1Client node.EthClient, l2Client node.EthClient, da *mantle_da.MantleDataStore, cfg config.Config,
+	bridgeNotifier BridgeNotifier, shutdown context.CancelCauseFunc) *BusinessProcessor {
 
 	resCtx, resCancel := context.WithCancel(context.Background())
 	businessProcessor := BusinessProcessor{
@@ -50,13 +72,20 @@ func NewBusinessProcessor(logger log.Logger, db *database.DB, l1Client node.EthC
 		resourceCancel:           resCancel,
 		l1Client:                 l1Client,
 		l2Client:                 l2Client,
+		metrics:                  metrics,
 		mantleDA:                 da,
-		fraudProofWindows:        cfg.FraudProofWindows,
+		optimismPortal:           cfg.Chain.L1Contracts.OptimismPortalProxy,
 		startDataStoreId:         cfg.StartDataStoreId,
 		L1AccountCheckingAddress: cfg.CheckingAddress.L1AccountCheckingAddress,
 		L2AccountCheckingAddress: cfg.CheckingAddress.L2AccountCheckingAddress,
 		L1StandardBridge:         cfg.Chain.L1Contracts.L1StandardBridgeProxy,
+		L2ToL1MessagePasser:      cfg.Chain.L2Contracts.L2ToL1MessagePasser,
+		feeVaults:                []common.Address{cfg.Chain.L2Contracts.SequencerFeeVault, cfg.Chain.L2Contracts.BaseFeeVault, cfg.Chain.L2Contracts.L1FeeVault},
 		tokenListUrl:             cfg.TokenListUrl,
+		withdrawalGracePeriod:    cfg.WithdrawalMonitorGracePeriod,
+		withdrawalProposalLag:    cfg.WithdrawalMonitorProposalLag,
+		withdrawalStartHeight:    big.NewInt(int64(max(cfg.Chain.L2StartingHeight, cfg.Chain.L2BedrockStartingHeight))),
+		bridgeNotifier:           bridgeNotifier,
 		tasks: tasks.Group{HandleCrit: func(err error) {
 			shutdown(fmt.Errorf("critical error in business processor: %w", err))
 		}},
@@ -66,64 +95,124 @@ func NewBusinessProcessor(logger log.Logger, db *database.DB, l1Client node.EthC
 
 func (bp *BusinessProcessor) Start() error {
 	bp.log.Info("starting business processor...")
-	tickerRun := time.NewTicker(time.Second * 5)
+	// the challenge deadlines of the withdrawals are set from the finalization period of the L2OutputOracle
+	retryStrategy := &retry.ExponentialStrategy{Min: 1000, Max: 20_000, MaxJitter: 250}
+	finalizationPeriod, err := retry.Do[uint64](bp.resourceCtx, 10, retryStrategy, func() (uint64, error) {
+		finalizationPeriod, err := bp.l1Client.FinalizationPeriodSeconds(bp.optimismPortal)
+		if err != nil {
+			bp.log.Warn("unable to read the finalization period, retrying", "err", err)
+		}
+		return finalizationPeriod, err
+	})
+	if err != nil {
+		return fmt.Errorf("unable to read the finalization period: %w", err)
+	}
+	bp.finalizationPeriod = finalizationPeriod
+	deadlines, err := bp.db.L2ToL1.SetChallengeDeadlines(finalizationPeriod)
+	if err != nil {
+		return fmt.Errorf("unable to set the challenge deadlines of the proven withdrawals: %w", err)
+	}
+	bp.log.Info("read finalization period", "seconds", finalizationPeriod, "deadlines_set", deadlines)
+
+	// the rollup and bridge statuses are derived from the bridge processor output, they are
+	// updated as soon as it has run
+	bridgeUpdates := func() <-chan struct{} {
+		if bp.bridgeNotifier == nil {
+			return nil
+		}
+		return bp.bridgeNotifier.Subscribe()
+	}
+
+	rollupUpdates := bridgeUpdates()
 	bp.tasks.Go(func() error {
-		for range tickerRun.C {
-			err := bp.onRollup()
-			if err != nil {
+		tasks.Loop(bp.resourceCtx, processFallbackInterval, rollupUpdates, func() {
+			if err := bp.onRollup(); err != nil {
 				bp.log.Error("business processor onRollup", "error", err)
 			}
-		}
+		})
 		return nil
 	})
 
-	ticker := time.NewTicker(time.Second)
+	outputUpdates := bridgeUpdates()
 	bp.tasks.Go(func() error {
-		for range ticker.C {
-			if err := bp.db.L2ToL1.UpdateTimeLeft(); err != nil {
-				bp.log.Error("business processor UpdateTimeLeft", "error", err)
+		tasks.Loop(bp.resourceCtx, processFallbackInterval, outputUpdates, func() {
+			if err := bp.verifyOutputRoots(); err != nil {
+				bp.log.Error("business processor verifyOutputRoots", "error", err)
 			}
-		}
+		})
+		return nil
+	})
+
+	incidentUpdates := bridgeUpdates()
+	bp.tasks.Go(func() error {
+		tasks.Loop(bp.resourceCtx, processFallbackInterval, incidentUpdates, func() {
+			if err := bp.monitorWithdrawals(); err != nil {
+				bp.log.Error("business processor monitorWithdrawals", "error", err)
+			}
+		})
 		return nil
 	})
 
-	tickerL1ToL2 := time.NewTicker(time.Second * 5)
+	receiptUpdates := bridgeUpdates()
 	bp.tasks.Go(func() error {
-		for range tickerL1ToL2.C {
+		tasks.Loop(bp.resourceCtx, processFallbackInterval, receiptUpdates, func() {
+			if err := bp.confirmDeposits(); err != nil {
+				bp.log.Error("business processor confirmDeposits", "error", err)
+			}
+		})
+		return nil
+	})
+
+	depositUpdates := bridgeUpdates()
+	bp.tasks.Go(func() error {
+		tasks.Loop(bp.resourceCtx, processFallbackInterval, depositUpdates, func() {
 			if err := bp.onDepositTxStatus(); err != nil {
 				bp.log.Error("business processor onDepositTxStatus", "error", err)
 			}
-		}
+		})
 		return nil
 	})
 
-	tickerL2ToL1 := time.NewTicker(time.Second * 5)
+	withdrawUpdates := bridgeUpdates()
 	bp.tasks.Go(func() error {
-		for range tickerL2ToL1.C {
+		tasks.Loop(bp.resourceCtx, processFallbackInterval, withdrawUpdates, func() {
 			if err := bp.onWithdrawTxStatus(); err != nil {
 				bp.log.Error("business processor onWithdrawTxStatus", "error", err)
 			}
-		}
+		})
 		return nil
 	})
 
-	tickerBridgeCheck := time.NewTicker(time.Hour * 6)
+	// sampled apart from the L2 synchronizer, so that a node unable to serve the balances doesn't hold back indexing
 	bp.tasks.Go(func() error {
-		for range tickerBridgeCheck.C {
+		tasks.Loop(bp.resourceCtx, feeVaultSampleInterval, nil, func() {
+			if err := bp.sampleFeeVaultBalances(); err != nil {
+				bp.log.Error("business processor sampleFeeVaultBalances", "error", err)
+			}
+		})
+		return nil
+	})
+
+	bp.tasks.Go(func() error {
+		tasks.Loop(bp.resourceCtx, time.Hour*6, nil, func() {
 			if err := bp.syncTokenBalance(); err != nil {
 				bp.log.Error("business processor syncTokenBalance", "error", err)
 			}
-		}
+		})
 		return nil
 	})
 
-	tokenListTicker := time.NewTicker(time.Minute * 1)
 	bp.tasks.Go(func() error {
-		for range tokenListTicker.C {
-			if err := bp.syncTokenList(); err != nil {
-				bp.log.Error(err.Error())
+		tasks.Loop(bp.resourceCtx, time.Minute*1, nil, func() {
+			if bp.tokenListUrl != "" {
+				if err := bp.syncTokenList(); err != nil {
+					bp.log.Error(err.Error())
+				}
 			}
-		}
+			if err := bp.syncTokenPairMetadata(); err != nil {
+				bp.log.Error("business processor syncTokenPairMetadata", "error", err)
+			}
+		})
 		return nil
 	})
 
@@ -140,6 +229,10 @@ func (bp *BusinessProcessor) onDepositTxStatus() error {
 		bp.log.Error("marked l2 to l1 finalized fail", "err", err)
 		return err
 	}
+	if err := bp.rejectedTransitions(bp.db.L1ToL2.UpdateL1ToL2RelayStatus()); err != nil {
+		bp.log.Error("update l1 to l2 relay status fail", "err", err)
+		return err
+	}
 	return nil
 }
 
@@ -148,14 +241,27 @@ func (bp *BusinessProcessor) onWithdrawTxStatus() error {
 		bp.log.Error("sync l2 to l1 state root fail", "err", err)
 		return err
 	}
+	if err := bp.invalidateDeletedOutputProofs(); err != nil {
+		bp.log.Error("invalidate deleted output proofs fail", "err", err)
+		return err
+	}
 	if err := bp.markedL2ToL1Proven(); err != nil {
 		bp.log.Error("marked l2 to l1 prove fail", "err", err)
 		return err
 	}
+	// the NFT withdrawals are proven by the bridge processor, which doesn't know the finalization period
+	if _, err := bp.db.ERC721Bridge.SetERC721ChallengeDeadlines(bp.finalizationPeriod); err != nil {
+		bp.log.Error("set erc721 challenge deadlines fail", "err", err)
+		return err
+	}
 	if err := bp.markedL2ToL1Finalized(); err != nil {
 		bp.log.Error("marked l2 to l1 finalized fail", "err", err)
 		return err
 	}
+	if err := bp.rejectedTransitions(bp.db.L2ToL1.UpdateL2ToL1RelayStatus()); err != nil {
+		bp.log.Error("update l2 to l1 relay status fail", "err", err)
+		return err
+	}
 	return nil
 }
 
@@ -216,8 +322,14 @@ func (bp *BusinessProcessor) syncL2ToL1StateRoot() error {
 	if blockNumber == 0 {
 		return nil
 	}
-	bp.log.Info("get state root l2 block number success", "l2BlockNumber", blockNumber, "fraudProofWindows", bp.fraudProofWindows)
-	err = bp.db.L2ToL1.UpdateReadyForProvedStatus(blockNumber, bp.fraudProofWindows)
+	bp.log.Info("get state root l2 block number success", "l2BlockNumber", blockNumber)
+	// the outputs deleted by the challenger no longer cover their withdrawals
+	err = bp.rejectedTransitions(bp.db.L2ToL1.RollbackL2ToL1ReadyForProved(blockNumber))
+	if err != nil {
+		bp.log.Error(err.Error())
+		return err
+	}
+	err = bp.rejectedTransitions(bp.db.L2ToL1.UpdateReadyForProvedStatus(blockNumber))
 	if err != nil {
 		bp.log.Error(err.Error())
 		return err
@@ -226,6 +338,103 @@ func (bp *BusinessProcessor) syncL2ToL1StateRoot() error {
 	return nil
 }
 
+// invalidateDeletedOutputProofs flags the proofs made against the outputs deleted by the challenger, their
+// withdrawals have to be proven again against the outputs proposed in their place
+func (bp *BusinessProcessor) invalidateDeletedOutputProofs() error {
+	blockNumber, err := bp.db.StateRoots.GetLatestStateRootL2BlockNumber()
+	if err != nil {
+		return err
+	}
+	return bp.db.Transaction(func(tx *database.DB) error {
+		proofs, err := tx.WithdrawProven.InvalidateWithdrawProven()
+		if err != nil {
+			return err
+		}
+		withdrawals, err := tx.L2ToL1.InvalidateL2ToL1Proven(blockNumber)
+		if err := bp.rejectedTransitions(err); err != nil {
+			return err
+		}
+		if proofs > 0 || withdrawals > 0 {
+			bp.log.Warn("invalidated proofs of deleted outputs", "proofs", proofs, "withdrawals", withdrawals)
+		}
+		return nil
+	})
+}
+
+// rejectedTransitions records the bridge status transitions rejected by an update, which carries on past them
+func (bp *BusinessProcessor) rejectedTransitions(err error) error {
+	return business.HandleRejectedTransitions(err, func(transition business.RejectedTransition) {
+		bp.metrics.RecordRejectedTransition(transition.Bridge, transition.From, transition.To)
+	})
+}
+
+// outputVerificationBatchSize bounds the outputs verified against L2 on every run
+const outputVerificationBatchSize = 20
+
+// verifyOutputRoots recomputes the root of the proposed outputs from the state root and block hash of their
+// L2 block and the storage root of the L2ToL1MessagePasser, latest output first. A mismatch means the
+// proposer committed to a state the L2 node does not agree with. Only the outputs up to the indexed L2 head
+// are verified, an output failing to be verified is retried on the next run.
+func (bp *BusinessProcessor) verifyOutputRoots() error {
+	l2Head, err := bp.db.Blocks.L2LatestBlockHeader()
+	if err != nil {
+		return err
+	} else if l2Head == nil {
+		return nil
+	}
+	stateRoots, err := bp.db.StateRoots.UnverifiedStateRoots(l2Head.Number, outputVerificationBatchSize)
+	if err != nil {
+		return err
+	}
+	verified := 0
+	for i := range stateRoots {
+		stateRoot := stateRoots[i]
+		if err := bp.verifyOutputRoot(&stateRoot); err != nil {
+			bp.log.Warn("unable to verify output root, retrying on the next run", "output_index", stateRoot.OutputIndex,
+				"l2_block_number", stateRoot.L2BlockNumber, "err", err)
+			continue
+		}
+		if stateRoot.VerificationStatus == common3.OutputRootMismatch {
+			bp.log.Error("proposed output root does not match L2", "output_index", stateRoot.OutputIndex, "l2_block_number", stateRoot.L2BlockNumber,
+				"proposed_output_root", stateRoot.OutputRoot, "computed_output_root", stateRoot.ComputedOutputRoot, "tx_hash", stateRoot.TransactionHash)
+			bp.metrics.RecordOutputRootMismatch(stateRoot.OutputIndex)
+		} else if stateRoot.VerificationStatus == common3.OutputRootVerified {
+			verified++
+		}
+	}
+	if verified > 0 {
+		bp.log.Info("verified output roots", "size", verified)
+		bp.metrics.RecordVerifiedOutputRoots(verified)
+	}
+	return nil
+}
+
+// verifyOutputRoot recomputes the root of the output and stores its verification status. The output is left
+// unverified when its L2 block is above the L2 node head.
+func (bp *BusinessProcessor) verifyOutputRoot(stateRoot *business.StateRoot) error {
+	header, err := bp.l2Client.BlockHeaderByNumber(stateRoot.L2BlockNumber)
+	if errors.Is(err, ethereum.NotFound) {
+		bp.log.Warn("output above the L2 node head, verification deferred", "output_index", stateRoot.OutputIndex, "l2_block_number", stateRoot.L2BlockNumber)
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("unable to fetch L2 header %s: %w", stateRoot.L2BlockNumber, err)
+	}
+	storageRoot, err := bp.l2Client.StorageHash(bp.L2ToL1MessagePasser, stateRoot.L2BlockNumber)
+	if err != nil {
+		return fmt.Errorf("unable to fetch the message passer storage root at L2 block %s: %w", stateRoot.L2BlockNumber, err)
+	}
+	l2StateRoot, l2BlockHash := header.Root, header.Hash()
+	// output root version zero
+	outputRoot := crypto.Keccak256Hash(common.Hash{}.Bytes(), l2StateRoot.Bytes(), storageRoot.Bytes(), l2BlockHash.Bytes())
+	stateRoot.L2StateRoot, stateRoot.MessagePasserStorageRoot, stateRoot.L2BlockHash, stateRoot.ComputedOutputRoot = &l2StateRoot, &storageRoot, &l2BlockHash, &outputRoot
+
+	stateRoot.VerificationStatus = common3.OutputRootVerified
+	if proposed := common.HexToHash(stateRoot.OutputRoot); outputRoot != proposed {
+		stateRoot.VerificationStatus = common3.OutputRootMismatch
+	}
+	return bp.db.StateRoots.UpdateStateRootVerification(*stateRoot)
+}
+
 func (bp *BusinessProcessor) syncStateRootStatus() error {
 	latestSafeBlockHeader, err := bp.l1Client.LatestSafeBlockHeader()
 	if err != nil {
@@ -264,6 +473,8 @@ func (bp *BusinessProcessor) markedL1ToL2Finalized() error {
 		l1l2Tx := business.L1ToL2{
 			L2TransactionHash: finalized.RelayTransactionHash,
 			L1BlockNumber:     finalized.BlockNumber,
+			L2BlockNumber:     finalized.BlockNumber,
+			MessageHash:       finalized.MessageHash,
 		}
 		withdrawTx, _ := bp.db.L1ToL2.L1ToL2TransactionDeposit(finalized.MessageHash)
 		if withdrawTx != nil {
@@ -273,7 +484,7 @@ func (bp *BusinessProcessor) markedL1ToL2Finalized() error {
 	}
 	if err := bp.db.Transaction(func(tx *database.DB) error {
 		if len(depositL2ToL1List) > 0 {
-			if err := bp.db.L1ToL2.MarkL1ToL2TransactionDepositFinalized(depositL2ToL1List); err != nil {
+			if err := bp.rejectedTransitions(bp.db.L1ToL2.MarkL1ToL2TransactionDepositFinalized(depositL2ToL1List)); err != nil {
 				bp.log.Error("Marked l2 to l1 transaction withdraw proven fail", "err", err)
 				return err
 			}
@@ -306,6 +517,8 @@ func (bp *BusinessProcessor) markedL2ToL1Proven() error {
 			WithdrawTransactionHash: provenTxn.WithdrawHash,
 			L1ProveTxHash:           provenTxn.ProvenTransactionHash,
 			L1BlockNumber:           provenTxn.BlockNumber,
+			L1ProvenTimestamp:       int64(provenTxn.Timestamp),
+			ChallengeDeadline:       int64(provenTxn.Timestamp + bp.finalizationPeriod),
 		}
 		withdrawTx, _ := bp.db.L2ToL1.L2ToL1TransactionWithdrawal(provenTxn.WithdrawHash)
 		if withdrawTx != nil {
@@ -320,7 +533,7 @@ func (bp *BusinessProcessor) markedL2ToL1Proven() error {
 	}
 	if err := bp.db.Transaction(func(tx *database.DB) error {
 		if len(withdrawL2ToL1List) > 0 {
-			if err := bp.db.L2ToL1.MarkL2ToL1TransactionWithdrawalProven(withdrawL2ToL1List); err != nil {
+			if err := bp.rejectedTransitions(bp.db.L2ToL1.MarkL2ToL1TransactionWithdrawalProven(withdrawL2ToL1List)); err != nil {
 				bp.log.Error("Marked l2 to l1 transaction withdraw proven fail", "err", err)
 				return err
 			}
@@ -331,7 +544,7 @@ func (bp *BusinessProcessor) markedL2ToL1Proven() error {
 			bp.log.Info("marked proven transaction success", "withdraw size", len(provenList), "marked size", len(needMarkWithdrawList))
 		}
 		if len(withdrawL2ToL1ListV0) > 0 {
-			if err := bp.db.L2ToL1.MarkL2ToL1TransactionWithdrawalProven(withdrawL2ToL1ListV0); err != nil {
+			if err := bp.rejectedTransitions(bp.db.L2ToL1.MarkL2ToL1TransactionWithdrawalProven(withdrawL2ToL1ListV0)); err != nil {
 				bp.log.Error("Marked l2 to l1 transaction withdraw proven fail", "err", err)
 				return err
 			}
@@ -381,7 +594,7 @@ func (bp *BusinessProcessor) markedL2ToL1Finalized() error {
 	}
 	if err := bp.db.Transaction(func(tx *database.DB) error {
 		if len(withdrawL2ToL1List) > 0 {
-			if err := bp.db.L2ToL1.MarkL2ToL1TransactionWithdrawalFinalized(withdrawL2ToL1List); err != nil {
+			if err := bp.rejectedTransitions(bp.db.L2ToL1.MarkL2ToL1TransactionWithdrawalFinalized(withdrawL2ToL1List)); err != nil {
 				bp.log.Error("Marked l2 to l1 transaction withdraw finalized fail", "err", err)
 				return err
 			}
@@ -392,7 +605,7 @@ func (bp *BusinessProcessor) markedL2ToL1Finalized() error {
 			bp.log.Info("marked finalized transaction success", "withdraw size", len(withdrawList), "marked size", len(needMarkWithdrawList))
 		}
 		if len(withdrawL2ToL1ListV0) > 0 {
-			if err := bp.db.L2ToL1.MarkL2ToL1TransactionWithdrawalFinalizedV0(withdrawL2ToL1ListV0); err != nil {
+			if err := bp.rejectedTransitions(bp.db.L2ToL1.MarkL2ToL1TransactionWithdrawalFinalizedV0(withdrawL2ToL1ListV0)); err != nil {
 				bp.log.Error("Marked l2 to l1 transaction withdraw proven fail", "err", err)
 				return err
 			}
@@ -673,3 +886,51 @@ func (bp *BusinessProcessor) syncTokenList() error {
 	return nil
 
 }
+
+// tokenPairMetadataBatchSize bounds the token pairs whose metadata is read on every tick
+const tokenPairMetadataBatchSize = 50
+
+// tokenPairMetadataAttempts is the number of times the metadata of a token pair is read before giving up on it
+const tokenPairMetadataAttempts = 5
+
+// syncTokenPairMetadata reads the name, symbol and decimals of the token pairs registered from the
+// OptimismMintableERC20Factory events, on L1 for the bridged token and on L2 for the minted one. A pair whose
+// metadata can't be read is retried on the next ticks, after the other pairs, up to tokenPairMetadataAttempts times.
+func (bp *BusinessProcessor) syncTokenPairMetadata() error {
+	pairs, err := bp.db.TokenPair.TokenPairsWithoutMetadata(tokenPairMetadataBatchSize, tokenPairMetadataAttempts)
+	if err != nil {
+		return err
+	}
+	for _, pair := range pairs {
+		if err := bp.readTokenPairMetadata(&pair); err != nil {
+			bp.log.Warn("unable to read token pair metadata", "l1_token", pair.L1TokenAddress, "l2_token", pair.L2TokenAddress,
+				"attempt", pair.MetadataAttempts+1, "err", err)
+			if pair.MetadataAttempts+1 >= tokenPairMetadataAttempts {
+				bp.log.Error("giving up on token pair metadata", "l1_token", pair.L1TokenAddress, "l2_token", pair.L2TokenAddress)
+			}
+			if err := bp.db.TokenPair.MarkTokenPairMetadataFailed(pair.L2TokenAddress); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := bp.db.TokenPair.UpdateTokenPairMetadata(pair); err != nil {
+			return err
+		}
+		bp.log.Info("token pair registered", "l1_token", pair.L1TokenAddress, "l2_token", pair.L2TokenAddress, "symbol", pair.L2Symbol)
+	}
+	return nil
+}
+
+func (bp *BusinessProcessor) readTokenPairMetadata(pair *business.TokenPair) error {
+	l1Metadata, err := bp.l1Client.GetERC20Metadata(pair.L1TokenAddress)
+	if err != nil {
+		return fmt.Errorf("unable to read l1 token %s metadata: %w", pair.L1TokenAddress, err)
+	}
+	l2Metadata, err := bp.l2Client.GetERC20Metadata(pair.L2TokenAddress)
+	if err != nil {
+		return fmt.Errorf("unable to read l2 token %s metadata: %w", pair.L2TokenAddress, err)
+	}
+	pair.L1Name, pair.L1Symbol, pair.L1Decimals = l1Metadata.Name, l1Metadata.Symbol, l1Metadata.Decimals
+	pair.L2Name, pair.L2Symbol, pair.L2Decimals = l2Metadata.Name, l2Metadata.Symbol, l2Metadata.Decimals
+	return nil
+}
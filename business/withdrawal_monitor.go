@@ -0,0 +1,100 @@
+package business
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	common2 "github.com/mantlenetworkio/lithosphere/database/common"
+)
+
+// monitorWithdrawals reports the proofs and finalizations on L1 without any withdrawal initiated on L2, and the
+// finalizations releasing other amounts than the ones withdrawn. A withdrawal is proven or finalized after
+// it has been initiated, an L1 event is only reported once the L2 withdrawals have been indexed past it by the
+// grace period, so that the lag of the L2 indexing is not mistaken for a forgery. The withdrawals initiated before the
+// starting height or before bedrock are not indexed, neither are the L1 events which may refer to them reported: the
+// proofs until the outputs past the first indexed L2 header may have been proposed, and the finalizations until the
+// challenge period of these proofs has elapsed. The older withdrawals proven even later are still reported.
+func (bp *BusinessProcessor) monitorWithdrawals() error {
+	cursor, err := bp.db.SyncCursors.SyncCursor(common2.L2BridgeInitiatedCursor)
+	if err != nil {
+		return err
+	} else if cursor == nil {
+		return nil
+	}
+	l2Header, err := bp.db.Blocks.L2BlockHeader(cursor.BlockHash)
+	if err != nil {
+		return err
+	} else if l2Header == nil {
+		return nil
+	}
+	firstL2Header, err := bp.db.Blocks.L2BlockHeaderWithScope(func(db *gorm.DB) *gorm.DB {
+		return db.Where("number >= ?", bp.withdrawalStartHeight).Order("number ASC")
+	})
+	if err != nil {
+		return err
+	}
+	window, ok := incidentWindow(firstL2Header, l2Header, uint64(bp.withdrawalGracePeriod/time.Second),
+		uint64(bp.withdrawalProposalLag/time.Second), bp.finalizationPeriod)
+	if !ok {
+		return nil
+	}
+
+	incidents, err := bp.db.WithdrawalIncident.DetectWithdrawalIncidents(window.ProvenFrom, window.FinalizedFrom, window.To)
+	if err != nil {
+		return err
+	}
+	if len(incidents) > 0 {
+		detectedAt := time.Now().Unix()
+		incidentsByKind := make(map[string]int)
+		for i := range incidents {
+			incidents[i].DetectedAt = detectedAt
+			incidentsByKind[incidents[i].Kind]++
+			bp.log.Error("withdrawal incident detected", "kind", incidents[i].Kind, "withdraw_hash", incidents[i].WithdrawHash,
+				"l1_tx_hash", incidents[i].L1TransactionHash, "l1_block_number", incidents[i].L1BlockNumber)
+		}
+		if err := bp.db.WithdrawalIncident.StoreWithdrawalIncidents(incidents); err != nil {
+			return err
+		}
+		for kind, size := range incidentsByKind {
+			bp.metrics.RecordWithdrawalIncidents(kind, size)
+		}
+	}
+
+	resolved, err := bp.db.WithdrawalIncident.ResolveWithdrawalIncidents()
+	if err != nil {
+		return err
+	} else if resolved > 0 {
+		bp.log.Warn("withdrawal incidents resolved, their L2 withdrawals were indexed after the grace period", "size", resolved)
+	}
+	open, err := bp.db.WithdrawalIncident.OpenWithdrawalIncidentsCount()
+	if err != nil {
+		return err
+	}
+	bp.metrics.RecordOpenWithdrawalIncidents(open)
+	return nil
+}
+
+// withdrawalIncidentWindow holds the L1 timestamps the proofs and finalizations without initiation are detected within
+type withdrawalIncidentWindow struct {
+	ProvenFrom    uint64
+	FinalizedFrom uint64
+	To            uint64
+}
+
+// incidentWindow returns the L1 timestamps the withdrawals without initiation are detected within, up to the L2
+// withdrawals indexed past by the grace period. A withdrawal initiated before the first indexed L2 header may be
+// proven as soon as the output covering it is proposed, so the proofs are detected from the first header offset by
+// the proposal lag, and the finalizations from the end of the challenge period of these proofs. ok is false when
+// neither window is open.
+func incidentWindow(firstL2Header, l2Header *common2.L2BlockHeader, gracePeriod, proposalLag, finalizationPeriod uint64) (window withdrawalIncidentWindow, ok bool) {
+	if firstL2Header == nil || l2Header == nil || l2Header.Timestamp <= gracePeriod {
+		return withdrawalIncidentWindow{}, false
+	}
+	window = withdrawalIncidentWindow{
+		ProvenFrom:    firstL2Header.Timestamp + proposalLag,
+		FinalizedFrom: firstL2Header.Timestamp + proposalLag + finalizationPeriod,
+		To:            l2Header.Timestamp - gracePeriod,
+	}
+	return window, window.ProvenFrom <= window.To
+}
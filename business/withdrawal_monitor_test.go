@@ -0,0 +1,57 @@
+package business
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	common2 "github.com/mantlenetworkio/lithosphere/database/common"
+)
+
+func TestIncidentWindow(t *testing.T) {
+	header := func(timestamp uint64) *common2.L2BlockHeader {
+		return &common2.L2BlockHeader{BlockHeader: common2.BlockHeader{Timestamp: timestamp}}
+	}
+
+	window, ok := incidentWindow(header(1000), header(500_000), 600, 3600, 86400)
+	require.True(t, ok)
+	require.Equal(t, withdrawalIncidentWindow{ProvenFrom: 4600, FinalizedFrom: 91000, To: 499_400}, window)
+
+	// the L2 withdrawals haven't been indexed past the proposal lag by the grace period yet
+	_, ok = incidentWindow(header(1000), header(5000), 600, 3600, 86400)
+	require.False(t, ok)
+	_, ok = incidentWindow(header(0), header(600), 600, 0, 0)
+	require.False(t, ok)
+
+	// nothing indexed from the starting height yet
+	_, ok = incidentWindow(nil, header(5000), 600, 3600, 86400)
+	require.False(t, ok)
+}
+
+func TestIncidentWindowBoundary(t *testing.T) {
+	const (
+		gracePeriod        = 600
+		proposalLag        = 3600
+		finalizationPeriod = 86400
+		firstL2Timestamp   = 1_000_000
+	)
+	window, ok := incidentWindow(&common2.L2BlockHeader{BlockHeader: common2.BlockHeader{Timestamp: firstL2Timestamp}},
+		&common2.L2BlockHeader{BlockHeader: common2.BlockHeader{Timestamp: firstL2Timestamp + 10*finalizationPeriod}},
+		gracePeriod, proposalLag, finalizationPeriod)
+	require.True(t, ok)
+	detected := func(from, timestamp uint64) bool {
+		return timestamp >= from && timestamp <= window.To
+	}
+
+	// a withdrawal initiated just before the first indexed L2 header, which is never indexed, is proven once the
+	// output covering it is proposed and finalized once the challenge period has elapsed
+	initiated := uint64(firstL2Timestamp - 1)
+	proven := initiated + proposalLag
+	finalized := proven + finalizationPeriod
+	require.False(t, detected(window.ProvenFrom, proven))
+	require.False(t, detected(window.FinalizedFrom, finalized))
+
+	// the proofs and finalizations which can't refer to a withdrawal initiated before the first header are detected
+	require.True(t, detected(window.ProvenFrom, firstL2Timestamp+proposalLag))
+	require.True(t, detected(window.FinalizedFrom, firstL2Timestamp+proposalLag+finalizationPeriod))
+}
@@ -19,6 +19,12 @@ apiRouter.Get(fmt.Sprintf(DataStoreByIDPath+idParam), h.DataStoreByIdHandler)
 apiRouter.Get(fmt.Sprintf(DataStoreTxByIDPath+idParam), h.DataStoreBlockByIDHandler)
 apiRouter.Get(fmt.Sprintf(StateRootListPath), h.StateRootListHandler)
 apiRouter.Get(fmt.Sprintf(StateRootByIndexPath+indexParam), h.StateRootByIndexHandler)
+apiRouter.Get(fmt.Sprintf(ERC721DepositsPath), h.ERC721DepositListHandler)
+apiRouter.Get(fmt.Sprintf(ERC721WithdrawalsPath), h.ERC721WithdrawalListHandler)
+apiRouter.Get(fmt.Sprintf(ERC721ByMessageHashPath+hashParam), h.ERC721BridgeByMessageHashHandler)
+apiRouter.Get(fmt.Sprintf(SystemConfigUpdatesPath), h.SystemConfigUpdateListHandler)
+apiRouter.Get(fmt.Sprintf(FeeVaultWithdrawalsPath), h.FeeVaultWithdrawalListHandler)
+apiRouter.Get(fmt.Sprintf(FeeVaultRevenuePath), h.FeeVaultRevenueHandler)
 */
 
 type LruCache struct {
@@ -29,6 +35,11 @@ type LruCache struct {
 	lruDataStoreById      *lru.LRU[string, any]
 	lruDataStoreBlockById *lru.LRU[string, any]
 	lruStateRootByIndex   *lru.LRU[string, any]
+	lruERC721List         *lru.LRU[string, any]
+	lruERC721ByHash       *lru.LRU[string, any]
+	lruSystemConfigList   *lru.LRU[string, any]
+	lruFeeVaultList       *lru.LRU[string, any]
+	lruFeeVaultRevenue    *lru.LRU[string, any]
 }
 
 func NewLruCache(cfg config.CacheConfig) *LruCache {
@@ -39,6 +50,11 @@ func NewLruCache(cfg config.CacheConfig) *LruCache {
 	lruDataStoreById := lru.NewLRU[string, any](cfg.DetailSize, nil, cfg.DetailExpireTime)
 	lruDataStoreBlockById := lru.NewLRU[string, any](cfg.DetailSize, nil, cfg.DetailExpireTime)
 	lruStateRootByIndex := lru.NewLRU[string, any](cfg.DetailSize, nil, cfg.DetailExpireTime)
+	lruERC721List := lru.NewLRU[string, any](cfg.ListSize, nil, cfg.ListExpireTime)
+	lruERC721ByHash := lru.NewLRU[string, any](cfg.DetailSize, nil, cfg.DetailExpireTime)
+	lruSystemConfigList := lru.NewLRU[string, any](cfg.ListSize, nil, cfg.ListExpireTime)
+	lruFeeVaultList := lru.NewLRU[string, any](cfg.ListSize, nil, cfg.ListExpireTime)
+	lruFeeVaultRevenue := lru.NewLRU[string, any](cfg.ListSize, nil, cfg.ListExpireTime)
 	return &LruCache{
 		lruDataStoreList:      lruDataStoreList,
 		lruL1ToL2List:         lruL1ToL2List,
@@ -47,6 +63,11 @@ func NewLruCache(cfg config.CacheConfig) *LruCache {
 		lruDataStoreById:      lruDataStoreById,
 		lruDataStoreBlockById: lruDataStoreBlockById,
 		lruStateRootByIndex:   lruStateRootByIndex,
+		lruERC721List:         lruERC721List,
+		lruERC721ByHash:       lruERC721ByHash,
+		lruSystemConfigList:   lruSystemConfigList,
+		lruFeeVaultList:       lruFeeVaultList,
+		lruFeeVaultRevenue:    lruFeeVaultRevenue,
 	}
 }
 
@@ -133,3 +154,63 @@ func (lc *LruCache) GetStateRootByIndex(key string) (*business.StateRoot, error)
 func (lc *LruCache) AddStateRootByIndex(key string, data *business.StateRoot) {
 	lc.lruStateRootByIndex.Add(key, data)
 }
+
+func (lc *LruCache) GetERC721List(key string) (*models.ERC721BridgesResponse, error) {
+	result, ok := lc.lruERC721List.Get(key)
+	if !ok {
+		return nil, errors.New("lru get erc721 bridge list fail")
+	}
+	return result.(*models.ERC721BridgesResponse), nil
+}
+
+func (lc *LruCache) AddERC721List(key string, data *models.ERC721BridgesResponse) {
+	lc.lruERC721List.Add(key, data)
+}
+
+func (lc *LruCache) GetERC721ByMessageHash(key string) (*business.ERC721Bridge, error) {
+	result, ok := lc.lruERC721ByHash.Get(key)
+	if !ok {
+		return nil, errors.New("lru get erc721 bridge by message hash fail")
+	}
+	return result.(*business.ERC721Bridge), nil
+}
+
+func (lc *LruCache) AddERC721ByMessageHash(key string, data *business.ERC721Bridge) {
+	lc.lruERC721ByHash.Add(key, data)
+}
+
+func (lc *LruCache) GetSystemConfigList(key string) (*models.SystemConfigUpdatesResponse, error) {
+	result, ok := lc.lruSystemConfigList.Get(key)
+	if !ok {
+		return nil, errors.New("lru get system config update list fail")
+	}
+	return result.(*models.SystemConfigUpdatesResponse), nil
+}
+
+func (lc *LruCache) AddSystemConfigList(key string, data *models.SystemConfigUpdatesResponse) {
+	lc.lruSystemConfigList.Add(key, data)
+}
+
+func (lc *LruCache) GetFeeVaultList(key string) (*models.FeeVaultWithdrawalsResponse, error) {
+	result, ok := lc.lruFeeVaultList.Get(key)
+	if !ok {
+		return nil, errors.New("lru get fee vault withdrawal list fail")
+	}
+	return result.(*models.FeeVaultWithdrawalsResponse), nil
+}
+
+func (lc *LruCache) AddFeeVaultList(key string, data *models.FeeVaultWithdrawalsResponse) {
+	lc.lruFeeVaultList.Add(key, data)
+}
+
+func (lc *LruCache) GetFeeVaultRevenue(key string) (*models.FeeVaultRevenueResponse, error) {
+	result, ok := lc.lruFeeVaultRevenue.Get(key)
+	if !ok {
+		return nil, errors.New("lru get fee vault revenue fail")
+	}
+	return result.(*models.FeeVaultRevenueResponse), nil
+}
+
+func (lc *LruCache) AddFeeVaultRevenue(key string, data *models.FeeVaultRevenueResponse) {
+	lc.lruFeeVaultRevenue.Add(key, data)
+}
@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"math/big"
+
 	"github.com/urfave/cli/v2"
 
 	"github.com/ethereum/go-ethereum/params"
@@ -17,6 +19,32 @@ import (
 	flag2 "github.com/mantlenetworkio/lithosphere/flag"
 )
 
+var (
+	ReindexChainFlag = &cli.StringFlag{
+		Name:     "chain",
+		Usage:    "The chain to reindex, l1 or l2",
+		Required: true,
+	}
+	ReindexFromFlag = &cli.Uint64Flag{
+		Name:     "from",
+		Usage:    "The first block of the range to reindex",
+		Required: true,
+	}
+	ReindexToFlag = &cli.Uint64Flag{
+		Name:     "to",
+		Usage:    "The last block of the range to reindex",
+		Required: true,
+	}
+	ReindexDryRunFlag = &cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "Report the rows that would be deleted without changing anything",
+	}
+	ReprocessRestartFlag = &cli.BoolFlag{
+		Name:  "restart",
+		Usage: "Discard the progress of an interrupted reprocess and start over",
+	}
+)
+
 func runIndexer(ctx *cli.Context, shutdown context.CancelCauseFunc) (cliapp.Lifecycle, error) {
 	log := oplog.NewLogger(oplog.AppOut(ctx), oplog.ReadCLIConfig(ctx)).New("role", "lithosphere")
 	oplog.SetGlobalLogHandler(log.GetHandler())
@@ -80,6 +108,34 @@ func runMigrations(ctx *cli.Context) error {
 	return db.ExecuteSQLMigration(cfg.Migrations)
 }
 
+func runReindex(ctx *cli.Context) error {
+	ctx.Context = opio.CancelOnInterrupt(ctx.Context)
+	log := oplog.NewLogger(oplog.AppOut(ctx), oplog.ReadCLIConfig(ctx)).New("role", "reindex")
+	oplog.SetGlobalLogHandler(log.GetHandler())
+	log.Info("running reindex...")
+	cfg, err := config.LoadConfig(log, ctx)
+	if err != nil {
+		log.Error("failed to load config", "err", err)
+		return err
+	}
+	from := new(big.Int).SetUint64(ctx.Uint64(ReindexFromFlag.Name))
+	to := new(big.Int).SetUint64(ctx.Uint64(ReindexToFlag.Name))
+	return lithosphere.Reindex(ctx.Context, log, &cfg, ctx.String(ReindexChainFlag.Name), from, to, ctx.Bool(ReindexDryRunFlag.Name))
+}
+
+func runReprocess(ctx *cli.Context) error {
+	ctx.Context = opio.CancelOnInterrupt(ctx.Context)
+	log := oplog.NewLogger(oplog.AppOut(ctx), oplog.ReadCLIConfig(ctx)).New("role", "reprocess")
+	oplog.SetGlobalLogHandler(log.GetHandler())
+	log.Info("running reprocess...")
+	cfg, err := config.LoadConfig(log, ctx)
+	if err != nil {
+		log.Error("failed to load config", "err", err)
+		return err
+	}
+	return lithosphere.Reprocess(ctx.Context, log, &cfg, ctx.Bool(ReprocessRestartFlag.Name))
+}
+
 func newCli(GitCommit string, GitDate string) *cli.App {
 	flags := oplog.CLIFlags("LITHOSPHERE")
 	flags = append(flags, flag2.Flags...)
@@ -106,6 +162,18 @@ func newCli(GitCommit string, GitDate string) *cli.App {
 				Description: "Runs the database migrations",
 				Action:      runMigrations,
 			},
+			{
+				Name:        "reindex",
+				Flags:       append([]cli.Flag{ReindexChainFlag, ReindexFromFlag, ReindexToFlag, ReindexDryRunFlag}, flags...),
+				Description: "Re-derives the indexed state of a block range while the indexer is stopped",
+				Action:      runReindex,
+			},
+			{
+				Name:        "reprocess",
+				Flags:       append([]cli.Flag{ReprocessRestartFlag}, flags...),
+				Description: "Rebuilds the bridge and state root tables from the indexed contract events while the indexer is stopped",
+				Action:      runReprocess,
+			},
 			{
 				Name:        "exporter",
 				Flags:       flags,
@@ -4,8 +4,23 @@ const (
 	L2ToL1Pending           = 0
 	L2ToL1ReadyForProved    = 1
 	L2ToL1InChallengePeriod = 2
-	L2ToL1ReadyForClaim     = 3
+	L2ToL1ReadyForClaim     = 3 // computed at query time from the challenge deadline, never stored
 	L2ToL1Claimed           = 4
 	L1ToL2Pending           = 1
 	L1ToL2Claimed           = 2
+	// The relay of the message by the messenger reverted and it has not been replayed successfully yet
+	L2ToL1RelayFailed = 5
+	L1ToL2RelayFailed = 3
+	// The output root of a state root as recomputed from the L2 node
+	OutputRootUnverified = 0
+	OutputRootVerified   = 1
+	OutputRootMismatch   = 2
+	// The deposit transaction of a deposit not relayed by the messenger reverted on L2
+	L1ToL2DepositFailed = 4
+	// The receipt of the deposit transaction on L2
+	DepositUnconfirmed = 0
+	DepositSucceeded   = 1
+	DepositReverted    = 2
+	// The deposit transaction was not found on L2 although the L2 chain moved past the L1 origin of its deposit
+	DepositMissing = 3
 )
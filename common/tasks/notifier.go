@@ -0,0 +1,55 @@
+package tasks
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Notifier fans out a notification to its subscribers every time new data has been committed.
+// Notifications are coalesced, a subscriber still busy with the previous one is only notified once.
+type Notifier struct {
+	mu          sync.Mutex
+	subscribers []chan struct{}
+}
+
+// Subscribe returns a channel receiving a value after every Publish
+func (n *Notifier) Subscribe() <-chan struct{} {
+	subscriber := make(chan struct{}, 1)
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.subscribers = append(n.subscribers, subscriber)
+	return subscriber
+}
+
+// Publish notifies the subscribers, without blocking on the ones yet to receive the previous notification
+func (n *Notifier) Publish() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for _, subscriber := range n.subscribers {
+		select {
+		case subscriber <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Loop runs fn on every notification received from updates, falling back to running it once the
+// interval elapsed without any, until ctx is done. A nil updates channel runs fn on the interval only.
+func Loop(ctx context.Context, interval time.Duration, updates <-chan struct{}, fn func()) {
+	fallback := time.NewTimer(interval)
+	defer fallback.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-updates:
+			if !fallback.Stop() {
+				<-fallback.C
+			}
+		case <-fallback.C:
+		}
+		fn()
+		fallback.Reset(interval)
+	}
+}
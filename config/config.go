@@ -32,10 +32,14 @@ type Config struct {
 	MetricsServer      ServerConfig
 	ExporterConfig     ExporterConfig
 	StartDataStoreId   uint32
-	FraudProofWindows  uint64
 	WithdrawCalcEnable bool
 	CheckingAddress    CheckingConfig
 	TokenListUrl       string
+	// WithdrawalMonitorGracePeriod delays the incidents of the L1 proofs and finalizations without L2 initiation
+	WithdrawalMonitorGracePeriod time.Duration
+	// WithdrawalMonitorProposalLag bounds the delay of the output proposals, the L1 events of the withdrawals initiated
+	// before the first indexed L2 block are not reported within it
+	WithdrawalMonitorProposalLag time.Duration
 }
 
 type L1Contracts struct {
@@ -64,18 +68,26 @@ func (c L1Contracts) ForEach(cb func(string, common.Address) error) error {
 }
 
 type L2Contracts struct {
-	L2ToL1MessagePasser    common.Address
-	L2CrossDomainMessenger common.Address
-	L2StandardBridge       common.Address
-	L2ERC721Bridge         common.Address
+	L2ToL1MessagePasser          common.Address
+	L2CrossDomainMessenger       common.Address
+	L2StandardBridge             common.Address
+	L2ERC721Bridge               common.Address
+	SequencerFeeVault            common.Address
+	BaseFeeVault                 common.Address
+	L1FeeVault                   common.Address
+	OptimismMintableERC20Factory common.Address
 }
 
 func L2ContractsFromPredeploys() L2Contracts {
 	return L2Contracts{
-		L2ToL1MessagePasser:    predeploys.L2ToL1MessagePasserAddr,
-		L2CrossDomainMessenger: predeploys.L2CrossDomainMessengerAddr,
-		L2StandardBridge:       predeploys.L2StandardBridgeAddr,
-		L2ERC721Bridge:         predeploys.L2ERC721BridgeAddr,
+		L2ToL1MessagePasser:          predeploys.L2ToL1MessagePasserAddr,
+		L2CrossDomainMessenger:       predeploys.L2CrossDomainMessengerAddr,
+		L2StandardBridge:             predeploys.L2StandardBridgeAddr,
+		L2ERC721Bridge:               predeploys.L2ERC721BridgeAddr,
+		SequencerFeeVault:            predeploys.SequencerFeeVaultAddr,
+		BaseFeeVault:                 predeploys.BaseFeeVaultAddr,
+		L1FeeVault:                   predeploys.L1FeeVaultAddr,
+		OptimismMintableERC20Factory: predeploys.OptimismMintableERC20FactoryAddr,
 	}
 }
 
@@ -99,8 +111,14 @@ type ChainConfig struct {
 	L2BedrockStartingHeight uint
 	L1Contracts             L1Contracts
 	L2Contracts             L2Contracts
+	L1ContractEvents        ContractEvents
+	L2ContractEvents        ContractEvents
 	L1ConfirmationDepth     uint
 	L2ConfirmationDepth     uint
+	L1TraversalMode         string
+	L2TraversalMode         string
+	L1SubscribeNewHeads     bool
+	L2SubscribeNewHeads     bool
 	L1PollingInterval       uint
 	L2PollingInterval       uint
 	L1HeaderBufferSize      uint
@@ -108,8 +126,10 @@ type ChainConfig struct {
 }
 
 type RPCsConfig struct {
-	L1RPC string
-	L2RPC string
+	L1RPCs   []string
+	L2RPCs   []string
+	L1Quorum uint
+	L2Quorum uint
 }
 
 type DBConfig struct {
@@ -144,6 +164,17 @@ func LoadConfig(log log.Logger, cliCtx *cli.Context) (Config, error) {
 	cfg = NewConfig(cliCtx)
 	cfg.Chain.L2Contracts = L2ContractsFromPredeploys()
 
+	l1ContractEvents, err := L1ContractEventsFromBindings()
+	if err != nil {
+		return cfg, err
+	}
+	cfg.Chain.L1ContractEvents = l1ContractEvents
+	l2ContractEvents, err := L2ContractEventsFromBindings()
+	if err != nil {
+		return cfg, err
+	}
+	cfg.Chain.L2ContractEvents = l2ContractEvents
+
 	if cfg.Chain.L1PollingInterval == 0 {
 		cfg.Chain.L1PollingInterval = defaultLoopInterval
 	}
@@ -209,14 +240,20 @@ func NewConfig(ctx *cli.Context) Config {
 			},
 			L1ConfirmationDepth: ctx.Uint(flag.L1ConfirmationDepthFlag.Name),
 			L2ConfirmationDepth: ctx.Uint(flag.L2ConfirmationDepthFlag.Name),
+			L1TraversalMode:     ctx.String(flag.L1TraversalModeFlag.Name),
+			L2TraversalMode:     ctx.String(flag.L2TraversalModeFlag.Name),
+			L1SubscribeNewHeads: ctx.Bool(flag.L1SubscribeNewHeadsFlag.Name),
+			L2SubscribeNewHeads: ctx.Bool(flag.L2SubscribeNewHeadsFlag.Name),
 			L1PollingInterval:   ctx.Uint(flag.L1PollingIntervalFlag.Name),
 			L2PollingInterval:   ctx.Uint(flag.L2PollingIntervalFlag.Name),
 			L1HeaderBufferSize:  ctx.Uint(flag.L1HeaderBufferSizeFlag.Name),
 			L2HeaderBufferSize:  ctx.Uint(flag.L2HeaderBufferSizeFlag.Name),
 		},
 		RPCs: RPCsConfig{
-			L1RPC: ctx.String(flag.L1EthRpcFlag.Name),
-			L2RPC: ctx.String(flag.L2EthRpcFlag.Name),
+			L1RPCs:   ctx.StringSlice(flag.L1EthRpcFlag.Name),
+			L2RPCs:   ctx.StringSlice(flag.L2EthRpcFlag.Name),
+			L1Quorum: ctx.Uint(flag.L1RpcQuorumFlag.Name),
+			L2Quorum: ctx.Uint(flag.L2RpcQuorumFlag.Name),
 		},
 		DA: DAConfig{
 			RetrieverSocket:          ctx.String(flag.RetrieverSocketFlag.Name),
@@ -256,7 +293,7 @@ func NewConfig(ctx *cli.Context) Config {
 		},
 		ExporterConfig: ExporterConfig{
 			ExportAddress:                     ctx.String(flag.ExporterAddressFlag.Name),
-			RpcProvider:                       ctx.String(flag.L2EthRpcFlag.Name),
+			RpcProvider:                       firstRPC(ctx.StringSlice(flag.L2EthRpcFlag.Name)),
 			NetworkLabel:                      ctx.String(flag.NetworkLabelFlag.Name),
 			Version:                           ctx.Bool(flag.VersionEnableFlag.Name),
 			UnhealthyTimePeriod:               ctx.Duration(flag.UnhealthyTimePeriodFlag.Name),
@@ -275,8 +312,18 @@ func NewConfig(ctx *cli.Context) Config {
 			L2AccountCheckingAddress: ctx.String(flag.L2AccountCheckingAddressFlag.Name),
 		},
 		StartDataStoreId:   uint32(ctx.Uint64(flag.StartDataStoreIdFlag.Name)),
-		FraudProofWindows:  ctx.Uint64(flag.FraudProofWindowsFlags.Name),
 		WithdrawCalcEnable: ctx.Bool(flag.EnableWithdrawCalcFlag.Name),
 		TokenListUrl:       ctx.String(flag.TokenListUrlFlag.Name),
+
+		WithdrawalMonitorGracePeriod: ctx.Duration(flag.WithdrawalMonitorGracePeriodFlag.Name),
+		WithdrawalMonitorProposalLag: ctx.Duration(flag.WithdrawalMonitorProposalLagFlag.Name),
+	}
+}
+
+// firstRPC returns the primary provider of a chain, used by components talking to a single endpoint
+func firstRPC(rpcs []string) string {
+	if len(rpcs) == 0 {
+		return ""
 	}
+	return rpcs[0]
 }
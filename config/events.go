@@ -0,0 +1,96 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/mantlenetworkio/lithosphere/event/op-bindings/bindings"
+	legacy_bindings "github.com/mantlenetworkio/lithosphere/event/op-bindings/legacy-bindings"
+)
+
+// TransferBigValueContracts is the ContractEvents key of the token contracts configured
+// through the TransferBigValueAddress* exporter settings.
+const TransferBigValueContracts = "TransferBigValueAddress"
+
+// confirmDataStoreEventID is the DataLayrServiceManager event parsed by the mantle-da processor,
+// which has no op-bindings ABI.
+var confirmDataStoreEventID = crypto.Keccak256Hash([]byte("ConfirmDataStore(uint32,bytes32)"))
+
+// ContractEvents is the allowlist of event signatures extracted by a synchronizer, keyed by the
+// L1Contracts/L2Contracts field name. The logs of contracts without an entry are not extracted.
+type ContractEvents map[string][]common.Hash
+
+type abiEvents struct {
+	metadata *bind.MetaData
+	names    []string
+}
+
+var l1ContractEvents = map[string]abiEvents{
+	"OptimismPortalProxy":         {bindings.OptimismPortalMetaData, []string{"TransactionDeposited", "WithdrawalProven", "WithdrawalFinalized"}},
+	"L2OutputOracleProxy":         {bindings.L2OutputOracleMetaData, []string{"OutputProposed", "OutputsDeleted"}},
+	"SystemConfigProxy":           {bindings.SystemConfigMetaData, []string{"ConfigUpdate"}},
+	"L1CrossDomainMessengerProxy": {bindings.L1CrossDomainMessengerMetaData, []string{"SentMessage", "SentMessageExtension1", "RelayedMessage", "FailedRelayedMessage"}},
+	"L1StandardBridgeProxy": {bindings.L1StandardBridgeMetaData, []string{
+		"ETHBridgeInitiated", "ERC20BridgeInitiated", "MNTBridgeInitiated",
+		"ETHBridgeFinalized", "ERC20BridgeFinalized", "MNTBridgeFinalized",
+		"ETHDepositInitiated", "ERC20DepositInitiated", "MNTDepositInitiated",
+	}},
+	"L1ERC721BridgeProxy":             {bindings.L1ERC721BridgeMetaData, []string{"ERC721BridgeInitiated", "ERC721BridgeFinalized"}},
+	"LegacyCanonicalTransactionChain": {legacy_bindings.CanonicalTransactionChainMetaData, []string{"TransactionEnqueued"}},
+	"LegacyStateCommitmentChain":      {legacy_bindings.StateCommitmentChainMetaData, []string{"StateBatchAppended"}},
+	TransferBigValueContracts:         {bindings.ERC20MetaData, []string{"Transfer"}},
+}
+
+var l2ContractEvents = map[string]abiEvents{
+	"L2ToL1MessagePasser":    {bindings.L2ToL1MessagePasserMetaData, []string{"MessagePassed"}},
+	"L2CrossDomainMessenger": {bindings.CrossDomainMessengerMetaData, []string{"SentMessage", "SentMessageExtension1", "RelayedMessage", "FailedRelayedMessage"}},
+	"L2StandardBridge": {bindings.L2StandardBridgeMetaData, []string{
+		"ETHBridgeInitiated", "ERC20BridgeInitiated", "MNTBridgeInitiated",
+		"ETHBridgeFinalized", "ERC20BridgeFinalized", "MNTBridgeFinalized",
+		"WithdrawalInitiated",
+	}},
+	"L2ERC721Bridge":               {bindings.L2ERC721BridgeMetaData, []string{"ERC721BridgeInitiated", "ERC721BridgeFinalized"}},
+	"SequencerFeeVault":            {bindings.SequencerFeeVaultMetaData, []string{"Withdrawal"}},
+	"BaseFeeVault":                 {bindings.BaseFeeVaultMetaData, []string{"Withdrawal"}},
+	"L1FeeVault":                   {bindings.L1FeeVaultMetaData, []string{"Withdrawal"}},
+	"OptimismMintableERC20Factory": {bindings.OptimismMintableERC20FactoryMetaData, []string{"OptimismMintableERC20Created"}},
+	TransferBigValueContracts:      {bindings.ERC20MetaData, []string{"Transfer"}},
+}
+
+// L1ContractEventsFromBindings returns the events of the L1 contracts used by the
+// processors and the exporter, resolved from the op-bindings ABIs.
+func L1ContractEventsFromBindings() (ContractEvents, error) {
+	events, err := contractEventsFromBindings(l1ContractEvents)
+	if err != nil {
+		return nil, err
+	}
+	events["DataLayrServiceManagerAddr"] = []common.Hash{confirmDataStoreEventID}
+	return events, nil
+}
+
+// L2ContractEventsFromBindings returns the events of the L2 contracts used by the
+// processors and the exporter, resolved from the op-bindings ABIs.
+func L2ContractEventsFromBindings() (ContractEvents, error) {
+	return contractEventsFromBindings(l2ContractEvents)
+}
+
+func contractEventsFromBindings(contracts map[string]abiEvents) (ContractEvents, error) {
+	events := make(ContractEvents, len(contracts))
+	for contract, allowlist := range contracts {
+		contractAbi, err := allowlist.metadata.GetAbi()
+		if err != nil {
+			return nil, fmt.Errorf("unable to load %s abi: %w", contract, err)
+		}
+		for _, name := range allowlist.names {
+			event, ok := contractAbi.Events[name]
+			if !ok {
+				return nil, fmt.Errorf("event %s not found in the %s abi", name, contract)
+			}
+			events[contract] = append(events[contract], event.ID)
+		}
+	}
+	return events, nil
+}
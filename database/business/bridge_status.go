@@ -0,0 +1,394 @@
+package business
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+
+	common3 "github.com/mantlenetworkio/lithosphere/common"
+	common2 "github.com/mantlenetworkio/lithosphere/database/common"
+)
+
+// Bridges whose status history is recorded
+const (
+	BridgeDeposit    = "deposit"
+	BridgeWithdrawal = "withdrawal"
+)
+
+// BridgeStatusHistory is a status transition of a deposit or a withdrawal. The history is append only, a transition
+// rolled back along with a reorg is followed by the transition moving the bridge back. The cause is the event of the
+// transition, unset for the transitions not caused by any event.
+type BridgeStatusHistory struct {
+	ID         uint64    `gorm:"primaryKey" json:"id"`
+	Bridge     string    `gorm:"column:bridge" json:"bridge"`
+	BridgeGUID uuid.UUID `gorm:"column:bridge_guid" json:"bridgeGuid"`
+	// FromStatus is nil for the initiation of the bridge
+	FromStatus      *int64       `gorm:"column:from_status" json:"fromStatus"`
+	ToStatus        int64        `gorm:"column:to_status" json:"toStatus"`
+	Reason          string       `gorm:"column:reason" json:"reason"`
+	Chain           string       `gorm:"column:chain" json:"chain"`
+	BlockNumber     *big.Int     `gorm:"serializer:u256;column:block_number" json:"blockNumber"`
+	TransactionHash *common.Hash `gorm:"serializer:bytes;column:transaction_hash" json:"transactionHash"`
+	// Timestamp is the timestamp of the block of the cause, or of the latest indexed block of the chain for the
+	// transitions not caused by any event
+	Timestamp int64 `gorm:"column:timestamp" json:"timestamp"`
+}
+
+func (BridgeStatusHistory) TableName() string {
+	return "bridge_status_history"
+}
+
+type BridgeStatusHistoryView interface {
+	BridgeStatusTimeline(bridge string, bridgeGUIDs []uuid.UUID) ([]BridgeStatusHistory, error)
+}
+
+type bridgeStatusHistoryDB struct {
+	gorm *gorm.DB
+}
+
+func NewBridgeStatusHistoryDB(db *gorm.DB) BridgeStatusHistoryView {
+	return &bridgeStatusHistoryDB{gorm: db}
+}
+
+// BridgeStatusTimeline returns the transitions of the deposits or withdrawals, in the order they were indexed
+func (db bridgeStatusHistoryDB) BridgeStatusTimeline(bridge string, bridgeGUIDs []uuid.UUID) ([]BridgeStatusHistory, error) {
+	var history []BridgeStatusHistory
+	if len(bridgeGUIDs) == 0 {
+		return history, nil
+	}
+	result := db.gorm.Where("bridge = ? AND bridge_guid IN ?", bridge, bridgeGUIDs).Order("id ASC").Find(&history)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return history, nil
+}
+
+// legacyDepositStatus is the status the deposits of the legacy bridge are indexed with
+const legacyDepositStatus = 0
+
+// depositLifecycle lists the transitions allowed between the statuses of the deposits
+var depositLifecycle = lifecycle{
+	bridge: BridgeDeposit,
+	table:  "l1_to_l2",
+	transitions: map[int64][]int64{
+		legacyDepositStatus: {common3.L1ToL2Claimed},
+		common3.L1ToL2Pending: {
+			common3.L1ToL2Claimed,       // relayed by the messenger, or deposit transaction executed
+			common3.L1ToL2RelayFailed,   // relay reverted
+			common3.L1ToL2DepositFailed, // deposit transaction reverted
+		},
+		common3.L1ToL2RelayFailed: {
+			common3.L1ToL2Claimed, // replayed
+			common3.L1ToL2Pending, // failed relay rolled back
+		},
+		common3.L1ToL2Claimed:       {common3.L1ToL2Pending}, // relay rolled back
+		common3.L1ToL2DepositFailed: {common3.L1ToL2Pending}, // receipt rolled back
+	},
+}
+
+// withdrawalLifecycle lists the transitions allowed between the statuses of the withdrawals. The events of L1 are
+// authoritative: a withdrawal is moved to its proven or finalized status even if the output covering it has not been
+// indexed yet. A proven withdrawal stays in its challenge period until finalized, ready for claim is computed from its
+// challenge deadline at query time and never stored.
+var withdrawalLifecycle = lifecycle{
+	bridge: BridgeWithdrawal,
+	table:  "l2_to_l1",
+	transitions: map[int64][]int64{
+		common3.L2ToL1Pending: {
+			common3.L2ToL1ReadyForProved,    // output proposed
+			common3.L2ToL1InChallengePeriod, // proven before its output was indexed
+			common3.L2ToL1Claimed,           // legacy withdrawal finalized
+		},
+		common3.L2ToL1ReadyForProved: {
+			common3.L2ToL1Pending,           // output deleted or rolled back
+			common3.L2ToL1InChallengePeriod, // proven
+			common3.L2ToL1Claimed,           // legacy withdrawal finalized
+		},
+		common3.L2ToL1InChallengePeriod: {
+			common3.L2ToL1Claimed,        // finalized
+			common3.L2ToL1ReadyForProved, // proof rolled back or invalidated
+			common3.L2ToL1Pending,        // proof invalidated, no output covers the withdrawal anymore
+		},
+		common3.L2ToL1Claimed: {
+			common3.L2ToL1RelayFailed,       // relay reverted
+			common3.L2ToL1InChallengePeriod, // finalization rolled back
+		},
+		common3.L2ToL1RelayFailed: {
+			common3.L2ToL1Claimed,           // replayed, or failed relay rolled back
+			common3.L2ToL1InChallengePeriod, // finalization rolled back
+		},
+	},
+}
+
+// lifecycle is the transition table of the statuses of a bridge
+type lifecycle struct {
+	bridge      string
+	table       string
+	transitions map[int64][]int64
+}
+
+func (lc lifecycle) allowed(from, to int64) bool {
+	for _, target := range lc.transitions[from] {
+		if target == to {
+			return true
+		}
+	}
+	return false
+}
+
+// RejectedTransition is a move of a bridge to a status its lifecycle does not allow
+type RejectedTransition struct {
+	Bridge string
+	GUID   uuid.UUID
+	From   int64
+	To     int64
+	Reason string
+}
+
+// RejectedTransitionsError is returned once the allowed transitions of an update have been applied, when some bridges
+// may not move to their new status. These are left untouched, the bridge status or the event moving it is inconsistent.
+type RejectedTransitionsError struct {
+	Transitions []RejectedTransition
+}
+
+func (e *RejectedTransitionsError) Error() string {
+	return fmt.Sprintf("%d rejected bridge status transitions", len(e.Transitions))
+}
+
+// HandleRejectedTransitions passes the rejected transitions of the error to record, any other error is returned.
+// The callers of the updates moving the bridges carry on past the rejected transitions.
+func HandleRejectedTransitions(err error, record func(RejectedTransition)) error {
+	var rejected *RejectedTransitionsError
+	if !errors.As(err, &rejected) {
+		return err
+	}
+	for _, transition := range rejected.Transitions {
+		record(transition)
+	}
+	return nil
+}
+
+// rejections collects the rejected transitions of the successive transitions of an update, which carries on past them
+type rejections struct {
+	transitions []RejectedTransition
+}
+
+// check collects the rejected transitions of the error, any other error is returned
+func (r *rejections) check(err error) error {
+	var rejected *RejectedTransitionsError
+	if errors.As(err, &rejected) {
+		r.transitions = append(r.transitions, rejected.Transitions...)
+		return nil
+	}
+	return err
+}
+
+// err returns the RejectedTransitionsError of the collected transitions, nil if none
+func (r *rejections) err() error {
+	if len(r.transitions) == 0 {
+		return nil
+	}
+	return &RejectedTransitionsError{Transitions: r.transitions}
+}
+
+// statusCause is the event moving a deposit or a withdrawal to another status. The block number and transaction
+// hash are either values, or SQL expressions evaluated on every row moved. Both are left unset when the
+// transition is not caused by any event.
+type statusCause struct {
+	reason              string
+	chain               string
+	blockNumber         *big.Int
+	transactionHash     *common.Hash
+	blockNumberExpr     *clause.Expr
+	transactionHashExpr *clause.Expr
+}
+
+type statusRow struct {
+	GUID            uuid.UUID
+	Status          int64
+	BlockNumber     *big.Int     `gorm:"serializer:u256"`
+	TransactionHash *common.Hash `gorm:"serializer:bytes"`
+}
+
+// transitionBatchSize bounds the rows updated by a single statement of a transition
+const transitionBatchSize = 1000
+
+// transition moves the rows selected by the scope to the status along with the updates, locking them until the
+// end of the transaction. Every transition is checked against the lifecycle and recorded in the status history,
+// the rows already in the status are only updated. The rows which may not move to the status are left untouched,
+// and returned in a RejectedTransitionsError once the other rows have been moved.
+func (lc lifecycle) transition(db *gorm.DB, scope func(*gorm.DB) *gorm.DB, to int64, updates map[string]interface{}, cause statusCause) (int64, error) {
+	var moved int64
+	var rejected rejections
+	err := db.Transaction(func(tx *gorm.DB) error {
+		columns, args := "guid, status", []interface{}{}
+		if cause.blockNumberExpr != nil {
+			columns += ", (?) AS block_number"
+			args = append(args, *cause.blockNumberExpr)
+		}
+		if cause.transactionHashExpr != nil {
+			columns += ", (?) AS transaction_hash"
+			args = append(args, *cause.transactionHashExpr)
+		}
+		var rows []statusRow
+		result := tx.Table(lc.table).Scopes(scope).Select(columns, args...).Order("guid").
+			Clauses(clause.Locking{Strength: "UPDATE"}).Find(&rows)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		var guids []uuid.UUID
+		var history []BridgeStatusHistory
+		rejected = rejections{}
+		for i := range rows {
+			row := rows[i]
+			if row.Status != to && !lc.allowed(row.Status, to) {
+				log.Error("rejected bridge status transition", "bridge", lc.bridge, "guid", row.GUID, "from", row.Status, "to", to, "reason", cause.reason)
+				rejected.transitions = append(rejected.transitions, RejectedTransition{Bridge: lc.bridge, GUID: row.GUID, From: row.Status, To: to, Reason: cause.reason})
+				continue
+			}
+			guids = append(guids, row.GUID)
+			if row.Status == to {
+				continue
+			}
+			from := row.Status
+			entry := BridgeStatusHistory{
+				Bridge:          lc.bridge,
+				BridgeGUID:      row.GUID,
+				FromStatus:      &from,
+				ToStatus:        to,
+				Reason:          cause.reason,
+				Chain:           cause.chain,
+				BlockNumber:     cause.blockNumber,
+				TransactionHash: cause.transactionHash,
+			}
+			if cause.blockNumberExpr != nil {
+				entry.BlockNumber = row.BlockNumber
+			}
+			if cause.transactionHashExpr != nil {
+				entry.TransactionHash = row.TransactionHash
+			}
+			history = append(history, entry)
+		}
+		if len(history) > 0 {
+			if err := stampHistory(tx, cause.chain, history); err != nil {
+				return err
+			}
+			if err := tx.CreateInBatches(&history, transitionBatchSize).Error; err != nil {
+				return err
+			}
+		}
+
+		values := map[string]interface{}{"status": to}
+		for column, value := range updates {
+			values[column] = value
+		}
+		for start := 0; start < len(guids); start += transitionBatchSize {
+			end := start + transitionBatchSize
+			if end > len(guids) {
+				end = len(guids)
+			}
+			result := tx.Table(lc.table).Where("guid IN ?", guids[start:end]).Updates(values)
+			if result.Error != nil {
+				return result.Error
+			}
+			moved += result.RowsAffected
+		}
+		return nil
+	})
+	if err != nil {
+		return moved, err
+	}
+	return moved, rejected.err()
+}
+
+// initiated records the initiation of the bridges, with the status and the timestamp they were stored with
+func (lc lifecycle) initiated(db *gorm.DB, history []BridgeStatusHistory) error {
+	if len(history) == 0 {
+		return nil
+	}
+	for i := range history {
+		history[i].Bridge = lc.bridge
+		history[i].Reason = "initiated"
+	}
+	return db.CreateInBatches(&history, transitionBatchSize).Error
+}
+
+type blockTimestamp struct {
+	Number    *big.Int `gorm:"serializer:u256"`
+	Timestamp int64
+}
+
+// stampHistory sets the timestamps of the transitions from the indexed blocks of the chain they were caused at
+func stampHistory(db *gorm.DB, chain string, history []BridgeStatusHistory) error {
+	headers := "l1_block_headers"
+	if chain == common2.SyncCursorLayerL2 {
+		headers = "l2_block_headers"
+	}
+
+	var numbers []*big.Int
+	seen := make(map[string]bool)
+	for i := range history {
+		if number := history[i].BlockNumber; number != nil && !seen[number.String()] {
+			seen[number.String()] = true
+			numbers = append(numbers, number)
+		}
+	}
+	timestamps := make(map[string]int64, len(numbers))
+	for start := 0; start < len(numbers); start += transitionBatchSize {
+		end := start + transitionBatchSize
+		if end > len(numbers) {
+			end = len(numbers)
+		}
+		var blocks []blockTimestamp
+		if err := db.Table(headers).Where("number IN ?", numbers[start:end]).Select("number, timestamp").Find(&blocks).Error; err != nil {
+			return err
+		}
+		for _, block := range blocks {
+			timestamps[block.Number.String()] = block.Timestamp
+		}
+	}
+
+	var latest *int64
+	for i := range history {
+		if number := history[i].BlockNumber; number != nil {
+			if timestamp, ok := timestamps[number.String()]; ok {
+				history[i].Timestamp = timestamp
+				continue
+			}
+		}
+		if latest == nil {
+			var timestamp int64
+			err := db.Table(headers).Order("number DESC").Select("timestamp").Take(&timestamp).Error
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+			latest = &timestamp
+		}
+		history[i].Timestamp = *latest
+	}
+	return nil
+}
+
+// deleteHistory deletes the history of the bridges selected by the scope, before they are deleted themselves
+func (lc lifecycle) deleteHistory(db *gorm.DB, scope func(*gorm.DB) *gorm.DB) error {
+	deleted := db.Table(lc.table).Scopes(scope).Select("guid")
+	return db.Where("bridge = ? AND bridge_guid IN (?)", lc.bridge, deleted).Delete(&BridgeStatusHistory{}).Error
+}
+
+// relayAttemptColumn is a column of the relay attempt of the message of a bridge on the chain: the first successful
+// relay, which the bridge is claimed by, or the latest failed one
+func relayAttemptColumn(table, column, chain string, success bool) clause.Expr {
+	order := "ASC"
+	if !success {
+		order = "DESC"
+	}
+	return gorm.Expr("SELECT "+column+" FROM relay_attempt WHERE chain = ? AND success = ? AND relay_attempt.message_hash = "+table+".message_hash ORDER BY block_number "+order+", log_index "+order+" LIMIT 1", chain, success)
+}
@@ -0,0 +1,254 @@
+package business
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	common3 "github.com/mantlenetworkio/lithosphere/common"
+	common2 "github.com/mantlenetworkio/lithosphere/database/common"
+	_ "github.com/mantlenetworkio/lithosphere/database/utils/serializers"
+)
+
+func TestDepositLifecycle(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    int64
+		to      int64
+		allowed bool
+	}{
+		{"relayed", common3.L1ToL2Pending, common3.L1ToL2Claimed, true},
+		{"relay reverted", common3.L1ToL2Pending, common3.L1ToL2RelayFailed, true},
+		{"deposit transaction reverted", common3.L1ToL2Pending, common3.L1ToL2DepositFailed, true},
+		{"replayed", common3.L1ToL2RelayFailed, common3.L1ToL2Claimed, true},
+		{"legacy deposit relayed", legacyDepositStatus, common3.L1ToL2Claimed, true},
+
+		// rollbacks
+		{"relay rolled back", common3.L1ToL2Claimed, common3.L1ToL2Pending, true},
+		{"failed relay rolled back", common3.L1ToL2RelayFailed, common3.L1ToL2Pending, true},
+		{"deposit receipt rolled back", common3.L1ToL2DepositFailed, common3.L1ToL2Pending, true},
+
+		{"claimed relay failing", common3.L1ToL2Claimed, common3.L1ToL2RelayFailed, false},
+		{"reverted deposit transaction claimed", common3.L1ToL2DepositFailed, common3.L1ToL2Claimed, false},
+		{"reverted deposit transaction relayed", common3.L1ToL2DepositFailed, common3.L1ToL2RelayFailed, false},
+		{"failed relay reverting the deposit transaction", common3.L1ToL2RelayFailed, common3.L1ToL2DepositFailed, false},
+		{"legacy deposit failing", legacyDepositStatus, common3.L1ToL2RelayFailed, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.allowed, depositLifecycle.allowed(test.from, test.to))
+		})
+	}
+}
+
+func TestWithdrawalLifecycle(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    int64
+		to      int64
+		allowed bool
+	}{
+		{"output proposed", common3.L2ToL1Pending, common3.L2ToL1ReadyForProved, true},
+		{"proven", common3.L2ToL1ReadyForProved, common3.L2ToL1InChallengePeriod, true},
+		{"proven before the output was indexed", common3.L2ToL1Pending, common3.L2ToL1InChallengePeriod, true},
+		{"finalized", common3.L2ToL1InChallengePeriod, common3.L2ToL1Claimed, true},
+		{"legacy withdrawal finalized", common3.L2ToL1Pending, common3.L2ToL1Claimed, true},
+		{"relay reverted", common3.L2ToL1Claimed, common3.L2ToL1RelayFailed, true},
+		{"replayed", common3.L2ToL1RelayFailed, common3.L2ToL1Claimed, true},
+
+		// rollbacks and reproofs
+		{"output deleted", common3.L2ToL1ReadyForProved, common3.L2ToL1Pending, true},
+		{"proof rolled back", common3.L2ToL1InChallengePeriod, common3.L2ToL1ReadyForProved, true},
+		{"proof invalidated", common3.L2ToL1InChallengePeriod, common3.L2ToL1Pending, true},
+		{"finalization rolled back", common3.L2ToL1Claimed, common3.L2ToL1InChallengePeriod, true},
+		{"failed finalization rolled back", common3.L2ToL1RelayFailed, common3.L2ToL1InChallengePeriod, true},
+
+		{"ready for claim stored", common3.L2ToL1InChallengePeriod, common3.L2ToL1ReadyForClaim, false},
+		{"finalized from ready for claim", common3.L2ToL1ReadyForClaim, common3.L2ToL1Claimed, false},
+		{"claimed withdrawal proven again", common3.L2ToL1Claimed, common3.L2ToL1ReadyForProved, false},
+		{"claimed withdrawal pending", common3.L2ToL1Claimed, common3.L2ToL1Pending, false},
+		{"pending withdrawal relay failing", common3.L2ToL1Pending, common3.L2ToL1RelayFailed, false},
+		{"proven withdrawal relay failing", common3.L2ToL1InChallengePeriod, common3.L2ToL1RelayFailed, false},
+		{"failed relay proof rolled back", common3.L2ToL1RelayFailed, common3.L2ToL1ReadyForProved, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.allowed, withdrawalLifecycle.allowed(test.from, test.to))
+		})
+	}
+}
+
+func TestHandleRejectedTransitions(t *testing.T) {
+	first := RejectedTransition{Bridge: BridgeWithdrawal, GUID: uuid.New(), From: common3.L2ToL1Claimed, To: common3.L2ToL1ReadyForProved}
+	second := RejectedTransition{Bridge: BridgeWithdrawal, GUID: uuid.New(), From: common3.L2ToL1Pending, To: common3.L2ToL1RelayFailed}
+
+	// the successive transitions of an update carry on past the rejected ones
+	var rejected rejections
+	require.NoError(t, rejected.err())
+	require.NoError(t, rejected.check(nil))
+	require.NoError(t, rejected.check(&RejectedTransitionsError{Transitions: []RejectedTransition{first}}))
+	require.NoError(t, rejected.check(&RejectedTransitionsError{Transitions: []RejectedTransition{second}}))
+	failure := errors.New("connection reset")
+	require.ErrorIs(t, rejected.check(failure), failure)
+
+	var recorded []RejectedTransition
+	record := func(transition RejectedTransition) { recorded = append(recorded, transition) }
+	require.NoError(t, HandleRejectedTransitions(fmt.Errorf("mark withdrawals proven: %w", rejected.err()), record))
+	require.Equal(t, []RejectedTransition{first, second}, recorded)
+
+	require.ErrorIs(t, HandleRejectedTransitions(failure, record), failure)
+	require.NoError(t, HandleRejectedTransitions(nil, record))
+	require.Len(t, recorded, 2)
+}
+
+func TestStampHistory(t *testing.T) {
+	headers := &testHeaders{timestamps: map[string]map[string]int64{
+		"l1_block_headers": {"7": 70},
+		"l2_block_headers": {"10": 100, "11": 110, "12": 120},
+	}}
+	db := headers.open(t)
+
+	history := []BridgeStatusHistory{
+		{BlockNumber: big.NewInt(10)},
+		{BlockNumber: big.NewInt(11)},
+		{BlockNumber: big.NewInt(10)},
+		// not indexed yet, or not caused by any event
+		{BlockNumber: big.NewInt(13)},
+		{},
+	}
+	require.NoError(t, stampHistory(db, common2.SyncCursorLayerL2, history))
+	require.Equal(t, []int64{100, 110, 100, 120, 120}, []int64{history[0].Timestamp, history[1].Timestamp, history[2].Timestamp,
+		history[3].Timestamp, history[4].Timestamp})
+	// the block numbers are looked up once, and the latest header only for the transitions left
+	require.Equal(t, []testQuery{
+		{table: "l2_block_headers", numbers: 3},
+		{table: "l2_block_headers", latest: true},
+	}, headers.queries)
+
+	headers.queries = nil
+	history = []BridgeStatusHistory{{BlockNumber: big.NewInt(7)}}
+	require.NoError(t, stampHistory(db, common2.SyncCursorLayerL1, history))
+	require.Equal(t, int64(70), history[0].Timestamp)
+	require.Equal(t, []testQuery{{table: "l1_block_headers", numbers: 1}}, headers.queries)
+
+	// the block numbers are looked up in batches
+	headers.queries = nil
+	history = make([]BridgeStatusHistory, transitionBatchSize+1)
+	for i := range history {
+		history[i].BlockNumber = big.NewInt(int64(i))
+	}
+	require.NoError(t, stampHistory(db, common2.SyncCursorLayerL2, history))
+	require.Equal(t, []testQuery{
+		{table: "l2_block_headers", numbers: transitionBatchSize},
+		{table: "l2_block_headers", numbers: 1},
+		{table: "l2_block_headers", latest: true},
+	}, headers.queries)
+	require.Equal(t, int64(100), history[10].Timestamp)
+	require.Equal(t, int64(120), history[transitionBatchSize].Timestamp)
+
+	// nothing indexed on the chain yet
+	headers.queries = nil
+	headers.timestamps["l1_block_headers"] = nil
+	history = []BridgeStatusHistory{{BlockNumber: big.NewInt(7)}, {}}
+	require.NoError(t, stampHistory(db, common2.SyncCursorLayerL1, history))
+	require.Equal(t, int64(0), history[0].Timestamp)
+	require.Equal(t, int64(0), history[1].Timestamp)
+}
+
+// testQuery is a lookup of the block headers, of the supplied block numbers or of the latest header
+type testQuery struct {
+	table   string
+	numbers int
+	latest  bool
+}
+
+// testHeaders serves the timestamps of the block headers looked up by stampHistory through a database/sql driver
+type testHeaders struct {
+	timestamps map[string]map[string]int64
+	queries    []testQuery
+}
+
+func (h *testHeaders) open(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sql.OpenDB(h)}), &gorm.Config{SkipDefaultTransaction: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+	return db
+}
+
+func (h *testHeaders) Connect(context.Context) (driver.Conn, error) {
+	return &testConn{headers: h}, nil
+}
+func (h *testHeaders) Driver() driver.Driver { return nil }
+
+type testConn struct {
+	headers *testHeaders
+}
+
+func (c *testConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *testConn) Close() error                        { return nil }
+func (c *testConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+// CheckNamedValue passes the *big.Int block numbers through, like pgx
+func (c *testConn) CheckNamedValue(*driver.NamedValue) error { return nil }
+
+func (c *testConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	var table string
+	for name := range c.headers.timestamps {
+		if strings.Contains(query, `"`+name+`"`) {
+			table = name
+		}
+	}
+	if table == "" {
+		return nil, fmt.Errorf("unexpected query %s", query)
+	}
+	timestamps := c.headers.timestamps[table]
+
+	if strings.Contains(query, "ORDER BY number DESC") {
+		c.headers.queries = append(c.headers.queries, testQuery{table: table, latest: true})
+		rows := &testRows{columns: []string{"timestamp"}}
+		var latest *big.Int
+		for number, timestamp := range timestamps {
+			if n, _ := new(big.Int).SetString(number, 10); latest == nil || n.Cmp(latest) > 0 {
+				latest, rows.values = n, [][]driver.Value{{timestamp}}
+			}
+		}
+		return rows, nil
+	}
+
+	c.headers.queries = append(c.headers.queries, testQuery{table: table, numbers: len(args)})
+	rows := &testRows{columns: []string{"number", "timestamp"}}
+	for _, arg := range args {
+		number := arg.Value.(*big.Int).String()
+		if timestamp, ok := timestamps[number]; ok {
+			rows.values = append(rows.values, []driver.Value{number, timestamp})
+		}
+	}
+	return rows, nil
+}
+
+type testRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *testRows) Columns() []string { return r.columns }
+func (r *testRows) Close() error      { return nil }
+
+func (r *testRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
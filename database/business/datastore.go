@@ -11,7 +11,6 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
 
-	common2 "github.com/mantlenetworkio/lithosphere/database/common"
 	"github.com/mantlenetworkio/lithosphere/database/utils"
 )
 
@@ -51,6 +50,8 @@ type DataStoreDB interface {
 	DataStoreView
 	StoreBatchDataStores([]DataStore) error
 	StoreBatchDataStoreBlocks([]DataStoreBlock) error
+	RollbackDataStores(timestamp uint64) error
+	DeleteDataStores(fromTimestamp, toTimestamp uint64) (int64, error)
 }
 
 type DataStoreView interface {
@@ -58,7 +59,6 @@ type DataStoreView interface {
 	DataStoreById(id *big.Int) (*DataStore, error)
 	DataStoreBlockById(id *big.Int) ([]DataStoreBlock, error)
 	LatestDataStoreId() uint64
-	DataStoreL1BlockHeader() (*common2.L1BlockHeader, error)
 }
 
 type dataStoreDB struct {
@@ -88,6 +88,27 @@ func (d dataStoreDB) StoreBatchDataStores(stores []DataStore) error {
 	return result.Error
 }
 
+// RollbackDataStores removes the data stores, and their blocks, built from events emitted after
+// the supplied L1 block timestamp. It must run before those events are rolled back themselves.
+func (d dataStoreDB) RollbackDataStores(timestamp uint64) error {
+	rolledBack := d.gorm.Table("data_store_event").Select("data_store_id").Where("timestamp > ?", timestamp)
+	if err := d.gorm.Where("data_store_id IN (?)", rolledBack).Delete(&DataStoreBlock{}).Error; err != nil {
+		return err
+	}
+	return d.gorm.Where("data_store_id IN (?)", rolledBack).Delete(&DataStore{}).Error
+}
+
+// DeleteDataStores removes the data stores, and their blocks, built from events emitted within the
+// supplied range of L1 block timestamps.
+func (d dataStoreDB) DeleteDataStores(fromTimestamp, toTimestamp uint64) (int64, error) {
+	deleted := d.gorm.Table("data_store_event").Select("data_store_id").Where("timestamp >= ? AND timestamp <= ?", fromTimestamp, toTimestamp)
+	if err := d.gorm.Where("data_store_id IN (?)", deleted).Delete(&DataStoreBlock{}).Error; err != nil {
+		return 0, err
+	}
+	result := d.gorm.Where("data_store_id IN (?)", deleted).Delete(&DataStore{})
+	return result.RowsAffected, result.Error
+}
+
 func (d dataStoreDB) DataStoreById(id *big.Int) (*DataStore, error) {
 	var dataStore DataStore
 	dataStoreQuery := d.gorm.Where("data_store_id=?", id.Uint64())
@@ -130,16 +151,3 @@ func (d dataStoreDB) DataStoreList(page int, pageSize int, order string) (dataSt
 	}
 	return daList, totalRecord
 }
-
-func (d dataStoreDB) DataStoreL1BlockHeader() (*common2.L1BlockHeader, error) {
-	l1Query := d.gorm.Where("number = (?)", d.gorm.Table("data_store").Select("MAX(from_store_number)"))
-	var l1Header common2.L1BlockHeader
-	result := l1Query.Take(&l1Header)
-	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, nil
-		}
-		return nil, result.Error
-	}
-	return &l1Header, nil
-}
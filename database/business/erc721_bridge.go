@@ -0,0 +1,228 @@
+package business
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+
+	common3 "github.com/mantlenetworkio/lithosphere/common"
+)
+
+const (
+	ERC721Deposit    = "deposit"
+	ERC721Withdrawal = "withdrawal"
+)
+
+// ERC721Bridge is an NFT bridged through the L1ERC721Bridge/L2ERC721Bridge pair. Deposits go from
+// pending to claimed once finalized on L2, withdrawals are also proven on L1 before being claimed. A proven
+// withdrawal is ready for claim once the L1 timestamp is past its challenge deadline, computed at query time
+// like TimeLeft.
+type ERC721Bridge struct {
+	GUID               uuid.UUID      `gorm:"primaryKey" json:"guid"`
+	Direction          string         `gorm:"column:direction" json:"direction"`
+	MessageHash        common.Hash    `gorm:"column:message_hash;serializer:bytes" json:"messageHash"`
+	WithdrawalHash     common.Hash    `gorm:"column:withdrawal_hash;serializer:bytes" json:"withdrawalHash"`
+	L1BlockNumber      *big.Int       `gorm:"serializer:u256;column:l1_block_number" json:"l1BlockNumber"`
+	L2BlockNumber      *big.Int       `gorm:"serializer:u256;column:l2_block_number" json:"l2BlockNumber"`
+	L1TransactionHash  common.Hash    `gorm:"column:l1_transaction_hash;serializer:bytes" json:"l1TransactionHash"`
+	L2TransactionHash  common.Hash    `gorm:"column:l2_transaction_hash;serializer:bytes" json:"l2TransactionHash"`
+	L1ProveBlockNumber *big.Int       `gorm:"serializer:u256;column:l1_prove_block_number" json:"l1ProveBlockNumber"`
+	L1ProveTxHash      common.Hash    `gorm:"column:l1_prove_tx_hash;serializer:bytes" json:"l1ProveTxHash"`
+	L1ProvenTimestamp  int64          `gorm:"column:l1_proven_timestamp" json:"l1ProvenTimestamp"`
+	ChallengeDeadline  int64          `gorm:"column:challenge_deadline" json:"challengeDeadline"`
+	TimeLeft           *big.Int       `gorm:"-" json:"timeLeft"`
+	Status             int64          `gorm:"column:status" json:"status"`
+	FromAddress        common.Address `gorm:"column:from_address;serializer:bytes" json:"fromAddress"`
+	ToAddress          common.Address `gorm:"column:to_address;serializer:bytes" json:"toAddress"`
+	L1TokenAddress     common.Address `gorm:"column:l1_token_address;serializer:bytes" json:"l1TokenAddress"`
+	L2TokenAddress     common.Address `gorm:"column:l2_token_address;serializer:bytes" json:"l2TokenAddress"`
+	TokenId            *big.Int       `gorm:"serializer:u256;column:token_id" json:"tokenId"`
+	Timestamp          int64          `gorm:"column:timestamp" json:"timestamp"`
+}
+
+func (ERC721Bridge) TableName() string {
+	return "erc721_bridge"
+}
+
+type ERC721BridgeDB interface {
+	ERC721BridgeView
+	StoreERC721Bridges([]ERC721Bridge) error
+	MarkERC721DepositsFinalized([]ERC721Bridge) error
+	MarkERC721WithdrawalsProven([]ERC721Bridge) error
+	MarkERC721WithdrawalsFinalized([]ERC721Bridge) error
+	SetERC721ChallengeDeadlines(finalizationPeriod uint64) (int64, error)
+	RollbackL1ERC721Bridges(l1Height *big.Int) error
+	RollbackL2ERC721Bridges(l2Height *big.Int) error
+	DeleteL1ERC721Bridges(l1From, l1To *big.Int) (int64, error)
+	DeleteL2ERC721Bridges(l2From, l2To *big.Int) (int64, error)
+}
+
+type ERC721BridgeView interface {
+	ERC721BridgeList(direction string, address string, page int, pageSize int, order string) ([]ERC721Bridge, int64)
+	ERC721BridgeByMessageHash(common.Hash) (*ERC721Bridge, error)
+}
+
+type erc721BridgeDB struct {
+	gorm *gorm.DB
+}
+
+func NewERC721BridgeDB(db *gorm.DB) ERC721BridgeDB {
+	return &erc721BridgeDB{gorm: db}
+}
+
+func (db erc721BridgeDB) StoreERC721Bridges(bridges []ERC721Bridge) error {
+	result := db.gorm.CreateInBatches(&bridges, len(bridges))
+	return result.Error
+}
+
+func (db erc721BridgeDB) ERC721BridgeList(direction string, address string, page int, pageSize int, order string) ([]ERC721Bridge, int64) {
+	var totalRecord int64
+	var bridges []ERC721Bridge
+	query := db.gorm.Table("erc721_bridge").Where("direction = ?", direction)
+	if address != "0x00" {
+		query = query.Where("from_address = ? OR to_address = ?", address, address)
+	}
+	if err := query.Session(&gorm.Session{}).Count(&totalRecord).Error; err != nil {
+		log.Error("get erc721 bridge count fail", "err", err)
+	}
+	query = query.Offset((page - 1) * pageSize).Limit(pageSize)
+	if strings.ToLower(order) == "asc" {
+		query = query.Order("timestamp asc")
+	} else {
+		query = query.Order("timestamp desc")
+	}
+	if err := query.Find(&bridges).Error; err != nil {
+		log.Error("get erc721 bridge list fail", "err", err)
+	}
+	return bridges, totalRecord
+}
+
+func (db erc721BridgeDB) ERC721BridgeByMessageHash(messageHash common.Hash) (*ERC721Bridge, error) {
+	var bridge ERC721Bridge
+	result := db.gorm.Where(&ERC721Bridge{MessageHash: messageHash}).Take(&bridge)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &bridge, nil
+}
+
+// MarkERC721DepositsFinalized claims the deposits by message hash with the L2 finalization
+func (db erc721BridgeDB) MarkERC721DepositsFinalized(bridges []ERC721Bridge) error {
+	for i := range bridges {
+		result := db.gorm.Model(&ERC721Bridge{}).Where("direction = ? AND message_hash = ?", ERC721Deposit, bridges[i].MessageHash.String()).
+			Updates(map[string]interface{}{"status": common3.L1ToL2Claimed, "l2_block_number": bridges[i].L2BlockNumber, "l2_transaction_hash": bridges[i].L2TransactionHash.String()})
+		if result.Error != nil {
+			return result.Error
+		}
+	}
+	return nil
+}
+
+// MarkERC721WithdrawalsProven moves the withdrawals by withdrawal hash into their challenge period
+func (db erc721BridgeDB) MarkERC721WithdrawalsProven(bridges []ERC721Bridge) error {
+	for i := range bridges {
+		result := db.gorm.Model(&ERC721Bridge{}).Where("direction = ? AND withdrawal_hash = ? AND status = ?", ERC721Withdrawal, bridges[i].WithdrawalHash.String(), common3.L2ToL1Pending).
+			Updates(map[string]interface{}{"status": common3.L2ToL1InChallengePeriod, "l1_prove_block_number": bridges[i].L1ProveBlockNumber, "l1_prove_tx_hash": bridges[i].L1ProveTxHash.String(),
+				"l1_proven_timestamp": bridges[i].L1ProvenTimestamp})
+		if result.Error != nil {
+			return result.Error
+		}
+	}
+	return nil
+}
+
+// MarkERC721WithdrawalsFinalized claims the withdrawals by message hash with the L1 finalization
+func (db erc721BridgeDB) MarkERC721WithdrawalsFinalized(bridges []ERC721Bridge) error {
+	for i := range bridges {
+		result := db.gorm.Model(&ERC721Bridge{}).Where("direction = ? AND message_hash = ?", ERC721Withdrawal, bridges[i].MessageHash.String()).
+			Updates(map[string]interface{}{"status": common3.L2ToL1Claimed, "l1_block_number": bridges[i].L1BlockNumber, "l1_transaction_hash": bridges[i].L1TransactionHash.String()})
+		if result.Error != nil {
+			return result.Error
+		}
+	}
+	return nil
+}
+
+// SetERC721ChallengeDeadlines sets the challenge deadline of the proven withdrawals without one, the finalization
+// period being read by the business processor
+func (db erc721BridgeDB) SetERC721ChallengeDeadlines(finalizationPeriod uint64) (int64, error) {
+	result := db.gorm.Model(&ERC721Bridge{}).Where("direction = ? AND challenge_deadline = ? AND l1_proven_timestamp > ?", ERC721Withdrawal, 0, 0).
+		Updates(map[string]interface{}{"challenge_deadline": gorm.Expr("l1_proven_timestamp + ?", finalizationPeriod)})
+	return result.RowsAffected, result.Error
+}
+
+// RollbackL1ERC721Bridges removes the deposits initiated above the supplied L1 height and
+// moves the withdrawals proven or finalized above it back to their previous status.
+func (db erc721BridgeDB) RollbackL1ERC721Bridges(l1Height *big.Int) error {
+	_, err := db.deleteL1(new(big.Int).Add(l1Height, big.NewInt(1)), nil)
+	return err
+}
+
+// RollbackL2ERC721Bridges removes the withdrawals initiated above the supplied L2 height and
+// moves the deposits finalized above it back to pending.
+func (db erc721BridgeDB) RollbackL2ERC721Bridges(l2Height *big.Int) error {
+	_, err := db.deleteL2(new(big.Int).Add(l2Height, big.NewInt(1)), nil)
+	return err
+}
+
+// DeleteL1ERC721Bridges is the ranged counterpart of RollbackL1ERC721Bridges, returning the deleted deposits
+func (db erc721BridgeDB) DeleteL1ERC721Bridges(l1From, l1To *big.Int) (int64, error) {
+	return db.deleteL1(l1From, l1To)
+}
+
+// DeleteL2ERC721Bridges is the ranged counterpart of RollbackL2ERC721Bridges, returning the deleted withdrawals
+func (db erc721BridgeDB) DeleteL2ERC721Bridges(l2From, l2To *big.Int) (int64, error) {
+	return db.deleteL2(l2From, l2To)
+}
+
+func (db erc721BridgeDB) deleteL1(l1From, l1To *big.Int) (int64, error) {
+	inRange := func(column string) *gorm.DB {
+		query := db.gorm.Model(&ERC721Bridge{}).Where(column+" >= ?", l1From)
+		if l1To != nil {
+			query = query.Where(column+" <= ?", l1To)
+		}
+		return query
+	}
+
+	finalized := inRange("l1_block_number").Where("direction = ? AND status = ?", ERC721Withdrawal, common3.L2ToL1Claimed).
+		Updates(map[string]interface{}{"status": gorm.Expr("CASE WHEN l1_prove_block_number > 0 THEN ? ELSE ? END", common3.L2ToL1InChallengePeriod, common3.L2ToL1Pending),
+			"l1_block_number": 0, "l1_transaction_hash": common.Hash{}.String()})
+	if finalized.Error != nil {
+		return 0, finalized.Error
+	}
+	proven := inRange("l1_prove_block_number").Where("direction = ? AND status = ?", ERC721Withdrawal, common3.L2ToL1InChallengePeriod).
+		Updates(map[string]interface{}{"status": common3.L2ToL1Pending, "l1_prove_block_number": 0, "l1_prove_tx_hash": common.Hash{}.String(),
+			"l1_proven_timestamp": 0, "challenge_deadline": 0})
+	if proven.Error != nil {
+		return 0, proven.Error
+	}
+	deleted := inRange("l1_block_number").Where("direction = ?", ERC721Deposit).Delete(&ERC721Bridge{})
+	return deleted.RowsAffected, deleted.Error
+}
+
+func (db erc721BridgeDB) deleteL2(l2From, l2To *big.Int) (int64, error) {
+	inRange := func() *gorm.DB {
+		query := db.gorm.Model(&ERC721Bridge{}).Where("l2_block_number >= ?", l2From)
+		if l2To != nil {
+			query = query.Where("l2_block_number <= ?", l2To)
+		}
+		return query
+	}
+
+	finalized := inRange().Where("direction = ? AND status = ?", ERC721Deposit, common3.L1ToL2Claimed).
+		Updates(map[string]interface{}{"status": common3.L1ToL2Pending, "l2_block_number": 0, "l2_transaction_hash": common.Hash{}.String()})
+	if finalized.Error != nil {
+		return 0, finalized.Error
+	}
+	deleted := inRange().Where("direction = ?", ERC721Withdrawal).Delete(&ERC721Bridge{})
+	return deleted.RowsAffected, deleted.Error
+}
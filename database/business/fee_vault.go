@@ -0,0 +1,198 @@
+package business
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Periods of the fee vault revenue series
+const (
+	FeeVaultPeriodDay   = "day"
+	FeeVaultPeriodMonth = "month"
+)
+
+// FeeVaultWithdrawal is a Withdrawal event of a fee vault predeploy. The withdrawn fees are bridged
+// to L1, WithdrawalHash links the withdrawal to its l2_to_l1 record.
+type FeeVaultWithdrawal struct {
+	GUID              uuid.UUID      `gorm:"primaryKey" json:"guid"`
+	VaultAddress      common.Address `gorm:"serializer:bytes;column:vault_address" json:"vaultAddress"`
+	L2BlockNumber     *big.Int       `gorm:"serializer:u256;column:l2_block_number" json:"l2BlockNumber"`
+	L2TransactionHash common.Hash    `gorm:"serializer:bytes;column:l2_transaction_hash" json:"l2TransactionHash"`
+	LogIndex          uint64         `gorm:"column:log_index" json:"logIndex"`
+	WithdrawalHash    common.Hash    `gorm:"serializer:bytes;column:withdrawal_hash" json:"withdrawalHash"`
+	Value             *big.Int       `gorm:"serializer:u256;column:value" json:"value"`
+	FromAddress       common.Address `gorm:"serializer:bytes;column:from_address" json:"fromAddress"`
+	ToAddress         common.Address `gorm:"serializer:bytes;column:to_address" json:"toAddress"`
+	Timestamp         int64          `gorm:"column:timestamp" json:"timestamp"`
+}
+
+func (FeeVaultWithdrawal) TableName() string {
+	return "fee_vault_withdrawal"
+}
+
+// FeeVaultBalance is the balance of a fee vault sampled at an L2 block
+type FeeVaultBalance struct {
+	VaultAddress  common.Address `gorm:"primaryKey;serializer:bytes;column:vault_address" json:"vaultAddress"`
+	L2BlockNumber *big.Int       `gorm:"primaryKey;serializer:u256;column:l2_block_number" json:"l2BlockNumber"`
+	Balance       *big.Int       `gorm:"serializer:u256;column:balance" json:"balance"`
+	Timestamp     int64          `gorm:"column:timestamp" json:"timestamp"`
+}
+
+func (FeeVaultBalance) TableName() string {
+	return "fee_vault_balance"
+}
+
+// FeeVaultPeriodAmount is an amount aggregated over the period starting at the Period timestamp
+type FeeVaultPeriodAmount struct {
+	Period int64    `gorm:"column:period" json:"period"`
+	Amount *big.Int `gorm:"serializer:u256;column:amount" json:"amount"`
+}
+
+type FeeVaultDB interface {
+	FeeVaultView
+	StoreFeeVaultWithdrawals([]FeeVaultWithdrawal) error
+	StoreFeeVaultBalances([]FeeVaultBalance) error
+	RollbackFeeVaults(l2Height *big.Int) error
+	DeleteFeeVaultWithdrawals(l2From, l2To *big.Int) (int64, error)
+	DeleteFeeVaultBalances(l2From, l2To *big.Int) (int64, error)
+}
+
+type FeeVaultView interface {
+	FeeVaultWithdrawalList(vault common.Address, page int, pageSize int, order string) ([]FeeVaultWithdrawal, int64)
+	FeeVaultLatestBalances() ([]FeeVaultBalance, error)
+	FeeVaultBalanceBefore(vault common.Address, timestamp int64) (*FeeVaultBalance, error)
+	FeeVaultBalanceFrom(vault common.Address, timestamp int64) (*FeeVaultBalance, error)
+	FeeVaultClosingBalances(vault common.Address, period string, fromTimestamp, toTimestamp int64) ([]FeeVaultPeriodAmount, error)
+	FeeVaultPeriodWithdrawals(vault common.Address, period string, fromTimestamp, toTimestamp int64) ([]FeeVaultPeriodAmount, error)
+}
+
+type feeVaultDB struct {
+	gorm *gorm.DB
+}
+
+func NewFeeVaultDB(db *gorm.DB) FeeVaultDB {
+	return &feeVaultDB{gorm: db}
+}
+
+func (db feeVaultDB) StoreFeeVaultWithdrawals(withdrawals []FeeVaultWithdrawal) error {
+	result := db.gorm.CreateInBatches(&withdrawals, len(withdrawals))
+	return result.Error
+}
+
+func (db feeVaultDB) StoreFeeVaultBalances(balances []FeeVaultBalance) error {
+	result := db.gorm.CreateInBatches(&balances, len(balances))
+	return result.Error
+}
+
+func (db feeVaultDB) FeeVaultWithdrawalList(vault common.Address, page int, pageSize int, order string) ([]FeeVaultWithdrawal, int64) {
+	var totalRecord int64
+	var withdrawals []FeeVaultWithdrawal
+	query := db.gorm.Table("fee_vault_withdrawal")
+	if vault != (common.Address{}) {
+		query = query.Where("vault_address = ?", strings.ToLower(vault.String()))
+	}
+	if err := query.Count(&totalRecord).Error; err != nil {
+		log.Error("get fee vault withdrawal count fail", "err", err)
+	}
+	query = query.Offset((page - 1) * pageSize).Limit(pageSize)
+	if strings.ToLower(order) == "asc" {
+		query = query.Order("l2_block_number asc, log_index asc")
+	} else {
+		query = query.Order("l2_block_number desc, log_index desc")
+	}
+	if err := query.Find(&withdrawals).Error; err != nil {
+		log.Error("get fee vault withdrawal list fail", "err", err)
+	}
+	return withdrawals, totalRecord
+}
+
+// FeeVaultLatestBalances returns the last sampled balance of every fee vault
+func (db feeVaultDB) FeeVaultLatestBalances() ([]FeeVaultBalance, error) {
+	var balances []FeeVaultBalance
+	result := db.gorm.Model(&FeeVaultBalance{}).
+		Select("DISTINCT ON (vault_address) *").
+		Order("vault_address ASC, l2_block_number DESC").
+		Find(&balances)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return balances, nil
+}
+
+// FeeVaultBalanceBefore returns the last balance of the vault sampled before the timestamp
+func (db feeVaultDB) FeeVaultBalanceBefore(vault common.Address, timestamp int64) (*FeeVaultBalance, error) {
+	return db.balance(db.gorm.Where("vault_address = ? AND timestamp < ?", strings.ToLower(vault.String()), timestamp).Order("l2_block_number DESC"))
+}
+
+// FeeVaultBalanceFrom returns the first balance of the vault sampled at or after the timestamp
+func (db feeVaultDB) FeeVaultBalanceFrom(vault common.Address, timestamp int64) (*FeeVaultBalance, error) {
+	return db.balance(db.gorm.Where("vault_address = ? AND timestamp >= ?", strings.ToLower(vault.String()), timestamp).Order("l2_block_number ASC"))
+}
+
+func (db feeVaultDB) balance(query *gorm.DB) (*FeeVaultBalance, error) {
+	var balance FeeVaultBalance
+	result := query.Take(&balance)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &balance, nil
+}
+
+// FeeVaultClosingBalances returns, for every period of the [from, to] range, the last balance of the vault sampled within it
+func (db feeVaultDB) FeeVaultClosingBalances(vault common.Address, period string, fromTimestamp, toTimestamp int64) ([]FeeVaultPeriodAmount, error) {
+	var balances []FeeVaultPeriodAmount
+	result := db.gorm.Table("fee_vault_balance").
+		Select("DISTINCT ON (period) "+periodColumn+" AS period, balance AS amount", period).
+		Where("vault_address = ? AND timestamp >= ? AND timestamp <= ?", strings.ToLower(vault.String()), fromTimestamp, toTimestamp).
+		Order("period ASC, l2_block_number DESC").
+		Find(&balances)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return balances, nil
+}
+
+// FeeVaultPeriodWithdrawals returns, for every period of the [from, to] range, the total withdrawn from the vault within it
+func (db feeVaultDB) FeeVaultPeriodWithdrawals(vault common.Address, period string, fromTimestamp, toTimestamp int64) ([]FeeVaultPeriodAmount, error) {
+	var withdrawals []FeeVaultPeriodAmount
+	result := db.gorm.Table("fee_vault_withdrawal").
+		Select(periodColumn+" AS period, SUM(value) AS amount", period).
+		Where("vault_address = ? AND timestamp >= ? AND timestamp <= ?", strings.ToLower(vault.String()), fromTimestamp, toTimestamp).
+		Group("period").
+		Order("period ASC").
+		Find(&withdrawals)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return withdrawals, nil
+}
+
+// periodColumn truncates the timestamp to the start of its UTC day or month
+const periodColumn = "EXTRACT(EPOCH FROM date_trunc(?, to_timestamp(timestamp) AT TIME ZONE 'UTC'))::BIGINT"
+
+func (db feeVaultDB) RollbackFeeVaults(l2Height *big.Int) error {
+	if err := db.gorm.Where("l2_block_number > ?", l2Height).Delete(&FeeVaultWithdrawal{}).Error; err != nil {
+		return err
+	}
+	return db.gorm.Where("l2_block_number > ?", l2Height).Delete(&FeeVaultBalance{}).Error
+}
+
+func (db feeVaultDB) DeleteFeeVaultWithdrawals(l2From, l2To *big.Int) (int64, error) {
+	result := db.gorm.Where("l2_block_number >= ? AND l2_block_number <= ?", l2From, l2To).Delete(&FeeVaultWithdrawal{})
+	return result.RowsAffected, result.Error
+}
+
+func (db feeVaultDB) DeleteFeeVaultBalances(l2From, l2To *big.Int) (int64, error) {
+	result := db.gorm.Where("l2_block_number >= ? AND l2_block_number <= ?", l2From, l2To).Delete(&FeeVaultBalance{})
+	return result.RowsAffected, result.Error
+}
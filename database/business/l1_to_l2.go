@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/google/uuid"
 
@@ -36,6 +37,11 @@ type L1ToL2 struct {
 	GasLimit              *big.Int       `gorm:"serializer:u256;column:gas_limit" json:"gasLimit"`
 	Version               int64          `gorm:"column:version" json:"version"`
 	Timestamp             int64          `gorm:"column:timestamp" db:"timestamp" json:"timestamp" form:"timestamp"`
+	// The deposit transaction derived from the TransactionDeposited event and its receipt on L2, unset for the legacy deposits
+	L2DepositTransactionHash *common.Hash `gorm:"column:l2_deposit_transaction_hash;serializer:bytes" json:"l2DepositTransactionHash"`
+	L2DepositStatus          int          `gorm:"column:l2_deposit_status" json:"l2DepositStatus"`
+	L2DepositBlockNumber     *big.Int     `gorm:"serializer:u256;column:l2_deposit_block_number" json:"l2DepositBlockNumber"`
+	L2DepositGasUsed         *big.Int     `gorm:"serializer:u256;column:l2_deposit_gas_used" json:"l2DepositGasUsed"`
 }
 
 type L1ToL2s []*L1ToL2
@@ -52,19 +58,28 @@ type L1ToL2DB interface {
 	MarkL1ToL2TransactionDepositFinalized(l1l2List []L1ToL2) error
 	RelayedL1ToL2Transaction(l1L2List []L1ToL2) error
 	FinalizedL1ToL2Transaction(l1L2List []L1ToL2) error
+	RollbackL1ToL2Transactions(l1Height *big.Int) error
+	RollbackL1ToL2Relayed(l2Height *big.Int) error
+	DeleteL1ToL2Transactions(l1From, l1To *big.Int) (int64, error)
+	ResetL1ToL2Relayed(l2From, l2To *big.Int) (int64, error)
+	UpdateL1ToL2RelayStatus() error
+	ConfirmL1ToL2Deposit(l1ToL2 L1ToL2) error
+	MarkL1ToL2DepositMissing(guid uuid.UUID) error
+	RollbackL1ToL2DepositReceipts(l2Height *big.Int) error
+	ResetL1ToL2DepositReceipts(l2From, l2To *big.Int) (int64, error)
 }
 
 type L1ToL2View interface {
 	GetBlockNumberFromHash(blockHash common.Hash) (*big.Int, error)
 	GetL2BlockNumberFromHash(blockHash common.Hash) (*big.Int, error)
-	L1L2LatestL1BlockHeader() (*common2.L1BlockHeader, error)
 	L1L2LatestL2BlockHeader() (*common2.L2BlockHeader, error)
-	L1L2LatestFinalizedL2BlockHeader() (*common2.L2BlockHeader, error)
 	L1ToL2List(string, int, int, string) ([]L1ToL2, int64)
 	L1ToL2TransactionDeposit(common.Hash) (*L1ToL2, error)
 	L1ToL2Transaction(common.Hash) (*L1ToL2, error)
+	L1ToL2sByL1TransactionHash(common.Hash) ([]L1ToL2, error)
 	L1L2LatestTimestamp() int
 	GetDepositsAmountByTimestamp(startTimestamp int, endTimestamp int) (L1ToL2s, error)
+	UnconfirmedL1ToL2Deposits(limit int) ([]L1ToL2, error)
 }
 
 /**
@@ -81,7 +96,21 @@ func NewL1ToL2DB(db *gorm.DB) L1ToL2DB {
 
 func (l1l2 l1ToL2DB) StoreL1ToL2Transactions(l1L2List []L1ToL2) error {
 	result := l1l2.gorm.CreateInBatches(&l1L2List, len(l1L2List))
-	return result.Error
+	if result.Error != nil {
+		return result.Error
+	}
+	history := make([]BridgeStatusHistory, len(l1L2List))
+	for i := range l1L2List {
+		history[i] = BridgeStatusHistory{
+			BridgeGUID:      l1L2List[i].GUID,
+			ToStatus:        l1L2List[i].Status,
+			Chain:           common2.SyncCursorLayerL1,
+			BlockNumber:     l1L2List[i].L1BlockNumber,
+			TransactionHash: &l1L2List[i].L1TransactionHash,
+			Timestamp:       l1L2List[i].Timestamp,
+		}
+	}
+	return depositLifecycle.initiated(l1l2.gorm, history)
 }
 
 func (l1l2 l1ToL2DB) L1ToL2List(address string, page int, pageSize int, order string) (l1l2List []L1ToL2, total int64) {
@@ -125,49 +154,44 @@ func (l1l2 l1ToL2DB) L1ToL2TransactionDeposit(messageHash common.Hash) (*L1ToL2,
 	return &l1ToL2Withdrawal, nil
 }
 
+// L1ToL2sByL1TransactionHash returns the deposits initiated by the L1 transaction
+func (l1l2 l1ToL2DB) L1ToL2sByL1TransactionHash(txHash common.Hash) ([]L1ToL2, error) {
+	var l1ToL2List []L1ToL2
+	result := l1l2.gorm.Where("l1_transaction_hash = ?", txHash.String()).Order("l1_block_number ASC").Find(&l1ToL2List)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return l1ToL2List, nil
+}
+
+// l1ToL2InfoColumns are the columns of a deposit completed from the events of its bridge, the status is only
+// moved by the transitions of the deposit lifecycle
+var l1ToL2InfoColumns = []string{"l1_token_address", "l2_token_address", "from_address", "to_address", "erc20_amount"}
+
 func (l1l2 l1ToL2DB) UpdateL1ToL2InfoByTxHash(l1L2List []L1ToL2) error {
 	for i := 0; i < len(l1L2List); i++ {
-		var l1ToL2 = L1ToL2{}
-		result := l1l2.gorm.Where(&L1ToL2{L1TransactionHash: l1L2List[i].L1TransactionHash}).Take(&l1ToL2)
+		result := l1l2.gorm.Model(&L1ToL2{}).Where("l1_transaction_hash = ?", l1L2List[i].L1TransactionHash.String()).
+			Select(l1ToL2InfoColumns).Updates(&l1L2List[i])
 		if result.Error != nil {
-			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-				return nil
-			}
 			return result.Error
 		}
-		l1ToL2.L1TokenAddress = l1L2List[i].L1TokenAddress
-		l1ToL2.L2TokenAddress = l1L2List[i].L2TokenAddress
-		l1ToL2.FromAddress = l1L2List[i].FromAddress
-		l1ToL2.ToAddress = l1L2List[i].ToAddress
-		l1ToL2.ERC20Amount = l1L2List[i].ERC20Amount
-		err := l1l2.gorm.Save(&l1ToL2).Error
-		if err != nil {
-			return err
-		}
 	}
 	return nil
 }
 
 func (l1l2 l1ToL2DB) UpdateMessageHashByTxHash(l1L2List []L1ToL2) error {
 	for i := 0; i < len(l1L2List); i++ {
-		var l1ToL2 = L1ToL2{}
-		result := l1l2.gorm.Where(&L1ToL2{L1TransactionHash: l1L2List[i].L1TransactionHash}).Take(&l1ToL2)
+		result := l1l2.gorm.Model(&L1ToL2{}).Where("l1_transaction_hash = ?", l1L2List[i].L1TransactionHash.String()).
+			Select("message_hash").Updates(&l1L2List[i])
 		if result.Error != nil {
-			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-				return nil
-			}
 			return result.Error
 		}
-		l1ToL2.MessageHash = l1L2List[i].MessageHash
-		err := l1l2.gorm.Save(&l1ToL2).Error
-		if err != nil {
-			return err
-		}
 	}
 	return nil
 }
 
 func (l1l2 l1ToL2DB) MarkL1ToL2TransactionDepositFinalized(L1l2List []L1ToL2) error {
+	var rejected rejections
 	for i := 0; i < len(L1l2List); i++ {
 		var l1ToL2 = L1ToL2{}
 		if L1l2List[i].L1BlockNumber.Uint64() <= 0 {
@@ -176,61 +200,51 @@ func (l1l2 l1ToL2DB) MarkL1ToL2TransactionDepositFinalized(L1l2List []L1ToL2) er
 		result := l1l2.gorm.Where(&L2ToL1{MessageHash: L1l2List[i].MessageHash}).Take(&l1ToL2)
 		if result.Error != nil {
 			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-				return nil
+				return rejected.err()
 			}
 			return result.Error
 		}
 		log.Info("mark transaction finalized", "L1BlockNumber", L1l2List[i].L1BlockNumber, "L1TransactionHash", L1l2List[i].L1TransactionHash)
-		l1ToL2.L2BlockNumber = L1l2List[i].L2BlockNumber
-		l1ToL2.L2TransactionHash = L1l2List[i].L2TransactionHash
-		l1ToL2.Status = common3.L1ToL2Claimed // relayed
-		err := l1l2.gorm.Save(&l1ToL2).Error
-		if err != nil {
+		_, err := depositLifecycle.transition(l1l2.gorm, byGUID(l1ToL2.GUID), common3.L1ToL2Claimed,
+			map[string]interface{}{"l2_block_number": L1l2List[i].L2BlockNumber, "l2_transaction_hash": L1l2List[i].L2TransactionHash.String()},
+			statusCause{reason: "relayed", chain: common2.SyncCursorLayerL2, blockNumber: L1l2List[i].L2BlockNumber, transactionHash: &L1l2List[i].L2TransactionHash})
+		if err := rejected.check(err); err != nil {
 			return err
 		}
 	}
-	return nil
+	return rejected.err()
 }
 
 func (l1l2 l1ToL2DB) RelayedL1ToL2Transaction(l1L2List []L1ToL2) error {
 	for i := 0; i < len(l1L2List); i++ {
-		var l1ToL2 = L1ToL2{}
-		result := l1l2.gorm.Where(&L1ToL2{MessageHash: l1L2List[i].MessageHash}).Take(&l1ToL2)
+		result := l1l2.gorm.Model(&L1ToL2{}).Where("message_hash = ?", l1L2List[i].MessageHash.String()).
+			Select("l2_transaction_hash").Updates(&l1L2List[i])
 		if result.Error != nil {
-			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-				return nil
-			}
 			return result.Error
 		}
-		l1ToL2.L2TransactionHash = l1L2List[i].L2TransactionHash
-		err := l1l2.gorm.Save(&l1ToL2).Error
-		if err != nil {
-			return err
-		}
 	}
 	return nil
 }
 
 func (l1l2 l1ToL2DB) FinalizedL1ToL2Transaction(l1L2List []L1ToL2) error {
+	var rejected rejections
 	for i := 0; i < len(l1L2List); i++ {
 		var l1ToL2 = L1ToL2{}
 		result := l1l2.gorm.Where(&L1ToL2{MessageHash: l1L2List[i].MessageHash}).Take(&l1ToL2)
 		if result.Error != nil {
 			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-				return nil
+				return rejected.err()
 			}
 			return result.Error
 		}
-		l1ToL2.L2TransactionHash = l1L2List[i].L2TransactionHash
-		l1ToL2.L2BlockNumber = l1L2List[i].L2BlockNumber
-		l1ToL2.Status = l1L2List[i].Status
-
-		err := l1l2.gorm.Save(&l1ToL2).Error
-		if err != nil {
+		_, err := depositLifecycle.transition(l1l2.gorm, byGUID(l1ToL2.GUID), l1L2List[i].Status,
+			map[string]interface{}{"l2_block_number": l1L2List[i].L2BlockNumber, "l2_transaction_hash": l1L2List[i].L2TransactionHash.String()},
+			statusCause{reason: "finalized", chain: common2.SyncCursorLayerL2, blockNumber: l1L2List[i].L2BlockNumber, transactionHash: &l1L2List[i].L2TransactionHash})
+		if err := rejected.check(err); err != nil {
 			return err
 		}
 	}
-	return nil
+	return rejected.err()
 }
 
 func (l1l2 l1ToL2DB) GetBlockNumberFromHash(blockHash common.Hash) (*big.Int, error) {
@@ -257,19 +271,6 @@ func (l1l2 l1ToL2DB) GetL2BlockNumberFromHash(blockHash common.Hash) (*big.Int,
 	return new(big.Int).SetUint64(l2BlockNumber), nil
 }
 
-func (l1l2 *l1ToL2DB) L1L2LatestL1BlockHeader() (*common2.L1BlockHeader, error) {
-	l1Query := l1l2.gorm.Where("number = (?)", l1l2.gorm.Table("l1_to_l2").Select("MAX(l1_block_number)"))
-	var l1Header common2.L1BlockHeader
-	result := l1Query.Take(&l1Header)
-	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, nil
-		}
-		return nil, result.Error
-	}
-	return &l1Header, nil
-}
-
 func (l1l2 *l1ToL2DB) L1L2LatestL2BlockHeader() (*common2.L2BlockHeader, error) {
 	var l2Header common2.L2BlockHeader
 	result := l1l2.gorm.Table("l2_block_headers").Order("number desc").Limit(1).Take(&l2Header)
@@ -282,19 +283,6 @@ func (l1l2 *l1ToL2DB) L1L2LatestL2BlockHeader() (*common2.L2BlockHeader, error)
 	return &l2Header, nil
 }
 
-func (l1l2 *l1ToL2DB) L1L2LatestFinalizedL2BlockHeader() (*common2.L2BlockHeader, error) {
-	l1Query := l1l2.gorm.Where("number = (?)", l1l2.gorm.Table("l1_to_l2").Where("status = (?)", common3.L1ToL2Claimed).Select("MAX(l2_block_number)"))
-	var l2Header common2.L2BlockHeader
-	result := l1Query.Take(&l2Header)
-	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, nil
-		}
-		return nil, result.Error
-	}
-	return &l2Header, nil
-}
-
 func (l1l2 *l1ToL2DB) L1ToL2Transaction(msgHash common.Hash) (*L1ToL2, error) {
 	var l1tol2Tx L1ToL2
 	filterMessageHash := L1ToL2{MessageHash: msgHash}
@@ -329,3 +317,170 @@ func (l1l2 *l1ToL2DB) GetDepositsAmountByTimestamp(startTimestamp int, endTimest
 
 	return l1Tol2s, nil
 }
+
+func (l1l2 l1ToL2DB) RollbackL1ToL2Transactions(l1Height *big.Int) error {
+	if err := depositLifecycle.deleteHistory(l1l2.gorm, func(db *gorm.DB) *gorm.DB { return db.Where("l1_block_number > ?", l1Height) }); err != nil {
+		return err
+	}
+	result := l1l2.gorm.Where("l1_block_number > ?", l1Height).Delete(&L1ToL2{})
+	return result.Error
+}
+
+// RollbackL1ToL2Relayed moves deposits relayed, or whose relay failed, above the supplied L2 height back to pending.
+// Must be called before the relay messages and attempts themselves are rolled back.
+func (l1l2 l1ToL2DB) RollbackL1ToL2Relayed(l2Height *big.Int) error {
+	_, err := l1l2.resetRelayed("block_number > ?", l2Height)
+	return err
+}
+
+func (l1l2 l1ToL2DB) DeleteL1ToL2Transactions(l1From, l1To *big.Int) (int64, error) {
+	if err := depositLifecycle.deleteHistory(l1l2.gorm, func(db *gorm.DB) *gorm.DB {
+		return db.Where("l1_block_number >= ? AND l1_block_number <= ?", l1From, l1To)
+	}); err != nil {
+		return 0, err
+	}
+	result := l1l2.gorm.Where("l1_block_number >= ? AND l1_block_number <= ?", l1From, l1To).Delete(&L1ToL2{})
+	return result.RowsAffected, result.Error
+}
+
+// ResetL1ToL2Relayed moves deposits relayed, or whose relay failed, within the supplied L2 range back to pending.
+// Must be called before the relay messages and attempts themselves are deleted.
+func (l1l2 l1ToL2DB) ResetL1ToL2Relayed(l2From, l2To *big.Int) (int64, error) {
+	return l1l2.resetRelayed("block_number >= ? AND block_number <= ?", l2From, l2To)
+}
+
+func (l1l2 l1ToL2DB) resetRelayed(blockRange string, args ...interface{}) (int64, error) {
+	relayed := l1l2.gorm.Table("relay_message").Where(blockRange, args...).Select("relay_transaction_hash")
+	relayedAttempts := l1l2.gorm.Table("relay_attempt").Where("chain = ? AND success = ?", common2.SyncCursorLayerL2, true).Where(blockRange, args...).Select("transaction_hash")
+	var rejected rejections
+	claimed, err := depositLifecycle.transition(l1l2.gorm, func(db *gorm.DB) *gorm.DB {
+		return db.Where("status = ? AND (l2_transaction_hash IN (?) OR l2_transaction_hash IN (?))", common3.L1ToL2Claimed, relayed, relayedAttempts)
+	}, common3.L1ToL2Pending, nil, statusCause{reason: "relay rolled back", chain: common2.SyncCursorLayerL2})
+	if err := rejected.check(err); err != nil {
+		return 0, err
+	}
+	failedAttempts := l1l2.gorm.Table("relay_attempt").Where("chain = ? AND success = ?", common2.SyncCursorLayerL2, false).Where(blockRange, args...).Select("message_hash")
+	failed, err := depositLifecycle.transition(l1l2.gorm, func(db *gorm.DB) *gorm.DB {
+		return db.Where("status = ? AND message_hash IN (?)", common3.L1ToL2RelayFailed, failedAttempts)
+	}, common3.L1ToL2Pending, nil, statusCause{reason: "failed relay rolled back", chain: common2.SyncCursorLayerL2})
+	if err := rejected.check(err); err != nil {
+		return 0, err
+	}
+	return claimed + failed, rejected.err()
+}
+
+// UpdateL1ToL2RelayStatus derives the status of the deposits from their relay attempts on L2. A pending deposit whose
+// relay failed is marked relay failed until it is replayed successfully, a successful relay claims it.
+func (l1l2 l1ToL2DB) UpdateL1ToL2RelayStatus() error {
+	attempts := func(success bool) *gorm.DB {
+		return l1l2.gorm.Table("relay_attempt").Where("chain = ? AND success = ?", common2.SyncCursorLayerL2, success).Select("message_hash")
+	}
+	failedAttempt := func(column string) *clause.Expr {
+		expr := relayAttemptColumn("l1_to_l2", column, common2.SyncCursorLayerL2, false)
+		return &expr
+	}
+	var rejected rejections
+	_, err := depositLifecycle.transition(l1l2.gorm, func(db *gorm.DB) *gorm.DB {
+		return db.Where("status = ? AND message_hash IN (?) AND message_hash NOT IN (?)", common3.L1ToL2Pending, attempts(false), attempts(true))
+	}, common3.L1ToL2RelayFailed, nil, statusCause{reason: "relay failed", chain: common2.SyncCursorLayerL2, blockNumberExpr: failedAttempt("block_number"), transactionHashExpr: failedAttempt("transaction_hash")})
+	if err := rejected.check(err); err != nil {
+		return err
+	}
+	relayedAttempt := func(column string) clause.Expr {
+		return gorm.Expr("(?)", relayAttemptColumn("l1_to_l2", column, common2.SyncCursorLayerL2, true))
+	}
+	blockNumber, txHash := relayedAttempt("block_number"), relayedAttempt("transaction_hash")
+	_, err = depositLifecycle.transition(l1l2.gorm, func(db *gorm.DB) *gorm.DB {
+		return db.Where("status IN ? AND message_hash IN (?)", []int{common3.L1ToL2Pending, common3.L1ToL2RelayFailed}, attempts(true))
+	}, common3.L1ToL2Claimed, map[string]interface{}{"l2_block_number": blockNumber, "l2_transaction_hash": txHash},
+		statusCause{reason: "relayed", chain: common2.SyncCursorLayerL2, blockNumberExpr: &blockNumber, transactionHashExpr: &txHash})
+	if err := rejected.check(err); err != nil {
+		return err
+	}
+	// the failed attempts have been rolled back
+	_, err = depositLifecycle.transition(l1l2.gorm, func(db *gorm.DB) *gorm.DB {
+		return db.Where("status = ? AND message_hash NOT IN (?)", common3.L1ToL2RelayFailed, attempts(false))
+	}, common3.L1ToL2Pending, nil, statusCause{reason: "failed relay rolled back", chain: common2.SyncCursorLayerL2})
+	if err := rejected.check(err); err != nil {
+		return err
+	}
+	return rejected.err()
+}
+
+// UnconfirmedL1ToL2Deposits returns the deposits whose deposit transaction has no receipt on L2 yet, in deposit order
+func (l1l2 l1ToL2DB) UnconfirmedL1ToL2Deposits(limit int) ([]L1ToL2, error) {
+	var l1ToL2s []L1ToL2
+	result := l1l2.gorm.Where("l2_deposit_status = ? AND l2_deposit_transaction_hash IS NOT NULL", common3.DepositUnconfirmed).
+		Order("l1_block_number ASC, timestamp ASC").Limit(limit).Find(&l1ToL2s)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return l1ToL2s, nil
+}
+
+// ConfirmL1ToL2Deposit stores the receipt of the deposit transaction. A deposit not relayed by the messenger has
+// no other transaction on L2, it is claimed by its deposit transaction or failed if the latter reverted.
+func (l1l2 l1ToL2DB) ConfirmL1ToL2Deposit(l1ToL2 L1ToL2) error {
+	result := l1l2.gorm.Model(&L1ToL2{}).Where("guid = ?", l1ToL2.GUID).
+		Updates(map[string]interface{}{"l2_deposit_status": l1ToL2.L2DepositStatus, "l2_deposit_block_number": l1ToL2.L2DepositBlockNumber, "l2_deposit_gas_used": l1ToL2.L2DepositGasUsed})
+	if result.Error != nil {
+		return result.Error
+	}
+	status, reason := int64(common3.L1ToL2Claimed), "deposit transaction executed"
+	if l1ToL2.L2DepositStatus == common3.DepositReverted {
+		status, reason = common3.L1ToL2DepositFailed, "deposit transaction reverted"
+	}
+	_, err := depositLifecycle.transition(l1l2.gorm, func(db *gorm.DB) *gorm.DB {
+		return db.Where("guid = ? AND status = ?", l1ToL2.GUID, common3.L1ToL2Pending).Scopes(notRelayedDeposits)
+	}, status, map[string]interface{}{"l2_transaction_hash": l1ToL2.L2DepositTransactionHash.String(), "l2_block_number": l1ToL2.L2DepositBlockNumber},
+		statusCause{reason: reason, chain: common2.SyncCursorLayerL2, blockNumber: l1ToL2.L2DepositBlockNumber, transactionHash: l1ToL2.L2DepositTransactionHash})
+	return err
+}
+
+// MarkL1ToL2DepositMissing flags the deposit whose deposit transaction is not found on L2, it isn't fetched anymore
+func (l1l2 l1ToL2DB) MarkL1ToL2DepositMissing(guid uuid.UUID) error {
+	result := l1l2.gorm.Model(&L1ToL2{}).Where("guid = ? AND l2_deposit_status = ?", guid, common3.DepositUnconfirmed).
+		Update("l2_deposit_status", common3.DepositMissing)
+	return result.Error
+}
+
+// RollbackL1ToL2DepositReceipts drops the receipts of the deposit transactions above the supplied L2 height, the
+// deposits not relayed by the messenger are moved back to pending
+func (l1l2 l1ToL2DB) RollbackL1ToL2DepositReceipts(l2Height *big.Int) error {
+	_, err := l1l2.resetDepositReceipts("l2_deposit_block_number > ?", l2Height)
+	return err
+}
+
+// ResetL1ToL2DepositReceipts drops the receipts of the deposit transactions within the supplied L2 range, the
+// deposits not relayed by the messenger are moved back to pending
+func (l1l2 l1ToL2DB) ResetL1ToL2DepositReceipts(l2From, l2To *big.Int) (int64, error) {
+	return l1l2.resetDepositReceipts("l2_deposit_block_number >= ? AND l2_deposit_block_number <= ?", l2From, l2To)
+}
+
+func (l1l2 l1ToL2DB) resetDepositReceipts(blockRange string, args ...interface{}) (int64, error) {
+	var rejected rejections
+	_, err := depositLifecycle.transition(l1l2.gorm, func(db *gorm.DB) *gorm.DB {
+		return db.Where("status IN ?", []int{common3.L1ToL2Claimed, common3.L1ToL2DepositFailed}).Where(blockRange, args...).Scopes(notRelayedDeposits)
+	}, common3.L1ToL2Pending, map[string]interface{}{"l2_transaction_hash": common.Hash{}.String(), "l2_block_number": 0},
+		statusCause{reason: "deposit transaction rolled back", chain: common2.SyncCursorLayerL2})
+	if err := rejected.check(err); err != nil {
+		return 0, err
+	}
+	result := l1l2.gorm.Model(&L1ToL2{}).Where(blockRange, args...).
+		Updates(map[string]interface{}{"l2_deposit_status": common3.DepositUnconfirmed, "l2_deposit_block_number": nil, "l2_deposit_gas_used": nil})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, rejected.err()
+}
+
+// notRelayedDeposits selects the plain portal deposits, sent without any message of the messenger
+func notRelayedDeposits(db *gorm.DB) *gorm.DB {
+	return db.Where("message_hash IS NULL OR message_hash = ?", common.Hash{}.String())
+}
+
+func byGUID(guid uuid.UUID) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("guid = ?", guid)
+	}
+}
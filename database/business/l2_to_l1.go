@@ -7,6 +7,7 @@ import (
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
@@ -15,6 +16,8 @@ import (
 	common2 "github.com/mantlenetworkio/lithosphere/database/common"
 )
 
+// L2ToL1 is a withdrawal. The proven timestamp and challenge deadline are zero until it is proven, it can be finalized
+// once the L1 timestamp is past the deadline. TimeLeft is not stored, it is computed from the deadline at query time.
 type L2ToL1 struct {
 	GUID                    uuid.UUID      `gorm:"primaryKey" json:"guid"`
 	L1BlockNumber           *big.Int       `gorm:"serializer:u256;column:l1_block_number" db:"l1_block_number" json:"l1BlockNumber" form:"l1_block_number"`
@@ -30,12 +33,15 @@ type L2ToL1 struct {
 	ETHAmount               *big.Int       `gorm:"serializer:u256;column:eth_amount" json:"ETHAmount"`
 	ERC20Amount             *big.Int       `gorm:"serializer:u256;column:erc20_amount" json:"ERC20Amount"`
 	GasLimit                *big.Int       `gorm:"serializer:u256;column:gas_limit" json:"gasLimit"`
-	TimeLeft                *big.Int       `gorm:"serializer:u256;column:time_left" json:"timeLeft"`
+	TimeLeft                *big.Int       `gorm:"-" json:"timeLeft"`
 	ToAddress               common.Address `gorm:"column:to_address;serializer:bytes" db:"to_address" json:"toAddress" form:"to_address"`
 	L1TokenAddress          common.Address `gorm:"column:l1_token_address;serializer:bytes" db:"l1_token_address" json:"l1TokenAddress" form:"l1_token_address"`
 	L2TokenAddress          common.Address `gorm:"column:l2_token_address;serializer:bytes" db:"l2_token_address" json:"l2TokenAddress" form:"l2_token_address"`
 	Version                 int64          `gorm:"column:version" json:"version"`
 	Timestamp               int64          `gorm:"column:timestamp" db:"timestamp" json:"timestamp" form:"timestamp"`
+	ReproveRequired         bool           `gorm:"column:reprove_required" db:"reprove_required" json:"reproveRequired" form:"reprove_required"`
+	L1ProvenTimestamp       int64          `gorm:"column:l1_proven_timestamp" db:"l1_proven_timestamp" json:"l1ProvenTimestamp" form:"l1_proven_timestamp"`
+	ChallengeDeadline       int64          `gorm:"column:challenge_deadline" db:"challenge_deadline" json:"challengeDeadline" form:"challenge_deadline"`
 }
 
 type L2ToL1s []*L2ToL1
@@ -49,21 +55,29 @@ type L2ToL1DB interface {
 	StoreL2ToL1Transactions([]L2ToL1) error
 	UpdateL2ToL1InfoByTxHash(l2L1List []L2ToL1) error
 	UpdateL2ToL1InfoByMessageHash(l2L1List []L2ToL1) error
-	UpdateReadyForProvedStatus(l2BlockNumber uint64, fraudProofWindows uint64) error
-	UpdateTimeLeft() error
+	UpdateReadyForProvedStatus(l2BlockNumber uint64) error
+	SetChallengeDeadlines(finalizationPeriod uint64) (int64, error)
 	MarkL2ToL1TransactionWithdrawalProven(l2L1List []L2ToL1) error
 	MarkL2ToL1TransactionWithdrawalFinalized(l2L1List []L2ToL1) error
 	MarkL2ToL1TransactionWithdrawalProvenV0(l2L1List []L2ToL1) error
 	MarkL2ToL1TransactionWithdrawalFinalizedV0(l2L1List []L2ToL1) error
 	UpdateV1L2Tol1WithdrawalHash(txHash common.Hash, withdrawHash common.Hash) error
 	GetWithdrawsUnclaimedAmount(l1FinalizeTxHash string) (L2ToL1s, error)
+	RollbackL2ToL1Transactions(l2Height *big.Int) error
+	RollbackL2ToL1Proven(l1Height *big.Int) error
+	RollbackL2ToL1Finalized(l1Height *big.Int) error
+	RollbackL2ToL1ReadyForProved(l2BlockNumber uint64) error
+	DeleteL2ToL1Transactions(l2From, l2To *big.Int) (int64, error)
+	ResetL2ToL1Proven(l1From, l1To *big.Int) (int64, error)
+	ResetL2ToL1Finalized(l1From, l1To *big.Int) (int64, error)
+	UpdateL2ToL1RelayStatus() error
+	InvalidateL2ToL1Proven(l2BlockNumber uint64) (int64, error)
 }
 
 type L2ToL1View interface {
 	L2ToL1List(string, int, int, string) ([]L2ToL1, int64)
 	L2ToL1TransactionWithdrawal(common.Hash) (*L2ToL1, error)
 	GetBlockNumberFromHash(blockHash common.Hash) (*big.Int, error)
-	L2L1LatestBlockL2Header() (*common2.L2BlockHeader, error)
 	L2L1LatestBlockL1Header() (*common2.L1BlockHeader, error)
 	L2L1LatestFinalizedBlockL1Header() (*common2.L1BlockHeader, error)
 	L2ToL1TransactionTxHash(common.Hash) (*L2ToL1, error)
@@ -81,7 +95,21 @@ func NewL21ToL1DB(db *gorm.DB) L2ToL1DB {
 
 func (l2l1 l2ToL1DB) StoreL2ToL1Transactions(l1L2List []L2ToL1) error {
 	result := l2l1.gorm.CreateInBatches(&l1L2List, len(l1L2List))
-	return result.Error
+	if result.Error != nil {
+		return result.Error
+	}
+	history := make([]BridgeStatusHistory, len(l1L2List))
+	for i := range l1L2List {
+		history[i] = BridgeStatusHistory{
+			BridgeGUID:      l1L2List[i].GUID,
+			ToStatus:        l1L2List[i].Status,
+			Chain:           common2.SyncCursorLayerL2,
+			BlockNumber:     l1L2List[i].L2BlockNumber,
+			TransactionHash: &l1L2List[i].L2TransactionHash,
+			Timestamp:       l1L2List[i].Timestamp,
+		}
+	}
+	return withdrawalLifecycle.initiated(l2l1.gorm, history)
 }
 
 func (l2l1 l2ToL1DB) L2ToL1List(address string, page int, pageSize int, order string) (l2L1List []L2ToL1, total int64) {
@@ -113,57 +141,34 @@ func (l2l1 l2ToL1DB) L2ToL1List(address string, page int, pageSize int, order st
 	return l2ToL1List, totalRecord
 }
 
+// l2ToL1InfoColumns are the columns of a withdrawal completed from the events of its bridge, the status is only
+// moved by the transitions of the withdrawal lifecycle
+var l2ToL1InfoColumns = []string{"l1_block_number", "from_address", "to_address", "eth_amount", "erc20_amount", "l1_token_address", "l2_token_address"}
+
 func (l2l1 l2ToL1DB) UpdateL2ToL1InfoByTxHash(l2L1List []L2ToL1) error {
 	for i := 0; i < len(l2L1List); i++ {
-		var l2ToL1 = L2ToL1{}
-		result := l2l1.gorm.Where(&L2ToL1{L2TransactionHash: l2L1List[i].L2TransactionHash}).Take(&l2ToL1)
+		result := l2l1.gorm.Model(&L2ToL1{}).Where("l2_transaction_hash = ?", l2L1List[i].L2TransactionHash.String()).
+			Select(l2ToL1InfoColumns).Updates(&l2L1List[i])
 		if result.Error != nil {
-			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-				return nil
-			}
 			return result.Error
 		}
-		l2ToL1.L1BlockNumber = l2L1List[i].L1BlockNumber
-		l2ToL1.FromAddress = l2L1List[i].FromAddress
-		l2ToL1.ToAddress = l2L1List[i].ToAddress
-		l2ToL1.ETHAmount = l2L1List[i].ETHAmount
-		l2ToL1.ERC20Amount = l2L1List[i].ERC20Amount
-		l2ToL1.L1TokenAddress = l2L1List[i].L1TokenAddress
-		l2ToL1.L2TokenAddress = l2L1List[i].L2TokenAddress
-		err := l2l1.gorm.Save(&l2ToL1).Error
-		if err != nil {
-			return err
-		}
 	}
 	return nil
 }
 
 func (l2l1 l2ToL1DB) UpdateL2ToL1InfoByMessageHash(l2L1List []L2ToL1) error {
 	for i := 0; i < len(l2L1List); i++ {
-		var l2ToL1 = L2ToL1{}
-		result := l2l1.gorm.Where(&L2ToL1{MessageHash: l2L1List[i].MessageHash}).Take(&l2ToL1)
+		result := l2l1.gorm.Model(&L2ToL1{}).Where("message_hash = ?", l2L1List[i].MessageHash.String()).
+			Select(l2ToL1InfoColumns).Updates(&l2L1List[i])
 		if result.Error != nil {
-			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-				return nil
-			}
 			return result.Error
 		}
-		l2ToL1.L1BlockNumber = l2L1List[i].L1BlockNumber
-		l2ToL1.FromAddress = l2L1List[i].FromAddress
-		l2ToL1.ToAddress = l2L1List[i].ToAddress
-		l2ToL1.ETHAmount = l2L1List[i].ETHAmount
-		l2ToL1.ERC20Amount = l2L1List[i].ERC20Amount
-		l2ToL1.L1TokenAddress = l2L1List[i].L1TokenAddress
-		l2ToL1.L2TokenAddress = l2L1List[i].L2TokenAddress
-		err := l2l1.gorm.Save(&l2ToL1).Error
-		if err != nil {
-			return err
-		}
 	}
 	return nil
 }
 
 func (l2l1 l2ToL1DB) MarkL2ToL1TransactionWithdrawalProven(l2L1List []L2ToL1) error {
+	var rejected rejections
 	for i := 0; i < len(l2L1List); i++ {
 		var l2ToL1 = L2ToL1{}
 		if l2L1List[i].L1BlockNumber.Uint64() <= 0 {
@@ -172,29 +177,22 @@ func (l2l1 l2ToL1DB) MarkL2ToL1TransactionWithdrawalProven(l2L1List []L2ToL1) er
 		result := l2l1.gorm.Where(&L2ToL1{WithdrawTransactionHash: l2L1List[i].WithdrawTransactionHash}).Take(&l2ToL1)
 		if result.Error != nil {
 			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-				return nil
+				return rejected.err()
 			}
 			return result.Error
 		}
 		log.Info("mark transaction prove", "L1BlockNumber",
 			l2L1List[i].L1BlockNumber, "L1ProveTxHash", l2L1List[i].L1ProveTxHash, "WithdrawTransactionHash",
 			l2L1List[i].WithdrawTransactionHash, "l2ToL1WithdrawTransactionHash", l2ToL1.WithdrawTransactionHash)
-		l2ToL1.L1BlockNumber = l2L1List[i].L1BlockNumber
-		l2ToL1.L1ProveTxHash = l2L1List[i].L1ProveTxHash
-		if l2ToL1.TimeLeft.Uint64() > 0 {
-			l2ToL1.Status = common3.L2ToL1InChallengePeriod // in challenge period
-		} else {
-			l2ToL1.Status = common3.L2ToL1ReadyForClaim // ready for claim
-		}
-		err := l2l1.gorm.Save(&l2ToL1).Error
-		if err != nil {
+		if err := rejected.check(l2l1.markProven(l2ToL1, l2L1List[i])); err != nil {
 			return err
 		}
 	}
-	return nil
+	return rejected.err()
 }
 
 func (l2l1 l2ToL1DB) MarkL2ToL1TransactionWithdrawalProvenV0(l2L1List []L2ToL1) error {
+	var rejected rejections
 	for i := 0; i < len(l2L1List); i++ {
 		var l2ToL1 = L2ToL1{}
 		if l2L1List[i].L1BlockNumber.Uint64() <= 0 {
@@ -203,29 +201,22 @@ func (l2l1 l2ToL1DB) MarkL2ToL1TransactionWithdrawalProvenV0(l2L1List []L2ToL1)
 		result := l2l1.gorm.Where(&L2ToL1{MessageHash: l2L1List[i].MessageHash}).Take(&l2ToL1)
 		if result.Error != nil {
 			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-				return nil
+				return rejected.err()
 			}
 			return result.Error
 		}
 		log.Info("mark transaction v0 prove", "L1BlockNumber",
 			l2L1List[i].L1BlockNumber, "L1ProveTxHash", l2L1List[i].L1ProveTxHash,
 			"WithdrawTransactionHash", l2L1List[i].WithdrawTransactionHash)
-		l2ToL1.L1BlockNumber = l2L1List[i].L1BlockNumber
-		l2ToL1.L1ProveTxHash = l2L1List[i].L1ProveTxHash
-		if l2ToL1.TimeLeft.Uint64() > 0 {
-			l2ToL1.Status = common3.L2ToL1InChallengePeriod // in challenge period
-		} else {
-			l2ToL1.Status = common3.L2ToL1ReadyForClaim // ready for claim
-		}
-		err := l2l1.gorm.Save(&l2ToL1).Error
-		if err != nil {
+		if err := rejected.check(l2l1.markProven(l2ToL1, l2L1List[i])); err != nil {
 			return err
 		}
 	}
-	return nil
+	return rejected.err()
 }
 
 func (l2l1 l2ToL1DB) MarkL2ToL1TransactionWithdrawalFinalized(l2L1List []L2ToL1) error {
+	var rejected rejections
 	for i := 0; i < len(l2L1List); i++ {
 		var l2ToL1 = L2ToL1{}
 		if l2L1List[i].L1BlockNumber.Uint64() <= 0 {
@@ -234,25 +225,22 @@ func (l2l1 l2ToL1DB) MarkL2ToL1TransactionWithdrawalFinalized(l2L1List []L2ToL1)
 		result := l2l1.gorm.Where(&L2ToL1{WithdrawTransactionHash: l2L1List[i].WithdrawTransactionHash}).Take(&l2ToL1)
 		if result.Error != nil {
 			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-				return nil
+				return rejected.err()
 			}
 			return result.Error
 		}
 		log.Info("mark transaction finalized", "L1BlockNumber",
 			l2L1List[i].L1BlockNumber, "L1FinalizeTxHash", l2L1List[i].L1FinalizeTxHash,
 			"WithdrawTransactionHash", l2L1List[i].WithdrawTransactionHash, "l2ToL1WithdrawHash", l2ToL1.WithdrawTransactionHash)
-		l2ToL1.L1BlockNumber = l2L1List[i].L1BlockNumber
-		l2ToL1.L1FinalizeTxHash = l2L1List[i].L1FinalizeTxHash
-		l2ToL1.Status = common3.L2ToL1Claimed // relayed
-		err := l2l1.gorm.Save(&l2ToL1).Error
-		if err != nil {
+		if err := rejected.check(l2l1.markFinalized(l2ToL1, l2L1List[i])); err != nil {
 			return err
 		}
 	}
-	return nil
+	return rejected.err()
 }
 
 func (l2l1 l2ToL1DB) MarkL2ToL1TransactionWithdrawalFinalizedV0(l2L1List []L2ToL1) error {
+	var rejected rejections
 	for i := 0; i < len(l2L1List); i++ {
 		var l2ToL1 = L2ToL1{}
 		if l2L1List[i].L1BlockNumber.Uint64() <= 0 {
@@ -261,49 +249,57 @@ func (l2l1 l2ToL1DB) MarkL2ToL1TransactionWithdrawalFinalizedV0(l2L1List []L2ToL
 		result := l2l1.gorm.Where(&L2ToL1{MessageHash: l2L1List[i].MessageHash}).Take(&l2ToL1)
 		if result.Error != nil {
 			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-				return nil
+				return rejected.err()
 			}
 			return result.Error
 		}
 		log.Info("mark transaction v0 finalized",
 			"L1BlockNumber", l2L1List[i].L1BlockNumber, "L1FinalizeTxHash", l2L1List[i].L1FinalizeTxHash,
 			"WithdrawTransactionHash", l2L1List[i].WithdrawTransactionHash)
-		l2ToL1.L1BlockNumber = l2L1List[i].L1BlockNumber
-		l2ToL1.L1FinalizeTxHash = l2L1List[i].L1FinalizeTxHash
-		l2ToL1.Status = common3.L2ToL1Claimed // relayed
-		err := l2l1.gorm.Save(&l2ToL1).Error
-		if err != nil {
+		if err := rejected.check(l2l1.markFinalized(l2ToL1, l2L1List[i])); err != nil {
 			return err
 		}
 	}
-	return nil
+	return rejected.err()
 }
 
-func (l2l1 l2ToL1DB) UpdateReadyForProvedStatus(l2BlockNumber uint64, fraudProofWindows uint64) error {
-	var l2ToL1 = L2ToL1{}
-	err := l2l1.gorm.Model(&l2ToL1).Where("l2_block_number <= ? AND status = ?", l2BlockNumber, 0).Updates(map[string]interface{}{"status": common3.L2ToL1ReadyForProved, "time_left": fraudProofWindows}).Error
-	if err != nil {
-		return err
-	}
-	return nil
+// markProven moves the withdrawal to the challenge period ending at the deadline of the proof. It stays there until
+// finalized, whether the period has elapsed is only computed at query time.
+func (l2l1 l2ToL1DB) markProven(l2ToL1 L2ToL1, proven L2ToL1) error {
+	_, err := withdrawalLifecycle.transition(l2l1.gorm, byGUID(l2ToL1.GUID), common3.L2ToL1InChallengePeriod,
+		map[string]interface{}{"l1_block_number": proven.L1BlockNumber, "l1_prove_tx_hash": proven.L1ProveTxHash.String(), "reprove_required": false,
+			"l1_proven_timestamp": proven.L1ProvenTimestamp, "challenge_deadline": proven.ChallengeDeadline},
+		statusCause{reason: "proven", chain: common2.SyncCursorLayerL1, blockNumber: proven.L1BlockNumber, transactionHash: &proven.L1ProveTxHash})
+	return err
 }
 
-func (l2l1 l2ToL1DB) UpdateTimeLeft() error {
-	result := l2l1.gorm.Model(&L2ToL1{}).Where("time_left > ? and status = ?", 0, common3.L2ToL1InChallengePeriod).Updates(map[string]interface{}{"time_left": gorm.Expr("GREATEST(time_left - 1, 0)")})
-	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil
-		}
-		return result.Error
-	}
-	result = l2l1.gorm.Model(&L2ToL1{}).Where("time_left = ? and status = ?", 0, common3.L2ToL1InChallengePeriod).Updates(map[string]interface{}{"status": common3.L2ToL1ReadyForClaim})
-	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil
-		}
-		return result.Error
+func (l2l1 l2ToL1DB) markFinalized(l2ToL1 L2ToL1, finalized L2ToL1) error {
+	_, err := withdrawalLifecycle.transition(l2l1.gorm, byGUID(l2ToL1.GUID), common3.L2ToL1Claimed,
+		map[string]interface{}{"l1_block_number": finalized.L1BlockNumber, "l1_finalize_tx_hash": finalized.L1FinalizeTxHash.String()},
+		statusCause{reason: "finalized", chain: common2.SyncCursorLayerL1, blockNumber: finalized.L1BlockNumber, transactionHash: &finalized.L1FinalizeTxHash})
+	return err
+}
+
+// UpdateReadyForProvedStatus moves the pending withdrawals covered by an output to ready for proved, the output
+// proposal is the cause of the transition
+func (l2l1 l2ToL1DB) UpdateReadyForProvedStatus(l2BlockNumber uint64) error {
+	output := func(column string) *clause.Expr {
+		expr := gorm.Expr("SELECT " + column + " FROM state_root WHERE canonical AND state_root.l2_block_number >= l2_to_l1.l2_block_number ORDER BY state_root.l2_block_number ASC LIMIT 1")
+		return &expr
 	}
-	return nil
+	_, err := withdrawalLifecycle.transition(l2l1.gorm, func(db *gorm.DB) *gorm.DB {
+		return db.Where("l2_block_number <= ? AND status = ?", l2BlockNumber, common3.L2ToL1Pending)
+	}, common3.L2ToL1ReadyForProved, nil,
+		statusCause{reason: "output proposed", chain: common2.SyncCursorLayerL1, blockNumberExpr: output("l1_block_number"), transactionHashExpr: output("transaction_hash")})
+	return err
+}
+
+// SetChallengeDeadlines sets the challenge deadline of the proven withdrawals without one, those proven before the
+// deadlines were stored
+func (l2l1 l2ToL1DB) SetChallengeDeadlines(finalizationPeriod uint64) (int64, error) {
+	result := l2l1.gorm.Model(&L2ToL1{}).Where("challenge_deadline = ? AND l1_proven_timestamp > ?", 0, 0).
+		Updates(map[string]interface{}{"challenge_deadline": gorm.Expr("l1_proven_timestamp + ?", finalizationPeriod)})
+	return result.RowsAffected, result.Error
 }
 
 func (l2l1 l2ToL1DB) GetBlockNumberFromHash(blockHash common.Hash) (*big.Int, error) {
@@ -342,19 +338,6 @@ func (l2l1 l2ToL1DB) L2ToL1TransactionTxHash(txHash common.Hash) (*L2ToL1, error
 	return &l2ToL1Withdrawal, nil
 }
 
-func (l2l1 l2ToL1DB) L2L1LatestBlockL2Header() (*common2.L2BlockHeader, error) {
-	l2Query := l2l1.gorm.Where("number = (?)", l2l1.gorm.Table("l2_to_l1").Select("MAX(l2_block_number)"))
-	var l2Header common2.L2BlockHeader
-	result := l2Query.Take(&l2Header)
-	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, nil
-		}
-		return nil, result.Error
-	}
-	return &l2Header, nil
-}
-
 func (l2l1 l2ToL1DB) L2L1LatestBlockL1Header() (*common2.L1BlockHeader, error) {
 	var l1Header common2.L1BlockHeader
 	result := l2l1.gorm.Table("l1_block_headers").Order("number desc").Limit(1).Take(&l1Header)
@@ -424,3 +407,143 @@ func (l2l1 *l2ToL1DB) GetWithdrawsClaimedAmount(l1FinalizeTxHash string, startBl
 
 	return l2Tol1s, nil
 }
+
+func (l2l1 l2ToL1DB) RollbackL2ToL1Transactions(l2Height *big.Int) error {
+	if err := withdrawalLifecycle.deleteHistory(l2l1.gorm, func(db *gorm.DB) *gorm.DB { return db.Where("l2_block_number > ?", l2Height) }); err != nil {
+		return err
+	}
+	result := l2l1.gorm.Where("l2_block_number > ?", l2Height).Delete(&L2ToL1{})
+	return result.Error
+}
+
+// RollbackL2ToL1Proven moves withdrawals proven above the supplied L1 height back to ready
+// for proved. Must be called before the withdraw proven events are rolled back.
+func (l2l1 l2ToL1DB) RollbackL2ToL1Proven(l1Height *big.Int) error {
+	_, err := l2l1.resetProven("block_number > ?", l1Height)
+	return err
+}
+
+// RollbackL2ToL1Finalized moves withdrawals finalized above the supplied L1 height back to their
+// proven status. Must be called before the withdraw finalized events are rolled back.
+func (l2l1 l2ToL1DB) RollbackL2ToL1Finalized(l1Height *big.Int) error {
+	_, err := l2l1.resetFinalized("block_number > ?", l1Height)
+	return err
+}
+
+// UpdateL2ToL1RelayStatus derives the status of the finalized withdrawals from their relay attempts on L1.
+// A withdrawal whose relay failed is marked relay failed until it is replayed successfully.
+func (l2l1 l2ToL1DB) UpdateL2ToL1RelayStatus() error {
+	attempts := func(success bool) *gorm.DB {
+		return l2l1.gorm.Table("relay_attempt").Where("chain = ? AND success = ?", common2.SyncCursorLayerL1, success).Select("message_hash")
+	}
+	relayAttempt := func(column string, success bool) *clause.Expr {
+		expr := relayAttemptColumn("l2_to_l1", column, common2.SyncCursorLayerL1, success)
+		return &expr
+	}
+	var rejected rejections
+	_, err := withdrawalLifecycle.transition(l2l1.gorm, func(db *gorm.DB) *gorm.DB {
+		return db.Where("status = ? AND message_hash IN (?) AND message_hash NOT IN (?)", common3.L2ToL1Claimed, attempts(false), attempts(true))
+	}, common3.L2ToL1RelayFailed, nil, statusCause{reason: "relay failed", chain: common2.SyncCursorLayerL1,
+		blockNumberExpr: relayAttempt("block_number", false), transactionHashExpr: relayAttempt("transaction_hash", false)})
+	if err := rejected.check(err); err != nil {
+		return err
+	}
+	_, err = withdrawalLifecycle.transition(l2l1.gorm, func(db *gorm.DB) *gorm.DB {
+		return db.Where("status = ? AND message_hash IN (?)", common3.L2ToL1RelayFailed, attempts(true))
+	}, common3.L2ToL1Claimed, nil, statusCause{reason: "replayed", chain: common2.SyncCursorLayerL1,
+		blockNumberExpr: relayAttempt("block_number", true), transactionHashExpr: relayAttempt("transaction_hash", true)})
+	if err := rejected.check(err); err != nil {
+		return err
+	}
+	// the failed attempts have been rolled back
+	_, err = withdrawalLifecycle.transition(l2l1.gorm, func(db *gorm.DB) *gorm.DB {
+		return db.Where("status = ? AND message_hash NOT IN (?)", common3.L2ToL1RelayFailed, attempts(false))
+	}, common3.L2ToL1Claimed, nil, statusCause{reason: "failed relay rolled back", chain: common2.SyncCursorLayerL1})
+	if err := rejected.check(err); err != nil {
+		return err
+	}
+	return rejected.err()
+}
+
+// InvalidateL2ToL1Proven moves the withdrawals whose proof has been invalidated by the deletion of its output back
+// to ready for proved, or to pending when no canonical output up to the supplied L2 block number covers them anymore,
+// and flags them as to be proven again.
+func (l2l1 l2ToL1DB) InvalidateL2ToL1Proven(l2BlockNumber uint64) (int64, error) {
+	invalidated := func() *gorm.DB { return l2l1.gorm.Table("withdraw_proven").Where("invalidated = ?", true) }
+	invalidatedProofs := func(db *gorm.DB) *gorm.DB {
+		return db.Where("status = ? AND (withdraw_transaction_hash, l1_prove_tx_hash) IN (?)", common3.L2ToL1InChallengePeriod, invalidated().Select("withdraw_hash, proven_transaction_hash"))
+	}
+	updates := map[string]interface{}{"reprove_required": true, "l1_block_number": 0, "l1_prove_tx_hash": common.Hash{}.String(), "l1_proven_timestamp": 0, "challenge_deadline": 0}
+	cause := statusCause{reason: "proof invalidated", chain: common2.SyncCursorLayerL1}
+	var rejected rejections
+	readyForProved, err := withdrawalLifecycle.transition(l2l1.gorm, func(db *gorm.DB) *gorm.DB {
+		return db.Scopes(invalidatedProofs).Where("l2_block_number <= ?", l2BlockNumber)
+	}, common3.L2ToL1ReadyForProved, updates, cause)
+	if err := rejected.check(err); err != nil {
+		return 0, err
+	}
+	pending, err := withdrawalLifecycle.transition(l2l1.gorm, func(db *gorm.DB) *gorm.DB {
+		return db.Scopes(invalidatedProofs).Where("l2_block_number > ?", l2BlockNumber)
+	}, common3.L2ToL1Pending, updates, cause)
+	if err := rejected.check(err); err != nil {
+		return 0, err
+	}
+	// proofs invalidated before being matched to their withdrawal
+	unmatched := l2l1.gorm.Model(&L2ToL1{}).
+		Where("reprove_required = ? AND status IN ? AND withdraw_transaction_hash IN (?)", false, []int{common3.L2ToL1Pending, common3.L2ToL1ReadyForProved}, invalidated().Where("related = ?", false).Select("withdraw_hash")).
+		Updates(map[string]interface{}{"reprove_required": true})
+	if unmatched.Error != nil {
+		return 0, unmatched.Error
+	}
+	return readyForProved + pending + unmatched.RowsAffected, rejected.err()
+}
+
+// RollbackL2ToL1ReadyForProved moves unproven withdrawals above the supplied L2 block number,
+// which are no longer covered by a state root, back to pending.
+func (l2l1 l2ToL1DB) RollbackL2ToL1ReadyForProved(l2BlockNumber uint64) error {
+	_, err := withdrawalLifecycle.transition(l2l1.gorm, func(db *gorm.DB) *gorm.DB {
+		return db.Where("l2_block_number > ? AND status = ?", l2BlockNumber, common3.L2ToL1ReadyForProved)
+	}, common3.L2ToL1Pending, nil, statusCause{reason: "output deleted"})
+	return err
+}
+
+func (l2l1 l2ToL1DB) DeleteL2ToL1Transactions(l2From, l2To *big.Int) (int64, error) {
+	if err := withdrawalLifecycle.deleteHistory(l2l1.gorm, func(db *gorm.DB) *gorm.DB {
+		return db.Where("l2_block_number >= ? AND l2_block_number <= ?", l2From, l2To)
+	}); err != nil {
+		return 0, err
+	}
+	result := l2l1.gorm.Where("l2_block_number >= ? AND l2_block_number <= ?", l2From, l2To).Delete(&L2ToL1{})
+	return result.RowsAffected, result.Error
+}
+
+// ResetL2ToL1Proven moves withdrawals proven within the supplied L1 range back to ready
+// for proved. Must be called before the withdraw proven events are deleted.
+func (l2l1 l2ToL1DB) ResetL2ToL1Proven(l1From, l1To *big.Int) (int64, error) {
+	return l2l1.resetProven("block_number >= ? AND block_number <= ?", l1From, l1To)
+}
+
+func (l2l1 l2ToL1DB) resetProven(blockRange string, args ...interface{}) (int64, error) {
+	proven := l2l1.gorm.Table("withdraw_proven").Where(blockRange, args...).Select("proven_transaction_hash")
+	return withdrawalLifecycle.transition(l2l1.gorm, func(db *gorm.DB) *gorm.DB {
+		return db.Where("l1_prove_tx_hash IN (?)", proven)
+	}, common3.L2ToL1ReadyForProved, map[string]interface{}{"l1_block_number": 0, "l1_prove_tx_hash": common.Hash{}.String(), "l1_finalize_tx_hash": common.Hash{}.String(),
+		"l1_proven_timestamp": 0, "challenge_deadline": 0},
+		statusCause{reason: "proof rolled back", chain: common2.SyncCursorLayerL1})
+}
+
+// ResetL2ToL1Finalized moves withdrawals finalized within the supplied L1 range back to their
+// proven status. Must be called before the withdraw finalized events are deleted.
+func (l2l1 l2ToL1DB) ResetL2ToL1Finalized(l1From, l1To *big.Int) (int64, error) {
+	return l2l1.resetFinalized("block_number >= ? AND block_number <= ?", l1From, l1To)
+}
+
+// resetFinalized moves the withdrawals back to the challenge period, whose deadline is kept
+func (l2l1 l2ToL1DB) resetFinalized(blockRange string, args ...interface{}) (int64, error) {
+	finalized := l2l1.gorm.Table("withdraw_finalized").Where(blockRange, args...).Select("finalized_transaction_hash")
+	provenBlockNumber := gorm.Expr("(SELECT block_number FROM withdraw_proven WHERE withdraw_proven.proven_transaction_hash = l2_to_l1.l1_prove_tx_hash LIMIT 1)")
+	return withdrawalLifecycle.transition(l2l1.gorm, func(db *gorm.DB) *gorm.DB {
+		return db.Where("status IN ? AND l1_finalize_tx_hash IN (?)", []int{common3.L2ToL1Claimed, common3.L2ToL1RelayFailed}, finalized)
+	}, common3.L2ToL1InChallengePeriod, map[string]interface{}{"l1_block_number": provenBlockNumber, "l1_finalize_tx_hash": common.Hash{}.String()},
+		statusCause{reason: "finalization rolled back", chain: common2.SyncCursorLayerL1})
+}
@@ -11,7 +11,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
 
-	common2 "github.com/mantlenetworkio/lithosphere/database/common"
+	common3 "github.com/mantlenetworkio/lithosphere/common"
 )
 
 type StateRoot struct {
@@ -28,6 +28,15 @@ type StateRoot struct {
 	BatchSize         *big.Int    `gorm:"serializer:u256" json:"batchSize"`
 	Timestamp         uint64      `json:"timestamp"`
 	BlockSize         uint64      `json:"blockSize"`
+	// DeletedL1BlockNumber is the L1 block the output was deleted at by the challenger, nil while canonical
+	DeletedL1BlockNumber *big.Int `gorm:"serializer:u256" json:"deletedL1BlockNumber"`
+	// VerificationStatus tells whether the output root matches the one recomputed from the components read
+	// from the L2 node, the components are nil until the output has been verified
+	VerificationStatus       int          `gorm:"column:verification_status" json:"verificationStatus"`
+	L2StateRoot              *common.Hash `gorm:"serializer:bytes;column:l2_state_root" json:"l2StateRoot"`
+	MessagePasserStorageRoot *common.Hash `gorm:"serializer:bytes;column:message_passer_storage_root" json:"messagePasserStorageRoot"`
+	L2BlockHash              *common.Hash `gorm:"serializer:bytes;column:l2_block_hash" json:"l2BlockHash"`
+	ComputedOutputRoot       *common.Hash `gorm:"serializer:bytes;column:computed_output_root" json:"computedOutputRoot"`
 }
 
 func (StateRoot) TableName() string {
@@ -39,13 +48,19 @@ type StateRootDB interface {
 	StoreBatchStateRoots([]StateRoot) error
 	UpdateSafeStatus(safeBlockNumber *big.Int) error
 	UpdateFinalizedStatus(finalizedBlockNumber *big.Int) error
+	MarkStateRootsDeleted(newNextOutputIndex, prevNextOutputIndex, l1BlockNumber *big.Int) (int64, error)
+	RollbackStateRoots(l1Height *big.Int) error
+	DeleteStateRoots(l1From, l1To *big.Int) (int64, error)
+	ResetStateRootsDeleted(l1From, l1To *big.Int) (int64, error)
+	UpdateStateRootVerification(stateRoot StateRoot) error
 }
 
 type StateRootView interface {
 	StateRootList(int, int, string) ([]StateRoot, int64)
 	StateRootByIndex(index *big.Int) (*StateRoot, error)
+	StateRootCoveringL2Block(l2BlockNumber *big.Int) (*StateRoot, error)
 	GetLatestStateRootL2BlockNumber() (uint64, error)
-	StateRootL1BlockHeader() (*common2.L1BlockHeader, error)
+	UnverifiedStateRoots(l2Height *big.Int, limit int) ([]StateRoot, error)
 }
 
 /**
@@ -100,7 +115,7 @@ func (s stateRootDB) StateRootList(page int, pageSize int, order string) ([]Stat
 
 func (s stateRootDB) GetLatestStateRootL2BlockNumber() (uint64, error) {
 	var l2BlockNumber uint64
-	err := s.gorm.Table("state_root").Select("l2_block_number").Order("timestamp DESC").Limit(1).Find(&l2BlockNumber).Error
+	err := s.gorm.Table("state_root").Where("canonical = ?", true).Select("l2_block_number").Order("timestamp DESC").Limit(1).Find(&l2BlockNumber).Error
 	if err != nil {
 		return 0, err
 	}
@@ -128,7 +143,8 @@ func (s stateRootDB) UpdateFinalizedStatus(finalizedBlockNumber *big.Int) error
 
 func (s stateRootDB) StateRootByIndex(index *big.Int) (*StateRoot, error) {
 	var stateRoot StateRoot
-	stateRootQuery := s.gorm.Where("output_index=?", index.Uint64())
+	// a deleted output index is reused by the next proposal
+	stateRootQuery := s.gorm.Where("output_index=?", index.Uint64()).Order("canonical DESC, l1_block_number DESC")
 	result := stateRootQuery.Take(&stateRoot)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
@@ -140,15 +156,66 @@ func (s stateRootDB) StateRootByIndex(index *big.Int) (*StateRoot, error) {
 	return &stateRoot, nil
 }
 
-func (s stateRootDB) StateRootL1BlockHeader() (*common2.L1BlockHeader, error) {
-	l1Query := s.gorm.Where("number = (?)", s.gorm.Table("state_root").Select("MAX(l1_block_number)"))
-	var l1Header common2.L1BlockHeader
-	result := l1Query.Take(&l1Header)
+// StateRootCoveringL2Block returns the first canonical output proposed at or above the L2 block, nil when none covers it yet
+func (s stateRootDB) StateRootCoveringL2Block(l2BlockNumber *big.Int) (*StateRoot, error) {
+	var stateRoot StateRoot
+	result := s.gorm.Where("canonical = ? AND l2_block_number >= ?", true, l2BlockNumber).Order("output_index ASC").Take(&stateRoot)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, nil
 		}
 		return nil, result.Error
 	}
-	return &l1Header, nil
+	return &stateRoot, nil
+}
+
+// MarkStateRootsDeleted marks the outputs deleted by the challenger at the L1 block as non canonical, from
+// newNextOutputIndex up to, but excluding, prevNextOutputIndex. Outputs proposed after the deletion are left untouched.
+func (s stateRootDB) MarkStateRootsDeleted(newNextOutputIndex, prevNextOutputIndex, l1BlockNumber *big.Int) (int64, error) {
+	result := s.gorm.Model(&StateRoot{}).
+		Where("canonical = ? AND output_index >= ? AND output_index < ? AND l1_block_number <= ?", true, newNextOutputIndex, prevNextOutputIndex, l1BlockNumber).
+		Updates(map[string]interface{}{"canonical": false, "deleted_l1_block_number": l1BlockNumber})
+	return result.RowsAffected, result.Error
+}
+
+func (s stateRootDB) RollbackStateRoots(l1Height *big.Int) error {
+	result := s.gorm.Model(&StateRoot{}).Where("deleted_l1_block_number > ?", l1Height).
+		Updates(map[string]interface{}{"canonical": true, "deleted_l1_block_number": nil})
+	if result.Error != nil {
+		return result.Error
+	}
+	result = s.gorm.Where("l1_block_number > ?", l1Height).Delete(&StateRoot{})
+	return result.Error
+}
+
+func (s stateRootDB) DeleteStateRoots(l1From, l1To *big.Int) (int64, error) {
+	result := s.gorm.Where("l1_block_number >= ? AND l1_block_number <= ?", l1From, l1To).Delete(&StateRoot{})
+	return result.RowsAffected, result.Error
+}
+
+// ResetStateRootsDeleted restores the outputs deleted within the supplied L1 range as canonical
+func (s stateRootDB) ResetStateRootsDeleted(l1From, l1To *big.Int) (int64, error) {
+	result := s.gorm.Model(&StateRoot{}).Where("deleted_l1_block_number >= ? AND deleted_l1_block_number <= ?", l1From, l1To).
+		Updates(map[string]interface{}{"canonical": true, "deleted_l1_block_number": nil})
+	return result.RowsAffected, result.Error
+}
+
+// UnverifiedStateRoots returns the outputs up to the L2 height yet to be verified against L2, latest first. The
+// legacy state batches, without an L2 block, are not outputs and are never verified.
+func (s stateRootDB) UnverifiedStateRoots(l2Height *big.Int, limit int) ([]StateRoot, error) {
+	var stateRoots []StateRoot
+	result := s.gorm.Where("verification_status = ? AND l2_block_number > 0 AND l2_block_number <= ?", common3.OutputRootUnverified, l2Height).
+		Order("l2_block_number DESC").Limit(limit).Find(&stateRoots)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return stateRoots, nil
+}
+
+// UpdateStateRootVerification stores the verification status of the output along with the components its root was recomputed from
+func (s stateRootDB) UpdateStateRootVerification(stateRoot StateRoot) error {
+	result := s.gorm.Model(&StateRoot{}).Where("guid = ?", stateRoot.GUID).
+		Select("verification_status", "l2_state_root", "message_passer_storage_root", "l2_block_hash", "computed_output_root").
+		Updates(&stateRoot)
+	return result.Error
 }
@@ -0,0 +1,111 @@
+package business
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Update types of the SystemConfig ConfigUpdate event
+const (
+	SystemConfigUpdateBatcher           uint8 = 0
+	SystemConfigUpdateGasConfig         uint8 = 1
+	SystemConfigUpdateGasLimit          uint8 = 2
+	SystemConfigUpdateUnsafeBlockSigner uint8 = 3
+)
+
+// SystemConfigUpdate is a ConfigUpdate event of the SystemConfig. Only the fields
+// of its update type are set, the others are left empty.
+type SystemConfigUpdate struct {
+	GUID              uuid.UUID      `gorm:"primaryKey" json:"guid"`
+	L1BlockNumber     *big.Int       `gorm:"serializer:u256;column:l1_block_number" json:"l1BlockNumber"`
+	TransactionHash   common.Hash    `gorm:"serializer:bytes;column:transaction_hash" json:"transactionHash"`
+	LogIndex          uint64         `gorm:"column:log_index" json:"logIndex"`
+	Version           *big.Int       `gorm:"serializer:u256;column:version" json:"version"`
+	UpdateType        uint8          `gorm:"column:update_type" json:"updateType"`
+	BatcherHash       common.Hash    `gorm:"serializer:bytes;column:batcher_hash" json:"batcherHash"`
+	Overhead          *big.Int       `gorm:"serializer:u256;column:overhead" json:"overhead"`
+	Scalar            *big.Int       `gorm:"serializer:u256;column:scalar" json:"scalar"`
+	GasLimit          *big.Int       `gorm:"serializer:u256;column:gas_limit" json:"gasLimit"`
+	UnsafeBlockSigner common.Address `gorm:"serializer:bytes;column:unsafe_block_signer" json:"unsafeBlockSigner"`
+	Timestamp         int64          `gorm:"column:timestamp" json:"timestamp"`
+}
+
+func (SystemConfigUpdate) TableName() string {
+	return "system_config_update"
+}
+
+type SystemConfigDB interface {
+	SystemConfigView
+	StoreSystemConfigUpdates([]SystemConfigUpdate) error
+	RollbackSystemConfigUpdates(l1Height *big.Int) error
+	DeleteSystemConfigUpdates(l1From, l1To *big.Int) (int64, error)
+}
+
+type SystemConfigView interface {
+	SystemConfigUpdateList(page int, pageSize int, order string) ([]SystemConfigUpdate, int64)
+	SystemConfigAtL1Block(l1BlockNumber *big.Int) ([]SystemConfigUpdate, error)
+}
+
+type systemConfigDB struct {
+	gorm *gorm.DB
+}
+
+func NewSystemConfigDB(db *gorm.DB) SystemConfigDB {
+	return &systemConfigDB{gorm: db}
+}
+
+func (db systemConfigDB) StoreSystemConfigUpdates(updates []SystemConfigUpdate) error {
+	result := db.gorm.CreateInBatches(&updates, len(updates))
+	return result.Error
+}
+
+func (db systemConfigDB) SystemConfigUpdateList(page int, pageSize int, order string) ([]SystemConfigUpdate, int64) {
+	var totalRecord int64
+	var updates []SystemConfigUpdate
+	if err := db.gorm.Table("system_config_update").Count(&totalRecord).Error; err != nil {
+		log.Error("get system config update count fail", "err", err)
+	}
+	query := db.gorm.Table("system_config_update").Offset((page - 1) * pageSize).Limit(pageSize)
+	if strings.ToLower(order) == "asc" {
+		query = query.Order("l1_block_number asc, log_index asc")
+	} else {
+		query = query.Order("l1_block_number desc, log_index desc")
+	}
+	if err := query.Find(&updates).Error; err != nil {
+		log.Error("get system config update list fail", "err", err)
+	}
+	return updates, totalRecord
+}
+
+// SystemConfigAtL1Block returns, for every update type, the last update applied at or below the L1 block
+func (db systemConfigDB) SystemConfigAtL1Block(l1BlockNumber *big.Int) ([]SystemConfigUpdate, error) {
+	return db.latestUpdates(db.gorm.Where("l1_block_number <= ?", l1BlockNumber))
+}
+
+func (db systemConfigDB) latestUpdates(query *gorm.DB) ([]SystemConfigUpdate, error) {
+	var updates []SystemConfigUpdate
+	result := query.Model(&SystemConfigUpdate{}).
+		Select("DISTINCT ON (update_type) *").
+		Order("update_type ASC, l1_block_number DESC, log_index DESC").
+		Find(&updates)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return updates, nil
+}
+
+func (db systemConfigDB) RollbackSystemConfigUpdates(l1Height *big.Int) error {
+	result := db.gorm.Where("l1_block_number > ?", l1Height).Delete(&SystemConfigUpdate{})
+	return result.Error
+}
+
+func (db systemConfigDB) DeleteSystemConfigUpdates(l1From, l1To *big.Int) (int64, error) {
+	result := db.gorm.Where("l1_block_number >= ? AND l1_block_number <= ?", l1From, l1To).Delete(&SystemConfigUpdate{})
+	return result.RowsAffected, result.Error
+}
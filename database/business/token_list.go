@@ -1,6 +1,7 @@
 package business
 
 import (
+	"errors"
 	"strings"
 
 	"gorm.io/gorm"
@@ -28,6 +29,7 @@ type TokenListDB interface {
 
 type TokenListView interface {
 	GetSymbolByAddress(address string) (string, error)
+	GetTokenByAddress(address string) (*TokenList, error)
 }
 
 type tokenListDB struct {
@@ -50,9 +52,40 @@ func (tl tokenListDB) SaveTokenList(list TokenList) error {
 	return result.Error
 }
 
+// GetSymbolByAddress returns the symbol of the token, see GetTokenByAddress
 func (tl tokenListDB) GetSymbolByAddress(address string) (string, error) {
+	token, err := tl.GetTokenByAddress(address)
+	if err != nil {
+		return "", err
+	} else if token == nil {
+		return "", gorm.ErrRecordNotFound
+	}
+	return token.Symbol, nil
+}
+
+// GetTokenByAddress returns the metadata of the token from the remote token list, which overrides the
+// metadata read on-chain for the token pairs of the OptimismMintableERC20Factory. Either token of a pair
+// resolves to the metadata of its own chain. Nil is returned for unknown tokens.
+func (tl tokenListDB) GetTokenByAddress(address string) (*TokenList, error) {
 	address = strings.ToLower(address)
-	symbol := ""
-	result := tl.gorm.Table("token_lists").Where("address = ?", address).Select("symbol").Take(&symbol)
-	return symbol, result.Error
+	var token TokenList
+	result := tl.gorm.Table("token_lists").Where("address = ?", address).Take(&token)
+	if result.Error == nil {
+		return &token, nil
+	} else if !errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, result.Error
+	}
+
+	var pair TokenPair
+	result = tl.gorm.Where("metadata_synced AND (l1_token_address = ? OR l2_token_address = ?)", address, address).Take(&pair)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	if strings.ToLower(pair.L2TokenAddress.String()) == address {
+		return &TokenList{Address: address, Name: pair.L2Name, Symbol: pair.L2Symbol, Decimals: uint64(pair.L2Decimals), Timestamp: uint64(pair.Timestamp)}, nil
+	}
+	return &TokenList{Address: address, Name: pair.L1Name, Symbol: pair.L1Symbol, Decimals: uint64(pair.L1Decimals), Timestamp: uint64(pair.Timestamp)}, nil
 }
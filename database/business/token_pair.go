@@ -0,0 +1,99 @@
+package business
+
+import (
+	"math/big"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// TokenPair is an L2 token deployed by the OptimismMintableERC20Factory along with the L1 token it
+// bridges. The token metadata is read on-chain by the business processor after the pair is indexed.
+type TokenPair struct {
+	L2TokenAddress    common.Address `gorm:"primaryKey;serializer:bytes;column:l2_token_address" json:"l2TokenAddress"`
+	L1TokenAddress    common.Address `gorm:"serializer:bytes;column:l1_token_address" json:"l1TokenAddress"`
+	Deployer          common.Address `gorm:"serializer:bytes;column:deployer" json:"deployer"`
+	L2BlockNumber     *big.Int       `gorm:"serializer:u256;column:l2_block_number" json:"l2BlockNumber"`
+	L2TransactionHash common.Hash    `gorm:"serializer:bytes;column:l2_transaction_hash" json:"l2TransactionHash"`
+	L1Name            string         `gorm:"column:l1_name" json:"l1Name"`
+	L1Symbol          string         `gorm:"column:l1_symbol" json:"l1Symbol"`
+	L1Decimals        uint8          `gorm:"column:l1_decimals" json:"l1Decimals"`
+	L2Name            string         `gorm:"column:l2_name" json:"l2Name"`
+	L2Symbol          string         `gorm:"column:l2_symbol" json:"l2Symbol"`
+	L2Decimals        uint8          `gorm:"column:l2_decimals" json:"l2Decimals"`
+	MetadataSynced    bool           `gorm:"column:metadata_synced" json:"metadataSynced"`
+	MetadataAttempts  int            `gorm:"column:metadata_attempts" json:"metadataAttempts"`
+	Timestamp         int64          `gorm:"column:timestamp" json:"timestamp"`
+}
+
+func (TokenPair) TableName() string {
+	return "token_pair"
+}
+
+type TokenPairDB interface {
+	TokenPairView
+	StoreTokenPairs([]TokenPair) error
+	UpdateTokenPairMetadata(TokenPair) error
+	MarkTokenPairMetadataFailed(l2Token common.Address) error
+	RollbackTokenPairs(l2Height *big.Int) error
+	DeleteTokenPairs(l2From, l2To *big.Int) (int64, error)
+}
+
+type TokenPairView interface {
+	TokenPairsWithoutMetadata(limit int, maxAttempts int) ([]TokenPair, error)
+}
+
+type tokenPairDB struct {
+	gorm *gorm.DB
+}
+
+func NewTokenPairDB(db *gorm.DB) TokenPairDB {
+	return &tokenPairDB{gorm: db}
+}
+
+func (db tokenPairDB) StoreTokenPairs(pairs []TokenPair) error {
+	result := db.gorm.CreateInBatches(&pairs, len(pairs))
+	return result.Error
+}
+
+// TokenPairsWithoutMetadata returns the pairs whose metadata hasn't been read yet, leaving out the ones
+// that failed to be read maxAttempts times already
+func (db tokenPairDB) TokenPairsWithoutMetadata(limit int, maxAttempts int) ([]TokenPair, error) {
+	var pairs []TokenPair
+	result := db.gorm.Where("metadata_synced = ? AND metadata_attempts < ?", false, maxAttempts).
+		Order("metadata_attempts ASC, l2_block_number ASC").Limit(limit).Find(&pairs)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return pairs, nil
+}
+
+// UpdateTokenPairMetadata stores the metadata read for both tokens of the pair and marks it synced
+func (db tokenPairDB) UpdateTokenPairMetadata(pair TokenPair) error {
+	result := db.gorm.Model(&TokenPair{}).Where("l2_token_address = ?", strings.ToLower(pair.L2TokenAddress.String())).
+		Updates(map[string]interface{}{
+			"l1_name": pair.L1Name, "l1_symbol": pair.L1Symbol, "l1_decimals": pair.L1Decimals,
+			"l2_name": pair.L2Name, "l2_symbol": pair.L2Symbol, "l2_decimals": pair.L2Decimals,
+			"metadata_synced": true,
+		})
+	return result.Error
+}
+
+// MarkTokenPairMetadataFailed counts a failed attempt at reading the metadata of the pair
+func (db tokenPairDB) MarkTokenPairMetadataFailed(l2Token common.Address) error {
+	result := db.gorm.Model(&TokenPair{}).Where("l2_token_address = ?", strings.ToLower(l2Token.String())).
+		UpdateColumn("metadata_attempts", gorm.Expr("metadata_attempts + 1"))
+	return result.Error
+}
+
+func (db tokenPairDB) RollbackTokenPairs(l2Height *big.Int) error {
+	result := db.gorm.Where("l2_block_number > ?", l2Height).Delete(&TokenPair{})
+	return result.Error
+}
+
+func (db tokenPairDB) DeleteTokenPairs(l2From, l2To *big.Int) (int64, error) {
+	result := db.gorm.Where("l2_block_number >= ? AND l2_block_number <= ?", l2From, l2To).Delete(&TokenPair{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,177 @@
+package business
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Kinds of withdrawal incidents
+const (
+	// A WithdrawalProven event without any MessagePassed of the withdrawal on L2
+	IncidentProvenWithoutInitiation = "proven_without_initiation"
+	// A WithdrawalFinalized event without any MessagePassed of the withdrawal on L2
+	IncidentFinalizedWithoutInitiation = "finalized_without_initiation"
+	// The amounts released by the L1 bridge differ from the amounts withdrawn on L2
+	IncidentAmountMismatch = "amount_mismatch"
+)
+
+// WithdrawalIncident is a proof or finalization on L1 which does not match the withdrawal initiated on L2.
+// The amounts are only set for the amount mismatches.
+type WithdrawalIncident struct {
+	GUID              uuid.UUID   `gorm:"primaryKey" json:"guid"`
+	Kind              string      `gorm:"column:kind" json:"kind"`
+	WithdrawHash      common.Hash `gorm:"serializer:bytes;column:withdraw_hash" json:"withdrawHash"`
+	L1TransactionHash common.Hash `gorm:"serializer:bytes;column:l1_transaction_hash" json:"l1TransactionHash"`
+	L1BlockNumber     *big.Int    `gorm:"serializer:u256;column:l1_block_number" json:"l1BlockNumber"`
+	L1ETHAmount       *big.Int    `gorm:"serializer:u256;column:l1_eth_amount" json:"l1ETHAmount"`
+	L1ERC20Amount     *big.Int    `gorm:"serializer:u256;column:l1_erc20_amount" json:"l1ERC20Amount"`
+	L2ETHAmount       *big.Int    `gorm:"serializer:u256;column:l2_eth_amount" json:"l2ETHAmount"`
+	L2ERC20Amount     *big.Int    `gorm:"serializer:u256;column:l2_erc20_amount" json:"l2ERC20Amount"`
+	// Resolved is set once the withdrawal initiation of a missing initiation incident has been indexed
+	Resolved   bool   `gorm:"column:resolved" json:"resolved"`
+	Timestamp  uint64 `gorm:"column:timestamp" json:"timestamp"`
+	DetectedAt int64  `gorm:"column:detected_at" json:"detectedAt"`
+}
+
+func (WithdrawalIncident) TableName() string {
+	return "withdrawal_incident"
+}
+
+type WithdrawalIncidentDB interface {
+	WithdrawalIncidentView
+	DetectWithdrawalIncidents(provenFrom, finalizedFrom, toTimestamp uint64) ([]WithdrawalIncident, error)
+	StoreWithdrawalIncidents([]WithdrawalIncident) error
+	ResolveWithdrawalIncidents() (int64, error)
+	RollbackWithdrawalIncidents(l1Height *big.Int) error
+	DeleteWithdrawalIncidents(l1From, l1To *big.Int) (int64, error)
+}
+
+type WithdrawalIncidentView interface {
+	WithdrawalIncidentList(kind string, page int, pageSize int, order string) ([]WithdrawalIncident, int64)
+	OpenWithdrawalIncidentsCount() (int64, error)
+}
+
+type withdrawalIncidentDB struct {
+	gorm *gorm.DB
+}
+
+func NewWithdrawalIncidentDB(db *gorm.DB) WithdrawalIncidentDB {
+	return &withdrawalIncidentDB{gorm: db}
+}
+
+// DetectWithdrawalIncidents returns the incidents not stored yet. Proofs and finalizations without initiation are
+// only reported within the L1 timestamps, from the first ones which can't refer to withdrawals initiated before
+// the indexed L2 withdrawals up to the timestamp these have been indexed past by the grace period.
+func (db withdrawalIncidentDB) DetectWithdrawalIncidents(provenFrom, finalizedFrom, toTimestamp uint64) ([]WithdrawalIncident, error) {
+	var proven []WithdrawalIncident
+	result := db.gorm.Table("withdraw_proven").
+		Select("withdraw_hash, proven_transaction_hash AS l1_transaction_hash, block_number AS l1_block_number, timestamp").
+		Where("timestamp >= ? AND timestamp <= ?", provenFrom, toTimestamp).
+		Where("NOT EXISTS (SELECT 1 FROM l2_to_l1 WHERE l2_to_l1.withdraw_transaction_hash = withdraw_proven.withdraw_hash)").
+		Where("NOT EXISTS (SELECT 1 FROM withdrawal_incident WHERE kind = ? AND withdrawal_incident.withdraw_hash = withdraw_proven.withdraw_hash AND withdrawal_incident.l1_transaction_hash = withdraw_proven.proven_transaction_hash)", IncidentProvenWithoutInitiation).
+		Find(&proven)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	for i := range proven {
+		proven[i].Kind = IncidentProvenWithoutInitiation
+	}
+
+	var finalized []WithdrawalIncident
+	result = db.gorm.Table("withdraw_finalized").
+		Select("withdraw_hash, finalized_transaction_hash AS l1_transaction_hash, block_number AS l1_block_number, timestamp").
+		Where("timestamp >= ? AND timestamp <= ?", finalizedFrom, toTimestamp).
+		Where("NOT EXISTS (SELECT 1 FROM l2_to_l1 WHERE l2_to_l1.withdraw_transaction_hash = withdraw_finalized.withdraw_hash)").
+		Where("NOT EXISTS (SELECT 1 FROM withdrawal_incident WHERE kind = ? AND withdrawal_incident.withdraw_hash = withdraw_finalized.withdraw_hash AND withdrawal_incident.l1_transaction_hash = withdraw_finalized.finalized_transaction_hash)", IncidentFinalizedWithoutInitiation).
+		Find(&finalized)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	for i := range finalized {
+		finalized[i].Kind = IncidentFinalizedWithoutInitiation
+	}
+
+	// only the finalizations bound to their BridgeFinalized event carry the amounts released on L1
+	var mismatches []WithdrawalIncident
+	result = db.gorm.Table("withdraw_finalized").
+		Select("withdraw_finalized.withdraw_hash, withdraw_finalized.finalized_transaction_hash AS l1_transaction_hash, withdraw_finalized.block_number AS l1_block_number, "+
+			"withdraw_finalized.eth_amount AS l1_eth_amount, withdraw_finalized.erc20_amount AS l1_erc20_amount, "+
+			"l2_to_l1.eth_amount AS l2_eth_amount, l2_to_l1.erc20_amount AS l2_erc20_amount, withdraw_finalized.timestamp").
+		Joins("JOIN l2_to_l1 ON l2_to_l1.withdraw_transaction_hash = withdraw_finalized.withdraw_hash").
+		Where("withdraw_finalized.bridge_finalized = ?", true).
+		Where("COALESCE(withdraw_finalized.eth_amount, 0) != COALESCE(l2_to_l1.eth_amount, 0) OR COALESCE(withdraw_finalized.erc20_amount, 0) != COALESCE(l2_to_l1.erc20_amount, 0)").
+		Where("NOT EXISTS (SELECT 1 FROM withdrawal_incident WHERE kind = ? AND withdrawal_incident.withdraw_hash = withdraw_finalized.withdraw_hash AND withdrawal_incident.l1_transaction_hash = withdraw_finalized.finalized_transaction_hash)", IncidentAmountMismatch).
+		Find(&mismatches)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	for i := range mismatches {
+		mismatches[i].Kind = IncidentAmountMismatch
+	}
+
+	incidents := append(append(proven, finalized...), mismatches...)
+	for i := range incidents {
+		incidents[i].GUID = uuid.New()
+	}
+	return incidents, nil
+}
+
+func (db withdrawalIncidentDB) StoreWithdrawalIncidents(incidents []WithdrawalIncident) error {
+	result := db.gorm.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(&incidents, len(incidents))
+	return result.Error
+}
+
+// ResolveWithdrawalIncidents resolves the missing initiation incidents whose withdrawal has been indexed since,
+// the L2 withdrawals lagged behind the grace period
+func (db withdrawalIncidentDB) ResolveWithdrawalIncidents() (int64, error) {
+	result := db.gorm.Model(&WithdrawalIncident{}).
+		Where("resolved = ? AND kind IN ?", false, []string{IncidentProvenWithoutInitiation, IncidentFinalizedWithoutInitiation}).
+		Where("EXISTS (SELECT 1 FROM l2_to_l1 WHERE l2_to_l1.withdraw_transaction_hash = withdrawal_incident.withdraw_hash)").
+		Updates(map[string]interface{}{"resolved": true})
+	return result.RowsAffected, result.Error
+}
+
+func (db withdrawalIncidentDB) OpenWithdrawalIncidentsCount() (int64, error) {
+	var count int64
+	result := db.gorm.Model(&WithdrawalIncident{}).Where("resolved = ?", false).Count(&count)
+	return count, result.Error
+}
+
+func (db withdrawalIncidentDB) WithdrawalIncidentList(kind string, page int, pageSize int, order string) ([]WithdrawalIncident, int64) {
+	var totalRecord int64
+	var incidents []WithdrawalIncident
+	query := db.gorm.Table("withdrawal_incident")
+	if kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+	if err := query.Count(&totalRecord).Error; err != nil {
+		log.Error("get withdrawal incident count fail", "err", err)
+	}
+	query = query.Offset((page - 1) * pageSize).Limit(pageSize)
+	if strings.ToLower(order) == "asc" {
+		query = query.Order("timestamp asc")
+	} else {
+		query = query.Order("timestamp desc")
+	}
+	if err := query.Find(&incidents).Error; err != nil {
+		log.Error("get withdrawal incident list fail", "err", err)
+	}
+	return incidents, totalRecord
+}
+
+func (db withdrawalIncidentDB) RollbackWithdrawalIncidents(l1Height *big.Int) error {
+	result := db.gorm.Where("l1_block_number > ?", l1Height).Delete(&WithdrawalIncident{})
+	return result.Error
+}
+
+func (db withdrawalIncidentDB) DeleteWithdrawalIncidents(l1From, l1To *big.Int) (int64, error) {
+	result := db.gorm.Where("l1_block_number >= ? AND l1_block_number <= ?", l1From, l1To).Delete(&WithdrawalIncident{})
+	return result.RowsAffected, result.Error
+}
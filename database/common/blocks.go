@@ -63,6 +63,12 @@ type BlocksDB interface {
 
 	StoreL1BlockHeaders([]L1BlockHeader) error
 	StoreL2BlockHeaders([]L2BlockHeader) error
+
+	RollbackL1BlockHeaders(*big.Int) error
+	RollbackL2BlockHeaders(*big.Int) error
+
+	DeleteL1BlockHeaders(from, to *big.Int) (int64, error)
+	DeleteL2BlockHeaders(from, to *big.Int) (int64, error)
 }
 
 /**
@@ -84,6 +90,20 @@ func (db *blocksDB) StoreL1BlockHeaders(headers []L1BlockHeader) error {
 	return result.Error
 }
 
+// RollbackL1BlockHeaders removes every header above the supplied height. The contract
+// events of these headers are removed along with them by the foreign key cascade.
+func (db *blocksDB) RollbackL1BlockHeaders(height *big.Int) error {
+	result := db.gorm.Where("number > ?", height).Delete(&L1BlockHeader{})
+	return result.Error
+}
+
+// DeleteL1BlockHeaders removes the headers within the supplied range, returning how many were
+// removed. The contract events of these headers are removed along with them by the foreign key cascade.
+func (db *blocksDB) DeleteL1BlockHeaders(from, to *big.Int) (int64, error) {
+	result := db.gorm.Where("number >= ? AND number <= ?", from, to).Delete(&L1BlockHeader{})
+	return result.RowsAffected, result.Error
+}
+
 func (db *blocksDB) L1BlockHeader(hash common.Hash) (*L1BlockHeader, error) {
 	return db.L1BlockHeaderWithFilter(BlockHeader{Hash: hash})
 }
@@ -125,6 +145,19 @@ func (db *blocksDB) StoreL2BlockHeaders(headers []L2BlockHeader) error {
 	return result.Error
 }
 
+// RollbackL2BlockHeaders removes every header above the supplied height. The contract
+// events of these headers are removed along with them by the foreign key cascade.
+func (db *blocksDB) RollbackL2BlockHeaders(height *big.Int) error {
+	result := db.gorm.Where("number > ?", height).Delete(&L2BlockHeader{})
+	return result.Error
+}
+
+// DeleteL2BlockHeaders is the L2 counterpart of DeleteL1BlockHeaders
+func (db *blocksDB) DeleteL2BlockHeaders(from, to *big.Int) (int64, error) {
+	result := db.gorm.Where("number >= ? AND number <= ?", from, to).Delete(&L2BlockHeader{})
+	return result.RowsAffected, result.Error
+}
+
 func (db *blocksDB) L2BlockHeader(hash common.Hash) (*L2BlockHeader, error) {
 	return db.L2BlockHeaderWithFilter(BlockHeader{Hash: hash})
 }
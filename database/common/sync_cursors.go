@@ -0,0 +1,116 @@
+package common
+
+import (
+	"errors"
+	"math/big"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	SyncCursorLayerL1 = "l1"
+	SyncCursorLayerL2 = "l2"
+)
+
+// Cursors of the synchronizers, pointing to the last traversed header
+const (
+	L1SynchronizerCursor = "l1_synchronizer"
+	L2SynchronizerCursor = "l2_synchronizer"
+)
+
+// Cursors of the event processor stages, pointing to the last processed header
+const (
+	L1BridgeInitiatedCursor   = "l1_bridge_initiated"
+	L2BridgeFinalizedCursor   = "l2_bridge_finalized"
+	L1MantleDACursor          = "l1_mantle_da"
+	L1StateRootCursor         = "l1_state_root"
+	L2BridgeInitiatedCursor   = "l2_bridge_initiated"
+	L1WithdrawProvenCursor    = "l1_withdraw_proven"
+	L1WithdrawFinalizedCursor = "l1_withdraw_finalized"
+	L1SystemConfigCursor      = "l1_system_config"
+)
+
+type SyncCursor struct {
+	Name        string `gorm:"primaryKey"`
+	Layer       string
+	BlockNumber *big.Int    `gorm:"serializer:u256"`
+	BlockHash   common.Hash `gorm:"serializer:bytes"`
+	// BlockTimestamp is the timestamp of the header, which the synchronizers may not have stored
+	BlockTimestamp uint64
+	UpdatedAt      int64 `gorm:"autoUpdateTime"`
+}
+
+func (SyncCursor) TableName() string {
+	return "sync_cursors"
+}
+
+type SyncCursorsView interface {
+	SyncCursor(name string) (*SyncCursor, error)
+}
+
+type SyncCursorsDB interface {
+	SyncCursorsView
+
+	StoreSyncCursor(name, layer string, number *big.Int, hash common.Hash, timestamp uint64) error
+	DeleteSyncCursor(name string) error
+	RollbackSyncCursors(layer string, height *big.Int) error
+}
+
+type syncCursorsDB struct {
+	gorm *gorm.DB
+}
+
+func NewSyncCursorsDB(db *gorm.DB) SyncCursorsDB {
+	return &syncCursorsDB{gorm: db}
+}
+
+func (db *syncCursorsDB) SyncCursor(name string) (*SyncCursor, error) {
+	var cursor SyncCursor
+	result := db.gorm.Where("name = ?", name).Take(&cursor)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &cursor, nil
+}
+
+func (db *syncCursorsDB) StoreSyncCursor(name, layer string, number *big.Int, hash common.Hash, timestamp uint64) error {
+	cursor := SyncCursor{Name: name, Layer: layer, BlockNumber: number, BlockHash: hash, BlockTimestamp: timestamp}
+	result := db.gorm.Clauses(clause.OnConflict{UpdateAll: true}).Create(&cursor)
+	return result.Error
+}
+
+func (db *syncCursorsDB) DeleteSyncCursor(name string) error {
+	result := db.gorm.Where("name = ?", name).Delete(&SyncCursor{})
+	return result.Error
+}
+
+// RollbackSyncCursors moves every cursor of the layer above the supplied height back to the latest
+// header still stored at or below it. Cursors left without such a header are removed, in which case
+// their owner resumes from the indexed state as it did before the cursors were introduced.
+func (db *syncCursorsDB) RollbackSyncCursors(layer string, height *big.Int) error {
+	var header BlockHeader
+	var result *gorm.DB
+	if layer == SyncCursorLayerL1 {
+		var l1Header L1BlockHeader
+		result = db.gorm.Where("number <= ?", height).Order("number DESC").Take(&l1Header)
+		header = l1Header.BlockHeader
+	} else {
+		var l2Header L2BlockHeader
+		result = db.gorm.Where("number <= ?", height).Order("number DESC").Take(&l2Header)
+		header = l2Header.BlockHeader
+	}
+
+	rolledBack := db.gorm.Where("layer = ? AND block_number > ?", layer, height)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return rolledBack.Delete(&SyncCursor{}).Error
+	} else if result.Error != nil {
+		return result.Error
+	}
+	return rolledBack.Model(&SyncCursor{}).Updates(SyncCursor{BlockNumber: header.Number, BlockHash: header.Hash, BlockTimestamp: header.Timestamp}).Error
+}
@@ -49,6 +49,8 @@ type TransactionsDB interface {
 	TransactionsView
 	BuildTransactions(*types.Transaction, *types.Receipt) (Transactions, error)
 	StoreTransactions([]Transactions) error
+	RollbackTransactions(*big.Int) error
+	DeleteTransactions(from, to *big.Int) (int64, error)
 }
 
 type TransactionsView interface {
@@ -104,3 +106,13 @@ func (tx transactionsDB) BuildTransactions(transaction *types.Transaction, trans
 		Timestamp:            uint64(transaction.Time().Unix()),
 	}, nil
 }
+
+func (tx transactionsDB) RollbackTransactions(height *big.Int) error {
+	result := tx.gorm.Where("block_number > ?", height).Delete(&Transactions{})
+	return result.Error
+}
+
+func (tx transactionsDB) DeleteTransactions(from, to *big.Int) (int64, error) {
+	result := tx.gorm.Where("block_number >= ? AND block_number <= ?", from, to).Delete(&Transactions{})
+	return result.RowsAffected, result.Error
+}
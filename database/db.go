@@ -34,6 +34,7 @@ type DB struct {
 	WithdrawProven     event.WithdrawProvenDB
 	WithdrawFinalized  event.WithdrawFinalizedDB
 	RelayMessage       event.RelayMessageDB
+	RelayAttempt       event.RelayAttemptDB
 	StateRoots         business.StateRootDB
 	DataStore          business.DataStoreDB
 	L2ToL1             business.L2ToL1DB
@@ -42,6 +43,13 @@ type DB struct {
 	L2SentMessageEvent v1.L2SentMessageEventDB
 	CheckPoint         exporter.BridgeCheckpointDB
 	TokenList          business.TokenListDB
+	SyncCursors        common.SyncCursorsDB
+	ERC721Bridge       business.ERC721BridgeDB
+	SystemConfig       business.SystemConfigDB
+	FeeVault           business.FeeVaultDB
+	TokenPair          business.TokenPairDB
+	WithdrawalIncident business.WithdrawalIncidentDB
+	BridgeStatus       business.BridgeStatusHistoryView
 }
 
 func NewDB(ctx context.Context, log log.Logger, dbConfig config.DBConfig) (*DB, error) {
@@ -85,6 +93,7 @@ func NewDB(ctx context.Context, log log.Logger, dbConfig config.DBConfig) (*DB,
 		WithdrawProven:     event.NewWithdrawProvenDB(gorm),
 		WithdrawFinalized:  event.NewWithdrawFinalizedDB(gorm),
 		RelayMessage:       event.NewRelayMessageDB(gorm),
+		RelayAttempt:       event.NewRelayAttemptDB(gorm),
 		StateRoots:         business.NewStateRootDB(gorm),
 		DataStore:          business.NewDataStoreDB(gorm),
 		L1ToL2:             business.NewL1ToL2DB(gorm),
@@ -93,6 +102,13 @@ func NewDB(ctx context.Context, log log.Logger, dbConfig config.DBConfig) (*DB,
 		L2SentMessageEvent: v1.NewL2SentMessageEventDB(gorm),
 		CheckPoint:         exporter.NewBridgeCheckpointDB(gorm),
 		TokenList:          business.NewTokenListDB(gorm),
+		SyncCursors:        common.NewSyncCursorsDB(gorm),
+		ERC721Bridge:       business.NewERC721BridgeDB(gorm),
+		SystemConfig:       business.NewSystemConfigDB(gorm),
+		FeeVault:           business.NewFeeVaultDB(gorm),
+		TokenPair:          business.NewTokenPairDB(gorm),
+		WithdrawalIncident: business.NewWithdrawalIncidentDB(gorm),
+		BridgeStatus:       business.NewBridgeStatusHistoryDB(gorm),
 	}
 	return db, nil
 }
@@ -109,6 +125,7 @@ func (db *DB) Transaction(fn func(db *DB) error) error {
 			WithdrawProven:     event.NewWithdrawProvenDB(tx),
 			WithdrawFinalized:  event.NewWithdrawFinalizedDB(tx),
 			RelayMessage:       event.NewRelayMessageDB(tx),
+			RelayAttempt:       event.NewRelayAttemptDB(tx),
 			DataStore:          business.NewDataStoreDB(tx),
 			L1ToL2:             business.NewL1ToL2DB(tx),
 			L2ToL1:             business.NewL21ToL1DB(tx),
@@ -117,11 +134,28 @@ func (db *DB) Transaction(fn func(db *DB) error) error {
 			L2SentMessageEvent: v1.NewL2SentMessageEventDB(tx),
 			CheckPoint:         exporter.NewBridgeCheckpointDB(tx),
 			TokenList:          business.NewTokenListDB(tx),
+			SyncCursors:        common.NewSyncCursorsDB(tx),
+			ERC721Bridge:       business.NewERC721BridgeDB(tx),
+			SystemConfig:       business.NewSystemConfigDB(tx),
+			FeeVault:           business.NewFeeVaultDB(tx),
+			TokenPair:          business.NewTokenPairDB(tx),
+			WithdrawalIncident: business.NewWithdrawalIncidentDB(tx),
+			BridgeStatus:       business.NewBridgeStatusHistoryDB(tx),
 		}
 		return fn(txDB)
 	})
 }
 
+// TruncateTables removes every row of the supplied tables
+func (db *DB) TruncateTables(tables ...string) error {
+	for _, table := range tables {
+		if err := db.gorm.Exec(fmt.Sprintf("TRUNCATE TABLE %s", table)).Error; err != nil {
+			return fmt.Errorf("unable to truncate %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
 func (db *DB) Close() error {
 	sql, err := db.gorm.DB()
 	if err != nil {
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"strings"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -84,6 +85,7 @@ type ContractEventsView interface {
 	L2LatestContractEventWithFilter(ContractEvent) (*L2ContractEvent, error)
 
 	ContractEventsWithFilter(ContractEvent, string, *big.Int, *big.Int) ([]ContractEvent, error)
+	ContractEventsWithFilters([]ContractEvent, string, *big.Int, *big.Int) ([]ContractEvent, error)
 }
 
 type ContractEventsDB interface {
@@ -91,6 +93,9 @@ type ContractEventsDB interface {
 
 	StoreL1ContractEvents([]L1ContractEvent) error
 	StoreL2ContractEvents([]L2ContractEvent) error
+
+	DeleteL1ContractEvents(from, to *big.Int) (int64, error)
+	DeleteL2ContractEvents(from, to *big.Int) (int64, error)
 }
 
 /**
@@ -184,8 +189,22 @@ func (db *contractEventsDB) L1LatestContractEventWithFilter(filter ContractEvent
 	return &l1ContractEvent, nil
 }
 
+// DeleteL1ContractEvents removes the events of the headers within the supplied range
+func (db *contractEventsDB) DeleteL1ContractEvents(from, to *big.Int) (int64, error) {
+	headers := db.gorm.Table("l1_block_headers").Where("number >= ? AND number <= ?", from, to).Select("hash")
+	result := db.gorm.Where("block_hash IN (?)", headers).Delete(&L1ContractEvent{})
+	return result.RowsAffected, result.Error
+}
+
 // L2
 
+// DeleteL2ContractEvents removes the events of the headers within the supplied range
+func (db *contractEventsDB) DeleteL2ContractEvents(from, to *big.Int) (int64, error) {
+	headers := db.gorm.Table("l2_block_headers").Where("number >= ? AND number <= ?", from, to).Select("hash")
+	result := db.gorm.Where("block_hash IN (?)", headers).Delete(&L2ContractEvent{})
+	return result.RowsAffected, result.Error
+}
+
 func (db *contractEventsDB) StoreL2ContractEvents(events []L2ContractEvent) error {
 	result := db.gorm.CreateInBatches(&events, utils.BatchInsertSize)
 	return result.Error
@@ -292,3 +311,39 @@ func (db *contractEventsDB) ContractEventsWithFilter(filter ContractEvent, chain
 		return nil, errors.New("expected 'l1' or 'l2' for chain selection")
 	}
 }
+
+// ContractEventsWithFilters retrieves the contract events within the specified range matching the contract
+// address and event signature of any of the filters, according to the `chainSelector`. Events are ordered by block number and log index.
+func (db *contractEventsDB) ContractEventsWithFilters(filters []ContractEvent, chainSelector string, fromHeight, toHeight *big.Int) ([]ContractEvent, error) {
+	if len(filters) == 0 {
+		return nil, nil
+	}
+	if chainSelector != "l1" && chainSelector != "l2" {
+		return nil, errors.New("expected 'l1' or 'l2' for chain selection")
+	}
+	if fromHeight == nil || toHeight == nil {
+		return nil, errors.New("height range unspecified")
+	}
+	if fromHeight.Cmp(toHeight) > 0 {
+		return nil, fmt.Errorf("fromHeight %d is greater than toHeight %d", fromHeight, toHeight)
+	}
+
+	eventsTable, headersTable := chainSelector+"_contract_events", chainSelector+"_block_headers"
+	conditions := make([]string, len(filters))
+	args := make([]interface{}, 0, 2*len(filters))
+	for i := range filters {
+		conditions[i] = fmt.Sprintf("(%s.contract_address = ? AND %s.event_signature = ?)", eventsTable, eventsTable)
+		args = append(args, strings.ToLower(filters[i].ContractAddress.String()), filters[i].EventSignature.String())
+	}
+	query := db.gorm.Table(eventsTable).Where(strings.Join(conditions, " OR "), args...)
+	query = query.Joins(fmt.Sprintf("INNER JOIN %s ON %s.block_hash = %s.hash", headersTable, eventsTable, headersTable))
+	query = query.Where(headersTable+".number >= ? AND "+headersTable+".number <= ?", fromHeight, toHeight)
+	query = query.Order(headersTable + ".number ASC, " + eventsTable + ".log_index ASC").Select(eventsTable + ".*")
+
+	var events []ContractEvent
+	result := query.Find(&events)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return events, nil
+}
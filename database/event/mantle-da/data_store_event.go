@@ -24,6 +24,8 @@ func (DataStoreEvent) TableName() string {
 type DataStoreEventDB interface {
 	DataStoreEventView
 	StoreBatchDataStoreEvent([]DataStoreEvent) error
+	RollbackDataStoreEvents(timestamp uint64) error
+	DeleteDataStoreEvents(fromTimestamp, toTimestamp uint64) (int64, error)
 }
 
 type DataStoreEventView interface {
@@ -56,3 +58,15 @@ func (de dataStoreEventDB) StoreBatchDataStoreEvent(events []DataStoreEvent) err
 	result := de.gorm.CreateInBatches(&events, len(events))
 	return result.Error
 }
+
+// RollbackDataStoreEvents removes every event emitted after the supplied L1 block timestamp.
+func (de dataStoreEventDB) RollbackDataStoreEvents(timestamp uint64) error {
+	result := de.gorm.Where("timestamp > ?", timestamp).Delete(&DataStoreEvent{})
+	return result.Error
+}
+
+// DeleteDataStoreEvents removes every event emitted within the supplied range of L1 block timestamps
+func (de dataStoreEventDB) DeleteDataStoreEvents(fromTimestamp, toTimestamp uint64) (int64, error) {
+	result := de.gorm.Where("timestamp >= ? AND timestamp <= ?", fromTimestamp, toTimestamp).Delete(&DataStoreEvent{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,83 @@
+package event
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// RelayAttempt is a relay of a cross domain message by the messenger of the destination chain, from
+// its RelayedMessage or FailedRelayedMessage event. A failed message can be replayed, every attempt is kept.
+type RelayAttempt struct {
+	GUID            uuid.UUID   `gorm:"primaryKey" json:"guid"`
+	Chain           string      `gorm:"column:chain" json:"chain"`
+	MessageHash     common.Hash `gorm:"serializer:bytes;column:message_hash" json:"messageHash"`
+	BlockNumber     *big.Int    `gorm:"serializer:u256;column:block_number" json:"blockNumber"`
+	TransactionHash common.Hash `gorm:"serializer:bytes;column:transaction_hash" json:"transactionHash"`
+	LogIndex        uint64      `gorm:"column:log_index" json:"logIndex"`
+	Success         bool        `gorm:"column:success" json:"success"`
+	Timestamp       uint64      `gorm:"column:timestamp" json:"timestamp"`
+}
+
+func (RelayAttempt) TableName() string {
+	return "relay_attempt"
+}
+
+type RelayAttemptDB interface {
+	RelayAttemptView
+	StoreRelayAttempts([]RelayAttempt) error
+	RollbackRelayAttempts(chain string, height *big.Int) error
+	DeleteRelayAttempts(chain string, from, to *big.Int) (int64, error)
+}
+
+type RelayAttemptView interface {
+	RelayAttemptsByMessageHashes(chain string, messageHashes []common.Hash) (map[common.Hash][]RelayAttempt, error)
+}
+
+type relayAttemptDB struct {
+	gorm *gorm.DB
+}
+
+func NewRelayAttemptDB(db *gorm.DB) RelayAttemptDB {
+	return &relayAttemptDB{gorm: db}
+}
+
+func (db relayAttemptDB) StoreRelayAttempts(attempts []RelayAttempt) error {
+	result := db.gorm.CreateInBatches(&attempts, len(attempts))
+	return result.Error
+}
+
+// RelayAttemptsByMessageHashes returns the relay attempts of the messages on the chain, oldest first
+func (db relayAttemptDB) RelayAttemptsByMessageHashes(chain string, messageHashes []common.Hash) (map[common.Hash][]RelayAttempt, error) {
+	if len(messageHashes) == 0 {
+		return nil, nil
+	}
+	hashes := make([]string, len(messageHashes))
+	for i := range messageHashes {
+		hashes[i] = strings.ToLower(messageHashes[i].String())
+	}
+	var attempts []RelayAttempt
+	result := db.gorm.Where("chain = ? AND message_hash IN ?", chain, hashes).Order("block_number ASC, log_index ASC").Find(&attempts)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	attemptsByMessage := make(map[common.Hash][]RelayAttempt)
+	for _, attempt := range attempts {
+		attemptsByMessage[attempt.MessageHash] = append(attemptsByMessage[attempt.MessageHash], attempt)
+	}
+	return attemptsByMessage, nil
+}
+
+func (db relayAttemptDB) RollbackRelayAttempts(chain string, height *big.Int) error {
+	result := db.gorm.Where("chain = ? AND block_number > ?", chain, height).Delete(&RelayAttempt{})
+	return result.Error
+}
+
+func (db relayAttemptDB) DeleteRelayAttempts(chain string, from, to *big.Int) (int64, error) {
+	result := db.gorm.Where("chain = ? AND block_number >= ? AND block_number <= ?", chain, from, to).Delete(&RelayAttempt{})
+	return result.RowsAffected, result.Error
+}
@@ -36,6 +36,9 @@ type RelayMessageDB interface {
 	StoreRelayMessage([]RelayMessage) error
 	MarkedRelayMessageRelated(relayMessageList []RelayMessage) error
 	UpdateRelayMessageInfo(relayMessageList []RelayMessage) error
+	RollbackRelayMessage(*big.Int) error
+	DeleteRelayMessages(from, to *big.Int) (int64, error)
+	ResetRelayMessageRelated(l1From, l1To *big.Int) (int64, error)
 }
 
 type RelayMessageView interface {
@@ -119,3 +122,23 @@ func (rm relayMessageDB) RelayMessageUnRelatedList() ([]RelayMessage, error) {
 	}
 	return unRelatedRelayList, nil
 }
+
+func (rm relayMessageDB) RollbackRelayMessage(height *big.Int) error {
+	result := rm.gorm.Where("block_number > ?", height).Delete(&RelayMessage{})
+	return result.Error
+}
+
+func (rm relayMessageDB) DeleteRelayMessages(from, to *big.Int) (int64, error) {
+	result := rm.gorm.Where("block_number >= ? AND block_number <= ?", from, to).Delete(&RelayMessage{})
+	return result.RowsAffected, result.Error
+}
+
+// ResetRelayMessageRelated marks the relays of the deposits initiated within the supplied L1 range
+// as unrelated, so they are matched again once the deposits are re-indexed. Must be called before
+// the deposits themselves are deleted.
+func (rm relayMessageDB) ResetRelayMessageRelated(l1From, l1To *big.Int) (int64, error) {
+	deposits := rm.gorm.Table("l1_to_l2").Where("l1_block_number >= ? AND l1_block_number <= ?", l1From, l1To).Select("message_hash")
+	result := rm.gorm.Model(&RelayMessage{}).Where("related = ? AND message_hash IN (?)", true, deposits).
+		Updates(map[string]interface{}{"related": false})
+	return result.RowsAffected, result.Error
+}
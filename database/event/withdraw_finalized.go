@@ -9,8 +9,6 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
-
-	common2 "github.com/mantlenetworkio/lithosphere/database/common"
 )
 
 type WithdrawFinalized struct {
@@ -24,7 +22,9 @@ type WithdrawFinalized struct {
 	ETHAmount                *big.Int       `gorm:"serializer:u256;column:eth_amount"`
 	ERC20Amount              *big.Int       `gorm:"serializer:u256;column:erc20_amount"`
 	Related                  bool           `json:"related"`
-	Timestamp                uint64
+	// BridgeFinalized is set once the amounts released by the L1 bridge have been bound to the finalization
+	BridgeFinalized bool `json:"bridgeFinalized"`
+	Timestamp       uint64
 }
 
 func (WithdrawFinalized) TableName() string {
@@ -36,10 +36,12 @@ type WithdrawFinalizedDB interface {
 	StoreWithdrawFinalized([]WithdrawFinalized) error
 	MarkedWithdrawFinalizedRelated(withdrawFinalizedList []WithdrawFinalized) error
 	UpdateWithdrawFinalizedInfo(withdrawFinalizedList []WithdrawFinalized) error
+	RollbackWithdrawFinalized(*big.Int) error
+	DeleteWithdrawFinalized(from, to *big.Int) (int64, error)
+	ResetWithdrawFinalizedRelated(l2From, l2To *big.Int) (int64, error)
 }
 
 type WithdrawFinalizedView interface {
-	WithdrawFinalizedL1BlockHeader() (*common2.L1BlockHeader, error)
 	WithdrawFinalizedUnRelatedList() ([]WithdrawFinalized, error)
 }
 
@@ -51,19 +53,6 @@ func NewWithdrawFinalizedDB(db *gorm.DB) WithdrawFinalizedDB {
 	return &withdrawFinalizedDB{gorm: db}
 }
 
-func (w withdrawFinalizedDB) WithdrawFinalizedL1BlockHeader() (*common2.L1BlockHeader, error) {
-	l1Query := w.gorm.Where("number = (?)", w.gorm.Table("withdraw_finalized").Select("MAX(block_number)"))
-	var l1Header common2.L1BlockHeader
-	result := l1Query.Take(&l1Header)
-	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, nil
-		}
-		return nil, result.Error
-	}
-	return &l1Header, nil
-}
-
 func (w withdrawFinalizedDB) StoreWithdrawFinalized(withdrawFinalizedList []WithdrawFinalized) error {
 	result := w.gorm.CreateInBatches(&withdrawFinalizedList, len(withdrawFinalizedList))
 	return result.Error
@@ -103,6 +92,7 @@ func (w withdrawFinalizedDB) UpdateWithdrawFinalizedInfo(withdrawFinalizedList [
 		withdrawFinalizeds.ETHAmount = withdrawFinalizedList[i].ETHAmount
 		withdrawFinalizeds.ERC20Amount = withdrawFinalizedList[i].ERC20Amount
 		withdrawFinalizeds.MessageHash = withdrawFinalizedList[i].MessageHash
+		withdrawFinalizeds.BridgeFinalized = true
 		err := w.gorm.Save(withdrawFinalizeds).Error
 		if err != nil {
 			return err
@@ -119,3 +109,23 @@ func (w withdrawFinalizedDB) WithdrawFinalizedUnRelatedList() ([]WithdrawFinaliz
 	}
 	return unRelatedFinalizedList, nil
 }
+
+func (w withdrawFinalizedDB) RollbackWithdrawFinalized(height *big.Int) error {
+	result := w.gorm.Where("block_number > ?", height).Delete(&WithdrawFinalized{})
+	return result.Error
+}
+
+func (w withdrawFinalizedDB) DeleteWithdrawFinalized(from, to *big.Int) (int64, error) {
+	result := w.gorm.Where("block_number >= ? AND block_number <= ?", from, to).Delete(&WithdrawFinalized{})
+	return result.RowsAffected, result.Error
+}
+
+// ResetWithdrawFinalizedRelated marks the events of the withdrawals initiated within the supplied L2 range as
+// unrelated, so they are matched again once the withdrawals are re-indexed. Must be called before
+// the withdrawals themselves are deleted.
+func (w withdrawFinalizedDB) ResetWithdrawFinalizedRelated(l2From, l2To *big.Int) (int64, error) {
+	withdrawals := w.gorm.Table("l2_to_l1").Where("l2_block_number >= ? AND l2_block_number <= ?", l2From, l2To).Select("withdraw_transaction_hash")
+	result := w.gorm.Model(&WithdrawFinalized{}).Where("related = ? AND withdraw_hash IN (?)", true, withdrawals).
+		Updates(map[string]interface{}{"related": false})
+	return result.RowsAffected, result.Error
+}
@@ -9,7 +9,6 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/ethereum/go-ethereum/common"
-	common2 "github.com/mantlenetworkio/lithosphere/database/common"
 )
 
 type WithdrawProven struct {
@@ -23,7 +22,9 @@ type WithdrawProven struct {
 	ETHAmount             *big.Int       `gorm:"serializer:u256;column:eth_amount"`
 	ERC20Amount           *big.Int       `gorm:"serializer:u256;column:erc20_amount"`
 	Related               bool           `json:"related"`
-	Timestamp             uint64
+	// Invalidated is set when the proof may have been made against an output deleted by the challenger
+	Invalidated bool `json:"invalidated"`
+	Timestamp   uint64
 }
 
 func (WithdrawProven) TableName() string {
@@ -35,10 +36,14 @@ type WithdrawProvenDB interface {
 	StoreWithdrawProven([]WithdrawProven) error
 	MarkedWithdrawProvenRelated(withdrawProvenList []WithdrawProven) error
 	UpdateWithdrawProvenInfo(withdrawProvenList []WithdrawProven) error
+	RollbackWithdrawProven(*big.Int) error
+	DeleteWithdrawProven(from, to *big.Int) (int64, error)
+	ResetWithdrawProvenRelated(l2From, l2To *big.Int) (int64, error)
+	InvalidateWithdrawProven() (int64, error)
+	RevalidateWithdrawProven() (int64, error)
 }
 
 type WithdrawProvenView interface {
-	WithdrawProvenL1BlockHeader() (*common2.L1BlockHeader, error)
 	WithdrawProvenUnRelatedList() ([]WithdrawProven, error)
 }
 
@@ -50,19 +55,6 @@ func NewWithdrawProvenDB(db *gorm.DB) WithdrawProvenDB {
 	return &withdrawProvenDB{gorm: db}
 }
 
-func (w withdrawProvenDB) WithdrawProvenL1BlockHeader() (*common2.L1BlockHeader, error) {
-	l1Query := w.gorm.Where("number = (?)", w.gorm.Table("withdraw_proven").Select("MAX(block_number)"))
-	var l1Header common2.L1BlockHeader
-	result := l1Query.Take(&l1Header)
-	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, nil
-		}
-		return nil, result.Error
-	}
-	return &l1Header, nil
-}
-
 func (w withdrawProvenDB) StoreWithdrawProven(withdrawProvenList []WithdrawProven) error {
 	result := w.gorm.CreateInBatches(&withdrawProvenList, len(withdrawProvenList))
 	return result.Error
@@ -71,7 +63,8 @@ func (w withdrawProvenDB) StoreWithdrawProven(withdrawProvenList []WithdrawProve
 func (w withdrawProvenDB) MarkedWithdrawProvenRelated(withdrawProvenList []WithdrawProven) error {
 	for i := 0; i < len(withdrawProvenList); i++ {
 		var withdrawProvens = WithdrawProven{}
-		result := w.gorm.Where(&WithdrawProven{WithdrawHash: withdrawProvenList[i].WithdrawHash}).Take(&withdrawProvens)
+		// a withdrawal is proven again once the output of its previous proof has been deleted
+		result := w.gorm.Where(&WithdrawProven{WithdrawHash: withdrawProvenList[i].WithdrawHash, ProvenTransactionHash: withdrawProvenList[i].ProvenTransactionHash}).Take(&withdrawProvens)
 		if result.Error != nil {
 			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 				return nil
@@ -112,9 +105,54 @@ func (w withdrawProvenDB) UpdateWithdrawProvenInfo(withdrawProvenList []Withdraw
 
 func (w withdrawProvenDB) WithdrawProvenUnRelatedList() ([]WithdrawProven, error) {
 	var unRelatedProvenList []WithdrawProven
-	err := w.gorm.Table("withdraw_proven").Where("related = ?", false).Find(&unRelatedProvenList).Error
+	err := w.gorm.Table("withdraw_proven").Where("related = ? AND invalidated = ?", false, false).Find(&unRelatedProvenList).Error
 	if err != nil {
 		log.Error("get unrelated withdraw proven fail", "err", err)
 	}
 	return unRelatedProvenList, nil
 }
+
+func (w withdrawProvenDB) RollbackWithdrawProven(height *big.Int) error {
+	result := w.gorm.Where("block_number > ?", height).Delete(&WithdrawProven{})
+	return result.Error
+}
+
+func (w withdrawProvenDB) DeleteWithdrawProven(from, to *big.Int) (int64, error) {
+	result := w.gorm.Where("block_number >= ? AND block_number <= ?", from, to).Delete(&WithdrawProven{})
+	return result.RowsAffected, result.Error
+}
+
+// ResetWithdrawProvenRelated marks the events of the withdrawals initiated within the supplied L2 range as
+// unrelated, so they are matched again once the withdrawals are re-indexed. Must be called before
+// the withdrawals themselves are deleted.
+func (w withdrawProvenDB) ResetWithdrawProvenRelated(l2From, l2To *big.Int) (int64, error) {
+	withdrawals := w.gorm.Table("l2_to_l1").Where("l2_block_number >= ? AND l2_block_number <= ?", l2From, l2To).Select("withdraw_transaction_hash")
+	result := w.gorm.Model(&WithdrawProven{}).Where("related = ? AND withdraw_hash IN (?)", true, withdrawals).
+		Updates(map[string]interface{}{"related": false})
+	return result.RowsAffected, result.Error
+}
+
+// deletedOutputProofs selects the deleted outputs a proof may have been made against: the ones covering its
+// withdrawal, proposed at or before the proof and deleted at or after it
+func (w withdrawProvenDB) deletedOutputProofs() *gorm.DB {
+	withdrawalL2BlockNumber := "(SELECT l2_block_number FROM l2_to_l1 WHERE l2_to_l1.withdraw_transaction_hash = withdraw_proven.withdraw_hash LIMIT 1)"
+	return w.gorm.Table("state_root").Select("1").
+		Where("canonical = ? AND state_root.l1_block_number <= withdraw_proven.block_number AND state_root.deleted_l1_block_number >= withdraw_proven.block_number", false).
+		Where("state_root.l2_block_number >= " + withdrawalL2BlockNumber)
+}
+
+// InvalidateWithdrawProven flags the proofs which may have been made against a deleted output. The
+// proofs are not matched to their withdrawals anymore, the withdrawals have to be proven again.
+func (w withdrawProvenDB) InvalidateWithdrawProven() (int64, error) {
+	result := w.gorm.Model(&WithdrawProven{}).Where("invalidated = ? AND EXISTS (?)", false, w.deletedOutputProofs()).
+		Updates(map[string]interface{}{"invalidated": true})
+	return result.RowsAffected, result.Error
+}
+
+// RevalidateWithdrawProven clears the flag of the proofs whose deleted outputs have been restored by a
+// rollback, they are matched to their withdrawals again. Must be called after the state roots are rolled back.
+func (w withdrawProvenDB) RevalidateWithdrawProven() (int64, error) {
+	result := w.gorm.Model(&WithdrawProven{}).Where("invalidated = ? AND NOT EXISTS (?)", true, w.deletedOutputProofs()).
+		Updates(map[string]interface{}{"invalidated": false, "related": false})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"gorm.io/gorm"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/mantlenetworkio/lithosphere/common/bigint"
+	"github.com/mantlenetworkio/lithosphere/database"
+	common2 "github.com/mantlenetworkio/lithosphere/database/common"
+	"github.com/mantlenetworkio/lithosphere/database/event"
+)
+
+// Key selects the events routed to a handler, the event signature logs of a contract on either chain
+type Key struct {
+	Chain          string
+	Contract       common.Address
+	EventSignature common.Hash
+}
+
+// Handler indexes the events of contracts outside of the bridge and rollup processors. The events
+// matching its keys are extracted by the synchronizer of their chain and fed to the handler by the
+// event processor, with a cursor, metrics and failures of its own.
+//
+// Its events are only extracted from the run it is first registered in. A handler registered on an
+// indexed database doesn't start until the blocks indexed beforehand, from the starting height on,
+// are reindexed, which moves its extraction cursor back.
+type Handler interface {
+	// Name identifies the handler in its cursor, logs and metrics. It must not change across restarts.
+	Name() string
+
+	// HandleEvent is called for every event matching the keys of the handler, in block and log order,
+	// within the transaction moving the handler cursor. An error aborts the whole range, retried on the next run.
+	HandleEvent(log log.Logger, tx *database.DB, event event.ContractEvent) error
+
+	// Rollback removes the state derived from the events of the blocks above height, within the
+	// transaction of the synchronizer rolling back a reorg of the handler chain
+	Rollback(tx *database.DB, height *big.Int) error
+}
+
+// Registration is a handler along with the keys it was registered with
+type Registration struct {
+	Handler Handler
+	Chain   string
+	Keys    []Key
+}
+
+// Registry holds the handlers run by the event processor. Handlers are registered at startup, the
+// registry is read from once the synchronizers and the event processor are initialized.
+type Registry struct {
+	mu            sync.Mutex
+	registrations []Registration
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// DefaultRegistry is the registry of the handlers run by the lithosphere
+var DefaultRegistry = NewRegistry()
+
+// Register adds the handler to the DefaultRegistry. It is meant to be called from the init function
+// of the package implementing the handler and panics if the registration is invalid.
+func Register(handler Handler, keys ...Key) {
+	if err := DefaultRegistry.Register(handler, keys...); err != nil {
+		panic(err)
+	}
+}
+
+// Register adds the handler for the events matching the keys, which must all be of the same chain
+func (r *Registry) Register(handler Handler, keys ...Key) error {
+	if handler == nil || handler.Name() == "" {
+		return errors.New("handler must be named")
+	} else if len(keys) == 0 {
+		return fmt.Errorf("handler %s registered without keys", handler.Name())
+	}
+	chain := keys[0].Chain
+	for _, key := range keys {
+		if key.Chain != common2.SyncCursorLayerL1 && key.Chain != common2.SyncCursorLayerL2 {
+			return fmt.Errorf("handler %s: expected 'l1' or 'l2' chain, got %q", handler.Name(), key.Chain)
+		} else if key.Chain != chain {
+			return fmt.Errorf("handler %s: keys span both chains", handler.Name())
+		} else if key.Contract == (common.Address{}) || key.EventSignature == (common.Hash{}) {
+			return fmt.Errorf("handler %s: key without contract or event signature", handler.Name())
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, registration := range r.registrations {
+		if registration.Handler.Name() == handler.Name() {
+			return fmt.Errorf("handler %s already registered", handler.Name())
+		}
+	}
+	r.registrations = append(r.registrations, Registration{Handler: handler, Chain: chain, Keys: keys})
+	return nil
+}
+
+// Registrations returns the handlers registered for the chain, in registration order
+func (r *Registry) Registrations(chain string) []Registration {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var registrations []Registration
+	for _, registration := range r.registrations {
+		if registration.Chain == chain {
+			registrations = append(registrations, registration)
+		}
+	}
+	return registrations
+}
+
+// Rollback rolls back the handlers of the chain above height
+func (r *Registry) Rollback(tx *database.DB, chain string, height *big.Int) error {
+	for _, registration := range r.Registrations(chain) {
+		if err := registration.Handler.Rollback(tx, height); err != nil {
+			return fmt.Errorf("failed to rollback handler %s: %w", registration.Handler.Name(), err)
+		}
+	}
+	return nil
+}
+
+// CursorName is the sync cursor of the handler, pointing to the last processed header of its chain
+func CursorName(handler Handler) string {
+	return "handler_" + handler.Name()
+}
+
+// ExtractionCursorName is the sync cursor pointing to the last header indexed before the synchronizer of its chain
+// started extracting the events of the handler. Its events up to that header were never extracted.
+func ExtractionCursorName(handler Handler) string {
+	return "extraction_handler_" + handler.Name()
+}
+
+// StoreExtractionCursors records that the events of the handlers of the chain registered since the last run are
+// extracted above the last traversed header, or from genesis when header is nil
+func (r *Registry) StoreExtractionCursors(db *database.DB, chain string, header *types.Header) error {
+	for _, registration := range r.Registrations(chain) {
+		name := ExtractionCursorName(registration.Handler)
+		cursor, err := db.SyncCursors.SyncCursor(name)
+		if err != nil {
+			return err
+		} else if cursor != nil {
+			continue
+		}
+		number, hash, timestamp := big.NewInt(0), common.Hash{}, uint64(0)
+		if header != nil {
+			number, hash, timestamp = header.Number, header.Hash(), header.Time
+		}
+		if err := db.SyncCursors.StoreSyncCursor(name, chain, number, hash, timestamp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReindexExtractionCursors moves the extraction cursors of the handlers of the chain below the reindexed [from, to]
+// range when it covers them, the events of every registered handler being extracted along the range
+func (r *Registry) ReindexExtractionCursors(tx *database.DB, chain string, from, to *big.Int) error {
+	below := new(big.Int).Sub(from, bigint.One)
+	for _, registration := range r.Registrations(chain) {
+		name := ExtractionCursorName(registration.Handler)
+		cursor, err := tx.SyncCursors.SyncCursor(name)
+		if err != nil {
+			return err
+		} else if cursor == nil || cursor.BlockNumber.Cmp(below) <= 0 || cursor.BlockNumber.Cmp(to) > 0 {
+			continue
+		}
+
+		var header *common2.BlockHeader
+		belowScope := func(db *gorm.DB) *gorm.DB { return db.Where("number = ?", below) }
+		if chain == common2.SyncCursorLayerL1 {
+			l1Header, err := tx.Blocks.L1BlockHeaderWithScope(belowScope)
+			if err != nil {
+				return err
+			} else if l1Header != nil {
+				header = &l1Header.BlockHeader
+			}
+		} else {
+			l2Header, err := tx.Blocks.L2BlockHeaderWithScope(belowScope)
+			if err != nil {
+				return err
+			} else if l2Header != nil {
+				header = &l2Header.BlockHeader
+			}
+		}
+		// only the headers with events are indexed
+		hash, timestamp := common.Hash{}, uint64(0)
+		if header != nil {
+			hash, timestamp = header.Hash, header.Timestamp
+		}
+		if err := tx.SyncCursors.StoreSyncCursor(name, chain, below, hash, timestamp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
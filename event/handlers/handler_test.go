@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mantlenetworkio/lithosphere/database"
+	"github.com/mantlenetworkio/lithosphere/database/event"
+)
+
+type namedHandler string
+
+func (h namedHandler) Name() string { return string(h) }
+
+func (h namedHandler) HandleEvent(log.Logger, *database.DB, event.ContractEvent) error { return nil }
+
+func (h namedHandler) Rollback(*database.DB, *big.Int) error { return nil }
+
+func TestRegistryRegister(t *testing.T) {
+	contract, signature := common.HexToAddress("0x01"), common.HexToHash("0x02")
+	l1Key := Key{Chain: "l1", Contract: contract, EventSignature: signature}
+	l2Key := Key{Chain: "l2", Contract: contract, EventSignature: signature}
+
+	registry := NewRegistry()
+	require.NoError(t, registry.Register(namedHandler("l1"), l1Key))
+	require.NoError(t, registry.Register(namedHandler("l2"), l2Key))
+
+	require.Error(t, registry.Register(namedHandler("l1"), l1Key), "duplicate name")
+	require.Error(t, registry.Register(namedHandler(""), l1Key), "unnamed")
+	require.Error(t, registry.Register(namedHandler("none")), "no keys")
+	require.Error(t, registry.Register(namedHandler("both"), l1Key, l2Key), "keys on both chains")
+	require.Error(t, registry.Register(namedHandler("l3"), Key{Chain: "l3", Contract: contract, EventSignature: signature}))
+	require.Error(t, registry.Register(namedHandler("zero"), Key{Chain: "l1", EventSignature: signature}))
+
+	l1Registrations := registry.Registrations("l1")
+	require.Len(t, l1Registrations, 1)
+	require.Equal(t, "l1", l1Registrations[0].Handler.Name())
+	require.Equal(t, []Key{l1Key}, l1Registrations[0].Keys)
+	require.Len(t, registry.Registrations("l2"), 1)
+
+	var nilRegistry *Registry
+	require.Empty(t, nilRegistry.Registrations("l1"))
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"math/big"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/mantlenetworkio/lithosphere/metrics"
+)
+
+var (
+	MetricsNamespace string = "op_indexer_handler"
+)
+
+type Metricer interface {
+	RecordInterval(handler string) (done func(err error))
+	RecordLatestHeight(handler string, height *big.Int)
+	RecordEvents(handler string, size int)
+}
+
+type handlerMetrics struct {
+	latestHeight *prometheus.GaugeVec
+
+	intervalTick     *prometheus.CounterVec
+	intervalDuration *prometheus.HistogramVec
+	intervalFailures *prometheus.CounterVec
+
+	events *prometheus.CounterVec
+}
+
+func NewMetrics(registry *prometheus.Registry) Metricer {
+	factory := metrics.With(registry)
+	return &handlerMetrics{
+		intervalTick: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "intervals_total",
+			Help:      "number of times the handler has run",
+		}, []string{
+			"handler",
+		}),
+		intervalDuration: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: MetricsNamespace,
+			Name:      "interval_seconds",
+			Help:      "duration elapsed in a run of the handler",
+		}, []string{
+			"handler",
+		}),
+		intervalFailures: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "interval_failures_total",
+			Help:      "number of failed runs of the handler",
+		}, []string{
+			"handler",
+		}),
+		latestHeight: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "height",
+			Help:      "the latest block height processed by the handler",
+		}, []string{
+			"handler",
+		}),
+		events: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "events_total",
+			Help:      "number of events handled",
+		}, []string{
+			"handler",
+		}),
+	}
+}
+
+func (m *handlerMetrics) RecordInterval(handler string) func(error) {
+	m.intervalTick.WithLabelValues(handler).Inc()
+	timer := prometheus.NewTimer(m.intervalDuration.WithLabelValues(handler))
+	return func(err error) {
+		timer.ObserveDuration()
+		if err != nil {
+			m.intervalFailures.WithLabelValues(handler).Inc()
+		}
+	}
+}
+
+func (m *handlerMetrics) RecordLatestHeight(handler string, height *big.Int) {
+	m.latestHeight.WithLabelValues(handler).Set(float64(height.Uint64()))
+}
+
+func (m *handlerMetrics) RecordEvents(handler string, size int) {
+	m.events.WithLabelValues(handler).Add(float64(size))
+}
@@ -0,0 +1,225 @@
+package bridge
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/google/uuid"
+
+	common2 "github.com/mantlenetworkio/lithosphere/common"
+	"github.com/mantlenetworkio/lithosphere/common/bigint"
+	"github.com/mantlenetworkio/lithosphere/database"
+	"github.com/mantlenetworkio/lithosphere/database/business"
+	"github.com/mantlenetworkio/lithosphere/database/event"
+	"github.com/mantlenetworkio/lithosphere/event/processors/contracts"
+)
+
+// The ERC721 bridges emit their events after sending, or relaying, the cross domain message. The
+// message hash is found from the messenger events of the same transaction closest to the bridge event.
+
+// l1ProcessInitiatedERC721Bridges stores the NFT deposits of the L1ERC721Bridge
+func l1ProcessInitiatedERC721Bridges(log log.Logger, db *database.DB, bridgeAddress common.Address, sentMessages []contracts.CrossDomainMessengerSentMessageEvent, fromHeight, toHeight *big.Int) error {
+	initiatedBridges, err := contracts.ERC721BridgeInitiatedEvents("l1", bridgeAddress, db, fromHeight, toHeight)
+	if err != nil {
+		return err
+	}
+	if len(initiatedBridges) == 0 {
+		return nil
+	}
+	log.Info("detected erc721 bridge deposits", "size", len(initiatedBridges))
+
+	sentMessageEvent := func(e *contracts.CrossDomainMessengerSentMessageEvent) *event.ContractEvent { return e.Event }
+	sentMessagesByTx := txEvents(sentMessages, sentMessageEvent)
+	deposits := make([]business.ERC721Bridge, len(initiatedBridges))
+	for i := range initiatedBridges {
+		initiatedBridge := initiatedBridges[i]
+		sentMessage := nearestTxEvent(sentMessagesByTx, sentMessageEvent, initiatedBridge.Event, true)
+		if sentMessage == nil {
+			log.Error("expected SentMessage preceding ERC721BridgeInitiated event", "tx_hash", initiatedBridge.Event.TransactionHash.String())
+			return fmt.Errorf("expected SentMessage preceding ERC721BridgeInitiated event. tx_hash = %s", initiatedBridge.Event.TransactionHash.String())
+		}
+		blockNumber, err := db.L1ToL2.GetBlockNumberFromHash(initiatedBridge.Event.BlockHash)
+		if err != nil {
+			return err
+		}
+		deposits[i] = business.ERC721Bridge{
+			GUID:               uuid.New(),
+			Direction:          business.ERC721Deposit,
+			MessageHash:        sentMessage.MessageHash,
+			L1BlockNumber:      blockNumber,
+			L2BlockNumber:      bigint.Zero,
+			L1TransactionHash:  initiatedBridge.Event.TransactionHash,
+			L1ProveBlockNumber: bigint.Zero,
+			Status:             common2.L1ToL2Pending,
+			FromAddress:        initiatedBridge.FromAddress,
+			ToAddress:          initiatedBridge.ToAddress,
+			L1TokenAddress:     initiatedBridge.LocalTokenAddress,
+			L2TokenAddress:     initiatedBridge.RemoteTokenAddress,
+			TokenId:            initiatedBridge.TokenId,
+			Timestamp:          int64(initiatedBridge.Timestamp),
+		}
+	}
+	return db.ERC721Bridge.StoreERC721Bridges(deposits)
+}
+
+// l2ProcessInitiatedERC721Bridges stores the NFT withdrawals of the L2ERC721Bridge
+func l2ProcessInitiatedERC721Bridges(log log.Logger, db *database.DB, bridgeAddress common.Address, messagesPassed []contracts.L2ToL1MessagePasserMessagePassed,
+	sentMessages []contracts.CrossDomainMessengerSentMessageEvent, fromHeight, toHeight *big.Int) error {
+	initiatedBridges, err := contracts.ERC721BridgeInitiatedEvents("l2", bridgeAddress, db, fromHeight, toHeight)
+	if err != nil {
+		return err
+	}
+	if len(initiatedBridges) == 0 {
+		return nil
+	}
+	log.Info("detected erc721 bridge withdrawals", "size", len(initiatedBridges))
+
+	messagePassedEvent := func(e *contracts.L2ToL1MessagePasserMessagePassed) *event.ContractEvent { return e.Event }
+	sentMessageEvent := func(e *contracts.CrossDomainMessengerSentMessageEvent) *event.ContractEvent { return e.Event }
+	messagesPassedByTx, sentMessagesByTx := txEvents(messagesPassed, messagePassedEvent), txEvents(sentMessages, sentMessageEvent)
+	withdrawals := make([]business.ERC721Bridge, len(initiatedBridges))
+	for i := range initiatedBridges {
+		initiatedBridge := initiatedBridges[i]
+		messagePassed := nearestTxEvent(messagesPassedByTx, messagePassedEvent, initiatedBridge.Event, true)
+		if messagePassed == nil {
+			log.Error("expected MessagePassed preceding ERC721BridgeInitiated event", "tx_hash", initiatedBridge.Event.TransactionHash.String())
+			return fmt.Errorf("expected MessagePassed preceding ERC721BridgeInitiated event. tx_hash = %s", initiatedBridge.Event.TransactionHash.String())
+		}
+		sentMessage := nearestTxEvent(sentMessagesByTx, sentMessageEvent, initiatedBridge.Event, true)
+		if sentMessage == nil {
+			log.Error("expected SentMessage preceding ERC721BridgeInitiated event", "tx_hash", initiatedBridge.Event.TransactionHash.String())
+			return fmt.Errorf("expected SentMessage preceding ERC721BridgeInitiated event. tx_hash = %s", initiatedBridge.Event.TransactionHash.String())
+		}
+		blockNumber, err := db.L2ToL1.GetBlockNumberFromHash(initiatedBridge.Event.BlockHash)
+		if err != nil {
+			return err
+		}
+		withdrawals[i] = business.ERC721Bridge{
+			GUID:               uuid.New(),
+			Direction:          business.ERC721Withdrawal,
+			MessageHash:        sentMessage.MessageHash,
+			WithdrawalHash:     messagePassed.WithdrawalHash,
+			L1BlockNumber:      bigint.Zero,
+			L2BlockNumber:      blockNumber,
+			L2TransactionHash:  initiatedBridge.Event.TransactionHash,
+			L1ProveBlockNumber: bigint.Zero,
+			Status:             common2.L2ToL1Pending,
+			FromAddress:        initiatedBridge.FromAddress,
+			ToAddress:          initiatedBridge.ToAddress,
+			L1TokenAddress:     initiatedBridge.RemoteTokenAddress,
+			L2TokenAddress:     initiatedBridge.LocalTokenAddress,
+			TokenId:            initiatedBridge.TokenId,
+			Timestamp:          int64(initiatedBridge.Timestamp),
+		}
+	}
+	return db.ERC721Bridge.StoreERC721Bridges(withdrawals)
+}
+
+// l1ProcessProvenERC721Bridges moves the NFT withdrawals proven on L1 into their challenge period
+func l1ProcessProvenERC721Bridges(db *database.DB, provenWithdrawals []event.WithdrawProven) error {
+	proven := make([]business.ERC721Bridge, len(provenWithdrawals))
+	for i := range provenWithdrawals {
+		proven[i] = business.ERC721Bridge{
+			WithdrawalHash:     provenWithdrawals[i].WithdrawHash,
+			L1ProveBlockNumber: provenWithdrawals[i].BlockNumber,
+			L1ProveTxHash:      provenWithdrawals[i].ProvenTransactionHash,
+			L1ProvenTimestamp:  int64(provenWithdrawals[i].Timestamp),
+		}
+	}
+	return db.ERC721Bridge.MarkERC721WithdrawalsProven(proven)
+}
+
+// l1ProcessFinalizedERC721Bridges claims the NFT withdrawals finalized by the L1ERC721Bridge
+func l1ProcessFinalizedERC721Bridges(log log.Logger, db *database.DB, bridgeAddress common.Address, relayedMessages []contracts.CrossDomainMessengerRelayedMessageEvent, fromHeight, toHeight *big.Int) error {
+	finalizedBridges, err := finalizedERC721Bridges(log, "l1", db, bridgeAddress, relayedMessages, fromHeight, toHeight)
+	if err != nil || len(finalizedBridges) == 0 {
+		return err
+	}
+	finalized := make([]business.ERC721Bridge, len(finalizedBridges))
+	for i := range finalizedBridges {
+		blockNumber, err := db.L1ToL2.GetBlockNumberFromHash(finalizedBridges[i].Event.BlockHash)
+		if err != nil {
+			return err
+		}
+		finalized[i] = business.ERC721Bridge{
+			MessageHash:       finalizedBridges[i].MessageHash,
+			L1BlockNumber:     blockNumber,
+			L1TransactionHash: finalizedBridges[i].Event.TransactionHash,
+		}
+	}
+	return db.ERC721Bridge.MarkERC721WithdrawalsFinalized(finalized)
+}
+
+// l2ProcessFinalizedERC721Bridges claims the NFT deposits finalized by the L2ERC721Bridge
+func l2ProcessFinalizedERC721Bridges(log log.Logger, db *database.DB, bridgeAddress common.Address, relayedMessages []contracts.CrossDomainMessengerRelayedMessageEvent, fromHeight, toHeight *big.Int) error {
+	finalizedBridges, err := finalizedERC721Bridges(log, "l2", db, bridgeAddress, relayedMessages, fromHeight, toHeight)
+	if err != nil || len(finalizedBridges) == 0 {
+		return err
+	}
+	finalized := make([]business.ERC721Bridge, len(finalizedBridges))
+	for i := range finalizedBridges {
+		blockNumber, err := db.L2ToL1.GetBlockNumberFromHash(finalizedBridges[i].Event.BlockHash)
+		if err != nil {
+			return err
+		}
+		finalized[i] = business.ERC721Bridge{
+			MessageHash:       finalizedBridges[i].MessageHash,
+			L2BlockNumber:     blockNumber,
+			L2TransactionHash: finalizedBridges[i].Event.TransactionHash,
+		}
+	}
+	return db.ERC721Bridge.MarkERC721DepositsFinalized(finalized)
+}
+
+// finalizedERC721Bridges returns the relayed message of every ERC721BridgeFinalized event in the range
+func finalizedERC721Bridges(log log.Logger, chainSelector string, db *database.DB, bridgeAddress common.Address, relayedMessages []contracts.CrossDomainMessengerRelayedMessageEvent, fromHeight, toHeight *big.Int) ([]*contracts.CrossDomainMessengerRelayedMessageEvent, error) {
+	finalizedBridges, err := contracts.ERC721BridgeFinalizedEvents(chainSelector, bridgeAddress, db, fromHeight, toHeight)
+	if err != nil {
+		return nil, err
+	}
+	if len(finalizedBridges) == 0 {
+		return nil, nil
+	}
+	log.Info("detected finalized erc721 bridges", "size", len(finalizedBridges))
+
+	relayedMessageEvent := func(e *contracts.CrossDomainMessengerRelayedMessageEvent) *event.ContractEvent { return e.Event }
+	relayedMessagesByTx := txEvents(relayedMessages, relayedMessageEvent)
+	finalized := make([]*contracts.CrossDomainMessengerRelayedMessageEvent, len(finalizedBridges))
+	for i := range finalizedBridges {
+		finalizedBridge := finalizedBridges[i]
+		relayedMessage := nearestTxEvent(relayedMessagesByTx, relayedMessageEvent, finalizedBridge.Event, false)
+		if relayedMessage == nil {
+			log.Error("expected RelayedMessage following ERC721BridgeFinalized event", "tx_hash", finalizedBridge.Event.TransactionHash.String())
+			return nil, fmt.Errorf("expected RelayedMessage following ERC721BridgeFinalized event. tx_hash = %s", finalizedBridge.Event.TransactionHash.String())
+		}
+		finalized[i] = relayedMessage
+	}
+	return finalized, nil
+}
+
+// txEvents groups the supplied events by transaction
+func txEvents[T any](events []T, contractEvent func(*T) *event.ContractEvent) map[common.Hash][]*T {
+	byTx := make(map[common.Hash][]*T)
+	for i := range events {
+		txHash := contractEvent(&events[i]).TransactionHash
+		byTx[txHash] = append(byTx[txHash], &events[i])
+	}
+	return byTx
+}
+
+// nearestTxEvent returns the event of the target's transaction emitted closest before, or after, the target
+func nearestTxEvent[T any](byTx map[common.Hash][]*T, contractEvent func(*T) *event.ContractEvent, target *event.ContractEvent, before bool) *T {
+	var nearest *T
+	var nearestIndex uint64
+	for _, candidate := range byTx[target.TransactionHash] {
+		index := contractEvent(candidate).LogIndex
+		if before && index < target.LogIndex && (nearest == nil || index > nearestIndex) {
+			nearest, nearestIndex = candidate, index
+		} else if !before && index > target.LogIndex && (nearest == nil || index < nearestIndex) {
+			nearest, nearestIndex = candidate, index
+		}
+	}
+	return nearest
+}
@@ -0,0 +1,59 @@
+package bridge
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/google/uuid"
+
+	"github.com/mantlenetworkio/lithosphere/config"
+	"github.com/mantlenetworkio/lithosphere/database"
+	"github.com/mantlenetworkio/lithosphere/database/business"
+	"github.com/mantlenetworkio/lithosphere/database/event"
+	"github.com/mantlenetworkio/lithosphere/event/processors/contracts"
+)
+
+// l2ProcessFeeVaultWithdrawals stores the withdrawals of the fee vaults. A vault emits Withdrawal before bridging
+// its balance to L1, the withdrawal hash is found from the MessagePassed event following it in the same transaction.
+func l2ProcessFeeVaultWithdrawals(log log.Logger, db *database.DB, l2Contracts config.L2Contracts, messagesPassed []contracts.L2ToL1MessagePasserMessagePassed, fromHeight, toHeight *big.Int) error {
+	messagePassedEvent := func(e *contracts.L2ToL1MessagePasserMessagePassed) *event.ContractEvent { return e.Event }
+	messagesPassedByTx := txEvents(messagesPassed, messagePassedEvent)
+
+	var withdrawals []business.FeeVaultWithdrawal
+	for _, vault := range []common.Address{l2Contracts.SequencerFeeVault, l2Contracts.BaseFeeVault, l2Contracts.L1FeeVault} {
+		vaultWithdrawals, err := contracts.FeeVaultWithdrawalEvents(vault, db, fromHeight, toHeight)
+		if err != nil {
+			return err
+		}
+		for i := range vaultWithdrawals {
+			vaultWithdrawal := vaultWithdrawals[i]
+			blockNumber, err := db.L2ToL1.GetBlockNumberFromHash(vaultWithdrawal.Event.BlockHash)
+			if err != nil {
+				return err
+			}
+			withdrawal := business.FeeVaultWithdrawal{
+				GUID:              uuid.New(),
+				VaultAddress:      vault,
+				L2BlockNumber:     blockNumber,
+				L2TransactionHash: vaultWithdrawal.Event.TransactionHash,
+				LogIndex:          vaultWithdrawal.Event.LogIndex,
+				Value:             vaultWithdrawal.Value,
+				FromAddress:       vaultWithdrawal.FromAddress,
+				ToAddress:         vaultWithdrawal.ToAddress,
+				Timestamp:         int64(vaultWithdrawal.Event.Timestamp),
+			}
+			if messagePassed := nearestTxEvent(messagesPassedByTx, messagePassedEvent, vaultWithdrawal.Event, false); messagePassed != nil {
+				withdrawal.WithdrawalHash = messagePassed.WithdrawalHash
+			} else {
+				log.Warn("no MessagePassed following fee vault Withdrawal event", "vault", vault, "tx_hash", vaultWithdrawal.Event.TransactionHash.String())
+			}
+			withdrawals = append(withdrawals, withdrawal)
+		}
+	}
+	if len(withdrawals) == 0 {
+		return nil
+	}
+	log.Info("detected fee vault withdrawals", "size", len(withdrawals))
+	return db.FeeVault.StoreFeeVaultWithdrawals(withdrawals)
+}
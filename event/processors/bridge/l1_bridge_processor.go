@@ -43,23 +43,24 @@ func L1ProcessInitiatedBridgeEvents(log log.Logger, db *database.DB, metrics L1M
 			return err
 		}
 		l1ToL2s[i] = business.L1ToL2{
-			GUID:                  uuid.New(),
-			L1BlockNumber:         blockNumber,
-			L2BlockNumber:         bigint.Zero,
-			QueueIndex:            nil,
-			L1TransactionHash:     depositTx.Event.TransactionHash,
-			L2TransactionHash:     common.Hash{},
-			TransactionSourceHash: depositTx.DepositTx.SourceHash,
-			MessageHash:           common.Hash{},
-			L1TxOrigin:            depositTx.DepositTx.From,
-			Status:                common2.L1ToL2Pending,
-			L1TokenAddress:        common.Address{},
-			L2TokenAddress:        common.Address{},
-			ETHAmount:             depositTx.DepositTx.EthValue,
-			ERC20Amount:           depositTx.DepositTx.Value,
-			GasLimit:              depositTx.GasLimit,
-			Version:               1,
-			Timestamp:             int64(depositTx.Event.Timestamp),
+			GUID:                     uuid.New(),
+			L1BlockNumber:            blockNumber,
+			L2BlockNumber:            bigint.Zero,
+			QueueIndex:               nil,
+			L1TransactionHash:        depositTx.Event.TransactionHash,
+			L2TransactionHash:        common.Hash{},
+			TransactionSourceHash:    depositTx.DepositTx.SourceHash,
+			MessageHash:              common.Hash{},
+			L1TxOrigin:               depositTx.DepositTx.From,
+			Status:                   common2.L1ToL2Pending,
+			L1TokenAddress:           common.Address{},
+			L2TokenAddress:           common.Address{},
+			ETHAmount:                depositTx.DepositTx.EthValue,
+			ERC20Amount:              depositTx.DepositTx.Value,
+			GasLimit:                 depositTx.GasLimit,
+			Version:                  1,
+			Timestamp:                int64(depositTx.Event.Timestamp),
+			L2DepositTransactionHash: &depositTx.L2TxHash,
 		}
 	}
 	if len(l1ToL2s) > 0 {
@@ -177,7 +178,9 @@ func L1ProcessInitiatedBridgeEvents(log log.Logger, db *database.DB, metrics L1M
 			metrics.RecordL1InitiatedBridgeTransfers(tokenAddr, size)
 		}
 	}
-	return nil
+
+	// (4) L1ERC721Bridge
+	return l1ProcessInitiatedERC721Bridges(log, db, l1Contracts.L1ERC721BridgeProxy, crossDomainSentMessages, fromHeight, toHeight)
 }
 
 // L1ProcessProvenBridgeEvents Optimism portal proven withdrawals
@@ -218,7 +221,9 @@ func L1ProcessProvenBridgeEvents(log log.Logger, db *database.DB, metrics L1Metr
 		}
 		metrics.RecordL1ProvenWithdrawals(len(withdrawProvenList))
 	}
-	return nil
+
+	//  L1ERC721Bridge withdrawals enter their challenge period as well
+	return l1ProcessProvenERC721Bridges(db, withdrawProvenList)
 }
 
 // L1ProcessFinalizedBridgeEvents OptimismPortal (finalized withdrawals) and L1CrossDomainMessenger
@@ -229,8 +234,10 @@ func L1ProcessFinalizedBridgeEvents(log log.Logger, db *database.DB, metrics L1M
 	}
 	log.Info("detected finalized withdrawals", "size", len(finalizedWithdrawals))
 	withdrawFinalizedList := make([]event.WithdrawFinalized, len(finalizedWithdrawals))
+	portalFinalizations := make(map[common.Hash][]*contracts.OptimismPortalWithdrawalFinalizedEvent)
 	for i := range finalizedWithdrawals {
 		finalized := finalizedWithdrawals[i]
+		portalFinalizations[finalized.Event.TransactionHash] = append(portalFinalizations[finalized.Event.TransactionHash], &finalizedWithdrawals[i])
 		blockNumber := bigint.One
 		finalizedBlockNumber, err := db.L1ToL2.GetBlockNumberFromHash(finalized.Event.BlockHash)
 		if err != nil {
@@ -272,6 +279,9 @@ func L1ProcessFinalizedBridgeEvents(log log.Logger, db *database.DB, metrics L1M
 		relayedMessages[logKey{BlockHash: relayed.Event.BlockHash, LogIndex: relayed.Event.LogIndex}] = &relayed
 	}
 	metrics.RecordL1CrossDomainRelayedMessages(len(crossDomainRelayedMessages))
+	if err := processRelayAttempts(log, db, "l1", l1Contracts.L1CrossDomainMessengerProxy, crossDomainRelayedMessages, fromHeight, toHeight); err != nil {
+		return err
+	}
 
 	//  L1StandardBridge
 	finalizedBridges, err := contracts.StandardBridgeFinalizedEvents("l1", l1Contracts.L1StandardBridgeProxy, db, fromHeight, toHeight)
@@ -279,7 +289,7 @@ func L1ProcessFinalizedBridgeEvents(log log.Logger, db *database.DB, metrics L1M
 		return err
 	}
 	log.Info("detected finalized bridge withdrawals", "size", len(finalizedBridges))
-	withdrawFinalizedBridgeList := make([]event.WithdrawFinalized, len(crossDomainRelayedMessages))
+	var withdrawFinalizedBridgeList []event.WithdrawFinalized
 	finalizedTokens := make(map[common.Address]int)
 	for i := range finalizedBridges {
 		finalizedBridge := finalizedBridges[i]
@@ -288,20 +298,37 @@ func L1ProcessFinalizedBridgeEvents(log log.Logger, db *database.DB, metrics L1M
 			log.Error("expected RelayedMessage following BridgeFinalized event", "tx_hash", finalizedBridge.Event.TransactionHash.String())
 			return fmt.Errorf("expected RelayedMessage following BridgeFinalized event. tx_hash = %s", finalizedBridge.Event.TransactionHash.String())
 		}
-		withdrawFinalizedBridgeList[i].L1TokenAddress = finalizedBridge.LocalTokenAddress
-		withdrawFinalizedBridgeList[i].L2TokenAddress = finalizedBridge.RemoteTokenAddress
-		withdrawFinalizedBridgeList[i].ETHAmount = finalizedBridge.ETHAmount
-		withdrawFinalizedBridgeList[i].ERC20Amount = finalizedBridge.ERC20Amount
-		withdrawFinalizedBridgeList[i].MessageHash = relayedMessage.MessageHash
 		finalizedTokens[finalizedBridge.LocalTokenAddress]++
-	}
-	if len(finalizedBridges) > 0 {
-		for tokenAddr, size := range finalizedTokens {
-			if err := db.WithdrawFinalized.UpdateWithdrawFinalizedInfo(withdrawFinalizedBridgeList); err != nil {
-				return err
+
+		// the portal emits WithdrawalFinalized once the message has been relayed, after its RelayedMessage. Messages
+		// relayed again by the messenger after a failed relay are not finalized by the portal.
+		var portalFinalization *contracts.OptimismPortalWithdrawalFinalizedEvent
+		for _, finalized := range portalFinalizations[relayedMessage.Event.TransactionHash] {
+			if finalized.Event.LogIndex > relayedMessage.Event.LogIndex && (portalFinalization == nil || finalized.Event.LogIndex < portalFinalization.Event.LogIndex) {
+				portalFinalization = finalized
 			}
-			metrics.RecordL1FinalizedBridgeTransfers(tokenAddr, size)
 		}
+		if portalFinalization == nil {
+			continue
+		}
+		withdrawFinalizedBridgeList = append(withdrawFinalizedBridgeList, event.WithdrawFinalized{
+			WithdrawHash:   portalFinalization.WithdrawalHash,
+			L1TokenAddress: finalizedBridge.LocalTokenAddress,
+			L2TokenAddress: finalizedBridge.RemoteTokenAddress,
+			ETHAmount:      finalizedBridge.ETHAmount,
+			ERC20Amount:    finalizedBridge.ERC20Amount,
+			MessageHash:    relayedMessage.MessageHash,
+		})
 	}
-	return nil
+	if len(withdrawFinalizedBridgeList) > 0 {
+		if err := db.WithdrawFinalized.UpdateWithdrawFinalizedInfo(withdrawFinalizedBridgeList); err != nil {
+			return err
+		}
+	}
+	for tokenAddr, size := range finalizedTokens {
+		metrics.RecordL1FinalizedBridgeTransfers(tokenAddr, size)
+	}
+
+	//  L1ERC721Bridge
+	return l1ProcessFinalizedERC721Bridges(log, db, l1Contracts.L1ERC721BridgeProxy, crossDomainRelayedMessages, fromHeight, toHeight)
 }
@@ -55,7 +55,6 @@ func L2ProcessInitiatedBridgeEvents(log log.Logger, db *database.DB, metrics L2M
 			ETHAmount:               messagePassed.ETHAmount,
 			ERC20Amount:             messagePassed.ERC20Amount,
 			GasLimit:                messagePassed.GasLimit,
-			TimeLeft:                new(big.Int).SetUint64(0),
 			L1TokenAddress:          common.Address{},
 			L2TokenAddress:          common.Address{},
 			Version:                 1,
@@ -190,7 +189,19 @@ func L2ProcessInitiatedBridgeEvents(log log.Logger, db *database.DB, metrics L2M
 			metrics.RecordL2InitiatedBridgeTransfers(tokenAddr, size)
 		}
 	}
-	return nil
+
+	// (4) L2ERC721Bridge
+	if err := l2ProcessInitiatedERC721Bridges(log, db, l2Contracts.L2ERC721Bridge, l2ToL1MPMessagesPassed, crossDomainSentMessages, fromHeight, toHeight); err != nil {
+		return err
+	}
+
+	// (5) Fee vaults
+	if err := l2ProcessFeeVaultWithdrawals(log, db, l2Contracts, l2ToL1MPMessagesPassed, fromHeight, toHeight); err != nil {
+		return err
+	}
+
+	// (6) OptimismMintableERC20Factory
+	return l2ProcessCreatedTokenPairs(log, db, l2Contracts.OptimismMintableERC20Factory, fromHeight, toHeight)
 }
 
 func L2ProcessFinalizedBridgeEvents(log log.Logger, db *database.DB, metrics L2Metricer, l2Contracts config.L2Contracts, fromHeight, toHeight *big.Int) error {
@@ -217,6 +228,9 @@ func L2ProcessFinalizedBridgeEvents(log log.Logger, db *database.DB, metrics L2M
 	if len(relayedMessages) > 0 {
 		metrics.RecordL2CrossDomainRelayedMessages(len(relayedMessages))
 	}
+	if err := processRelayAttempts(log, db, "l2", l2Contracts.L2CrossDomainMessenger, crossDomainRelayedMessages, fromHeight, toHeight); err != nil {
+		return err
+	}
 
 	// (2) L2StandardBridge
 	finalizedBridges, err := contracts.StandardBridgeFinalizedEvents("l2", l2Contracts.L2StandardBridge, db, fromHeight, toHeight)
@@ -268,5 +282,7 @@ func L2ProcessFinalizedBridgeEvents(log log.Logger, db *database.DB, metrics L2M
 			metrics.RecordL2FinalizedBridgeTransfers(tokenAddr, size)
 		}
 	}
-	return nil
+
+	// (3) L2ERC721Bridge
+	return l2ProcessFinalizedERC721Bridges(log, db, l2Contracts.L2ERC721Bridge, crossDomainRelayedMessages, fromHeight, toHeight)
 }
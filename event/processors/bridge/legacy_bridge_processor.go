@@ -15,7 +15,6 @@ import (
 	"github.com/mantlenetworkio/lithosphere/database"
 	"github.com/mantlenetworkio/lithosphere/database/business"
 	contracts2 "github.com/mantlenetworkio/lithosphere/event/processors/contracts"
-	"github.com/mantlenetworkio/lithosphere/synchronizer/node"
 )
 
 func LegacyL1ProcessInitiatedBridgeEvents(log log.Logger, db *database.DB, metrics L1Metricer, l1Contracts config.L1Contracts, fromHeight, toHeight *big.Int) error {
@@ -171,7 +170,6 @@ func LegacyL2ProcessInitiatedBridgeEvents(log log.Logger, db *database.DB, metri
 			ETHAmount:               sentMessage.ETHAmount,
 			ERC20Amount:             sentMessage.ERC20Amount,
 			GasLimit:                sentMessage.GasLimit,
-			TimeLeft:                new(big.Int).SetUint64(0),
 			L1TokenAddress:          common.Address{},
 			L2TokenAddress:          common.Address{},
 			Timestamp:               int64(sentMessage.Event.Timestamp),
@@ -229,7 +227,7 @@ func LegacyL2ProcessInitiatedBridgeEvents(log log.Logger, db *database.DB, metri
 	return nil
 }
 
-func LegacyL1ProcessFinalizedBridgeEvents(log log.Logger, db *database.DB, metrics L1Metricer, l1Client node.EthClient, l1Contracts config.L1Contracts, fromHeight, toHeight *big.Int) error {
+func LegacyL1ProcessFinalizedBridgeEvents(log log.Logger, db *database.DB, metrics L1Metricer, l1Contracts config.L1Contracts, fromHeight, toHeight *big.Int) error {
 	crossDomainRelayedMessages, err := contracts2.CrossDomainMessengerRelayedMessageEvents("l1", l1Contracts.L1CrossDomainMessengerProxy, db, fromHeight, toHeight)
 	if err != nil {
 		return err
@@ -246,7 +244,10 @@ func LegacyL1ProcessFinalizedBridgeEvents(log log.Logger, db *database.DB, metri
 		l2ToL1Finalized[i].L1FinalizeTxHash = relayedMessage.Event.TransactionHash
 	}
 	if len(crossDomainRelayedMessages) > 0 {
-		if err = db.L2ToL1.MarkL2ToL1TransactionWithdrawalFinalized(l2ToL1Finalized); err != nil {
+		err = db.L2ToL1.MarkL2ToL1TransactionWithdrawalFinalized(l2ToL1Finalized)
+		if err := business.HandleRejectedTransitions(err, func(transition business.RejectedTransition) {
+			metrics.RecordL1RejectedTransition(transition.Bridge, transition.From, transition.To)
+		}); err != nil {
 			return err
 		}
 		metrics.RecordL1ProvenWithdrawals(len(crossDomainRelayedMessages))
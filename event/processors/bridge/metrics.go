@@ -2,6 +2,7 @@ package bridge
 
 import (
 	"math/big"
+	"strconv"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/prometheus/client_golang/prometheus"
@@ -18,6 +19,7 @@ type L1Metricer interface {
 	RecordL1LatestHeight(height *big.Int)
 	RecordL1LatestRollupSateRootHeight(height *big.Int)
 	RecordL1LatestRollupMantleDaHeight(height *big.Int)
+	RecordL1LatestSystemConfigHeight(height *big.Int)
 
 	RecordL1LatestProvenHeight(height *big.Int)
 	RecordL1LatestFinalizedHeight(height *big.Int)
@@ -29,12 +31,16 @@ type L1Metricer interface {
 	RecordL1CrossDomainSentMessages(size int)
 	RecordL1CrossDomainRelayedMessages(size int)
 
+	RecordL1DeletedOutputs(size int)
+
 	RecordL1SkippedOVM1ProvenWithdrawals(size int)
 	RecordL1SkippedOVM1FinalizedWithdrawals(size int)
 	RecordL1SkippedOVM1CrossDomainRelayedMessages(size int)
 
 	RecordL1InitiatedBridgeTransfers(token common.Address, size int)
 	RecordL1FinalizedBridgeTransfers(token common.Address, size int)
+
+	RecordL1RejectedTransition(bridge string, from, to int64)
 }
 
 type L2Metricer interface {
@@ -74,11 +80,15 @@ type bridgeMetrics struct {
 	sentMessages    *prometheus.CounterVec
 	relayedMessages *prometheus.CounterVec
 
+	deletedOutputs prometheus.Counter
+
 	skippedOVM1Withdrawals     *prometheus.CounterVec
 	skippedOVM1RelayedMessages prometheus.Counter
 
 	initiatedBridgeTransfers *prometheus.CounterVec
 	finalizedBridgeTransfers *prometheus.CounterVec
+
+	rejectedTransitions *prometheus.CounterVec
 }
 
 func NewMetrics(registry *prometheus.Registry) Metricer {
@@ -157,6 +167,11 @@ func NewMetrics(registry *prometheus.Registry) Metricer {
 		}, []string{
 			"chain",
 		}),
+		deletedOutputs: factory.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "deleted_outputs",
+			Help:      "number of l2 outputs deleted by the challenger on l1",
+		}),
 		skippedOVM1Withdrawals: factory.NewCounterVec(prometheus.CounterOpts{
 			Namespace: MetricsNamespace,
 			Name:      "skipped_ovm1_withdrawals",
@@ -185,6 +200,16 @@ func NewMetrics(registry *prometheus.Registry) Metricer {
 			"chain",
 			"token_address",
 		}),
+		rejectedTransitions: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Name:      "rejected_bridge_status_transitions_total",
+			Help:      "number of deposit and withdrawal status transitions not allowed by their lifecycle",
+		}, []string{
+			"chain",
+			"bridge",
+			"from",
+			"to",
+		}),
 	}
 }
 
@@ -213,6 +238,10 @@ func (m *bridgeMetrics) RecordL1LatestRollupMantleDaHeight(height *big.Int) {
 	m.latestHeight.WithLabelValues("l1", "mantle_da").Set(float64(height.Uint64()))
 }
 
+func (m *bridgeMetrics) RecordL1LatestSystemConfigHeight(height *big.Int) {
+	m.latestHeight.WithLabelValues("l1", "system_config").Set(float64(height.Uint64()))
+}
+
 func (m *bridgeMetrics) RecordL1LatestFinalizedHeight(height *big.Int) {
 	m.latestHeight.WithLabelValues("l1", "finalized").Set(float64(height.Uint64()))
 }
@@ -242,6 +271,10 @@ func (m *bridgeMetrics) RecordL1CrossDomainRelayedMessages(size int) {
 	m.relayedMessages.WithLabelValues("l1").Add(float64(size))
 }
 
+func (m *bridgeMetrics) RecordL1DeletedOutputs(size int) {
+	m.deletedOutputs.Add(float64(size))
+}
+
 func (m *bridgeMetrics) RecordL1SkippedOVM1ProvenWithdrawals(size int) {
 	m.skippedOVM1Withdrawals.WithLabelValues("stage", "proven").Add(float64(size))
 }
@@ -254,6 +287,10 @@ func (m *bridgeMetrics) RecordL1SkippedOVM1CrossDomainRelayedMessages(size int)
 	m.skippedOVM1RelayedMessages.Add(float64(size))
 }
 
+func (m *bridgeMetrics) RecordL1RejectedTransition(bridge string, from, to int64) {
+	m.rejectedTransitions.WithLabelValues("l1", bridge, strconv.FormatInt(from, 10), strconv.FormatInt(to, 10)).Inc()
+}
+
 func (m *bridgeMetrics) RecordL1InitiatedBridgeTransfers(tokenAddr common.Address, size int) {
 	m.initiatedBridgeTransfers.WithLabelValues("l1", tokenAddr.String()).Add(float64(size))
 }
@@ -0,0 +1,62 @@
+package bridge
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/google/uuid"
+
+	"github.com/mantlenetworkio/lithosphere/database"
+	common2 "github.com/mantlenetworkio/lithosphere/database/common"
+	"github.com/mantlenetworkio/lithosphere/database/event"
+	"github.com/mantlenetworkio/lithosphere/event/processors/contracts"
+)
+
+// processRelayAttempts stores every relay of a cross domain message by the messenger of the chain, successful or not.
+// The status of the messages is derived from their attempts by the business processor.
+func processRelayAttempts(log log.Logger, db *database.DB, chain string, messenger common.Address, relayedMessages []contracts.CrossDomainMessengerRelayedMessageEvent, fromHeight, toHeight *big.Int) error {
+	failedRelayedMessages, err := contracts.CrossDomainMessengerFailedRelayedMessageEvents(chain, messenger, db, fromHeight, toHeight)
+	if err != nil {
+		return err
+	}
+	if len(failedRelayedMessages) > 0 {
+		log.Info("detected failed relayed messages", "size", len(failedRelayedMessages))
+	}
+
+	attempts := make([]event.RelayAttempt, 0, len(relayedMessages)+len(failedRelayedMessages))
+	for _, relays := range []struct {
+		messages []contracts.CrossDomainMessengerRelayedMessageEvent
+		success  bool
+	}{{relayedMessages, true}, {failedRelayedMessages, false}} {
+		for i := range relays.messages {
+			relayed := relays.messages[i]
+			var blockNumber *big.Int
+			if chain == common2.SyncCursorLayerL1 {
+				blockNumber, err = db.L1ToL2.GetBlockNumberFromHash(relayed.Event.BlockHash)
+			} else {
+				blockNumber, err = db.L2ToL1.GetBlockNumberFromHash(relayed.Event.BlockHash)
+			}
+			if err != nil {
+				return err
+			} else if blockNumber == nil {
+				return fmt.Errorf("missing header of relayed message. tx_hash = %s", relayed.Event.TransactionHash.String())
+			}
+			attempts = append(attempts, event.RelayAttempt{
+				GUID:            uuid.New(),
+				Chain:           chain,
+				MessageHash:     relayed.MessageHash,
+				BlockNumber:     blockNumber,
+				TransactionHash: relayed.Event.TransactionHash,
+				LogIndex:        relayed.Event.LogIndex,
+				Success:         relays.success,
+				Timestamp:       relayed.Event.Timestamp,
+			})
+		}
+	}
+	if len(attempts) == 0 {
+		return nil
+	}
+	return db.RelayAttempt.StoreRelayAttempts(attempts)
+}
@@ -0,0 +1,43 @@
+package bridge
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/mantlenetworkio/lithosphere/database"
+	"github.com/mantlenetworkio/lithosphere/database/business"
+	"github.com/mantlenetworkio/lithosphere/event/processors/contracts"
+)
+
+// l2ProcessCreatedTokenPairs registers the token pairs deployed by the OptimismMintableERC20Factory. Their
+// metadata is left to the business processor, which reads it from both chains.
+func l2ProcessCreatedTokenPairs(log log.Logger, db *database.DB, factoryAddress common.Address, fromHeight, toHeight *big.Int) error {
+	createdTokens, err := contracts.OptimismMintableERC20CreatedEvents(factoryAddress, db, fromHeight, toHeight)
+	if err != nil {
+		return err
+	}
+	if len(createdTokens) == 0 {
+		return nil
+	}
+	log.Info("detected created token pairs", "size", len(createdTokens))
+
+	pairs := make([]business.TokenPair, len(createdTokens))
+	for i := range createdTokens {
+		createdToken := createdTokens[i]
+		blockNumber, err := db.L2ToL1.GetBlockNumberFromHash(createdToken.Event.BlockHash)
+		if err != nil {
+			return err
+		}
+		pairs[i] = business.TokenPair{
+			L2TokenAddress:    createdToken.LocalTokenAddress,
+			L1TokenAddress:    createdToken.RemoteTokenAddress,
+			Deployer:          createdToken.Deployer,
+			L2BlockNumber:     blockNumber,
+			L2TransactionHash: createdToken.Event.TransactionHash,
+			Timestamp:         int64(createdToken.Event.Timestamp),
+		}
+	}
+	return db.TokenPair.StoreTokenPairs(pairs)
+}
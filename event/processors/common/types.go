@@ -1,9 +1,13 @@
 package common
 
 import (
+	"bytes"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
 )
 
 type DepositTx struct {
@@ -17,3 +21,14 @@ type DepositTx struct {
 	EthValue            *big.Int `rlp:"nil"`
 	Data                []byte
 }
+
+// Hash is the hash of the deposit transaction executed on L2, the typed transaction hash of its RLP
+// encoding as done by the Mantle L2 node
+func (dep *DepositTx) Hash() (common.Hash, error) {
+	var buf bytes.Buffer
+	buf.WriteByte(types.DepositTxType)
+	if err := rlp.Encode(&buf, dep); err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash(buf.Bytes()), nil
+}
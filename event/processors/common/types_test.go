@@ -0,0 +1,57 @@
+package common
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// TestDepositTxHash checks the envelope the hash is computed over against one assembled by hand from the
+// field layout of the Mantle deposit transaction. It isn't pinned to a deposit executed on Mantle yet: the
+// source hash, fields and L2 transaction hash of a real deposit are still to be added as a vector here.
+func TestDepositTxHash(t *testing.T) {
+	to := common.HexToAddress("0x3333333333333333333333333333333333333333")
+	dep := DepositTx{
+		SourceHash: common.HexToHash("0x1111111111111111111111111111111111111111111111111111111111111111"),
+		From:       common.HexToAddress("0x2222222222222222222222222222222222222222"),
+		To:         &to,
+		Value:      big.NewInt(1e18),
+		Gas:        100000,
+	}
+
+	// the typed envelope of the Mantle deposit transaction: the nil mint and eth value are encoded as empty
+	// strings in place, the eth value sits between the system flag and the data
+	encoded := hexutil.MustDecode("0x7ef85c" +
+		"a01111111111111111111111111111111111111111111111111111111111111111" +
+		"942222222222222222222222222222222222222222" +
+		"943333333333333333333333333333333333333333" +
+		"80" +
+		"880de0b6b3a7640000" +
+		"830186a0" +
+		"80" +
+		"80" +
+		"80")
+	hash, err := dep.Hash()
+	require.NoError(t, err)
+	require.Equal(t, crypto.Keccak256Hash(encoded), hash)
+
+	dep.EthValue = big.NewInt(1)
+	encoded = hexutil.MustDecode("0x7ef85c" +
+		"a01111111111111111111111111111111111111111111111111111111111111111" +
+		"942222222222222222222222222222222222222222" +
+		"943333333333333333333333333333333333333333" +
+		"80" +
+		"880de0b6b3a7640000" +
+		"830186a0" +
+		"80" +
+		"01" +
+		"80")
+	hash, err = dep.Hash()
+	require.NoError(t, err)
+	require.Equal(t, crypto.Keccak256Hash(encoded), hash)
+}
@@ -225,3 +225,35 @@ func CrossDomainMessageCalldata(abi *abi.ABI, sentMsg *bindings.CrossDomainMesse
 
 	return nil, fmt.Errorf("unsupported cross domain messenger version: %d", version)
 }
+
+// CrossDomainMessengerFailedRelayedMessageEvents returns the relays of the messenger whose call reverted. The
+// message can be replayed by anyone, the replays emit RelayedMessage or FailedRelayedMessage again.
+func CrossDomainMessengerFailedRelayedMessageEvents(chainSelector string, contractAddress common.Address, db *database.DB, fromHeight, toHeight *big.Int) ([]CrossDomainMessengerRelayedMessageEvent, error) {
+	crossDomainMessengerAbi, err := bindings.CrossDomainMessengerMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+
+	failedRelayedMessageEventAbi := crossDomainMessengerAbi.Events["FailedRelayedMessage"]
+	contractEventFilter := event.ContractEvent{ContractAddress: contractAddress, EventSignature: failedRelayedMessageEventAbi.ID}
+	failedRelayedMessageEvents, err := db.ContractEvents.ContractEventsWithFilter(contractEventFilter, chainSelector, fromHeight, toHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	crossDomainFailedRelayedMessages := make([]CrossDomainMessengerRelayedMessageEvent, len(failedRelayedMessageEvents))
+	for i := range failedRelayedMessageEvents {
+		failedRelayedMessage := bindings.CrossDomainMessengerFailedRelayedMessage{Raw: *failedRelayedMessageEvents[i].RLPLog}
+		err = UnpackLog(&failedRelayedMessage, failedRelayedMessageEvents[i].RLPLog, failedRelayedMessageEventAbi.Name, crossDomainMessengerAbi)
+		if err != nil {
+			return nil, err
+		}
+
+		crossDomainFailedRelayedMessages[i] = CrossDomainMessengerRelayedMessageEvent{
+			Event:       &failedRelayedMessageEvents[i],
+			MessageHash: failedRelayedMessage.MsgHash,
+		}
+	}
+
+	return crossDomainFailedRelayedMessages, nil
+}
@@ -0,0 +1,70 @@
+package contracts
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/mantlenetworkio/lithosphere/database"
+	"github.com/mantlenetworkio/lithosphere/database/event"
+	"github.com/mantlenetworkio/lithosphere/database/utils"
+	"github.com/mantlenetworkio/lithosphere/event/op-bindings/bindings"
+)
+
+// ERC721BridgeEvent is an ERC721BridgeInitiated or ERC721BridgeFinalized event. Both share the
+// same fields, the local token being the one of the chain the event was emitted on.
+type ERC721BridgeEvent struct {
+	Event              *event.ContractEvent
+	LocalTokenAddress  common.Address
+	RemoteTokenAddress common.Address
+	FromAddress        common.Address
+	ToAddress          common.Address
+	TokenId            *big.Int
+	Data               utils.Bytes
+	Timestamp          uint64
+}
+
+// ERC721BridgeInitiatedEvents extracts the initiated bridge events of the L1ERC721Bridge or L2ERC721Bridge
+func ERC721BridgeInitiatedEvents(chainSelector string, contractAddress common.Address, db *database.DB, fromHeight, toHeight *big.Int) ([]ERC721BridgeEvent, error) {
+	return erc721BridgeEvents("ERC721BridgeInitiated", chainSelector, contractAddress, db, fromHeight, toHeight)
+}
+
+// ERC721BridgeFinalizedEvents extracts the finalized bridge events of the L1ERC721Bridge or L2ERC721Bridge
+func ERC721BridgeFinalizedEvents(chainSelector string, contractAddress common.Address, db *database.DB, fromHeight, toHeight *big.Int) ([]ERC721BridgeEvent, error) {
+	return erc721BridgeEvents("ERC721BridgeFinalized", chainSelector, contractAddress, db, fromHeight, toHeight)
+}
+
+func erc721BridgeEvents(eventName string, chainSelector string, contractAddress common.Address, db *database.DB, fromHeight, toHeight *big.Int) ([]ERC721BridgeEvent, error) {
+	// the L1 and L2 bridges emit the same events
+	erc721BridgeAbi, err := bindings.L1ERC721BridgeMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+
+	eventAbi := erc721BridgeAbi.Events[eventName]
+	contractEventFilter := event.ContractEvent{ContractAddress: contractAddress, EventSignature: eventAbi.ID}
+	bridgeEvents, err := db.ContractEvents.ContractEventsWithFilter(contractEventFilter, chainSelector, fromHeight, toHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	erc721BridgeEvents := make([]ERC721BridgeEvent, len(bridgeEvents))
+	for i := range bridgeEvents {
+		// ERC721BridgeInitiated and ERC721BridgeFinalized have the same layout
+		var bridgeEvent bindings.L1ERC721BridgeERC721BridgeInitiated
+		if err := UnpackLog(&bridgeEvent, bridgeEvents[i].RLPLog, eventName, erc721BridgeAbi); err != nil {
+			return nil, err
+		}
+		erc721BridgeEvents[i] = ERC721BridgeEvent{
+			Event:              &bridgeEvents[i],
+			LocalTokenAddress:  bridgeEvent.LocalToken,
+			RemoteTokenAddress: bridgeEvent.RemoteToken,
+			FromAddress:        bridgeEvent.From,
+			ToAddress:          bridgeEvent.To,
+			TokenId:            bridgeEvent.TokenId,
+			Data:               bridgeEvent.ExtraData,
+			Timestamp:          bridgeEvents[i].Timestamp,
+		}
+	}
+	return erc721BridgeEvents, nil
+}
@@ -0,0 +1,49 @@
+package contracts
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/mantlenetworkio/lithosphere/database"
+	"github.com/mantlenetworkio/lithosphere/database/event"
+	"github.com/mantlenetworkio/lithosphere/event/op-bindings/bindings"
+)
+
+type FeeVaultWithdrawalEvent struct {
+	Event       *event.ContractEvent
+	Value       *big.Int
+	FromAddress common.Address
+	ToAddress   common.Address
+}
+
+// FeeVaultWithdrawalEvents extracts the Withdrawal events of a fee vault predeploy
+func FeeVaultWithdrawalEvents(contractAddress common.Address, db *database.DB, fromHeight, toHeight *big.Int) ([]FeeVaultWithdrawalEvent, error) {
+	// the SequencerFeeVault, BaseFeeVault and L1FeeVault share the FeeVault events
+	feeVaultAbi, err := bindings.BaseFeeVaultMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+
+	withdrawalEventAbi := feeVaultAbi.Events["Withdrawal"]
+	contractEventFilter := event.ContractEvent{ContractAddress: contractAddress, EventSignature: withdrawalEventAbi.ID}
+	withdrawalEvents, err := db.ContractEvents.L2ContractEventsWithFilter(contractEventFilter, fromHeight, toHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	withdrawals := make([]FeeVaultWithdrawalEvent, len(withdrawalEvents))
+	for i := range withdrawalEvents {
+		var withdrawal bindings.BaseFeeVaultWithdrawal
+		if err := UnpackLog(&withdrawal, withdrawalEvents[i].RLPLog, withdrawalEventAbi.Name, feeVaultAbi); err != nil {
+			return nil, err
+		}
+		withdrawals[i] = FeeVaultWithdrawalEvent{
+			Event:       &withdrawalEvents[i].ContractEvent,
+			Value:       withdrawal.Value,
+			FromAddress: withdrawal.From,
+			ToAddress:   withdrawal.To,
+		}
+	}
+	return withdrawals, nil
+}
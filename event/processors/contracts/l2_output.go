@@ -45,8 +45,44 @@ func L2OutputProposedEvent(contractAddress common.Address, db *database.DB, from
 			BatchSize:       outputProposed.L2OutputIndex,
 			L1BlockNumber:   l1BlockNumber,
 			L2BlockNumber:   outputProposed.L2BlockNumber,
+			Canonical:       true,
 			Timestamp:       outputProposed.L1Timestamp.Uint64(),
 		}
 	}
 	return stateRoots, nil
 }
+
+type L2OutputsDeleted struct {
+	Event               *event.ContractEvent
+	PrevNextOutputIndex *big.Int
+	NewNextOutputIndex  *big.Int
+}
+
+// L2OutputsDeletedEvents returns the deletions of the outputs by the challenger. Every output from
+// NewNextOutputIndex up to, but excluding, PrevNextOutputIndex has been deleted.
+func L2OutputsDeletedEvents(contractAddress common.Address, db *database.DB, fromHeight, toHeight *big.Int) ([]L2OutputsDeleted, error) {
+	l2OutputAbi, err := bindings.L2OutputOracleMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	outputsDeletedEventAbi := l2OutputAbi.Events["OutputsDeleted"]
+	contractEventFilter := event.ContractEvent{ContractAddress: contractAddress, EventSignature: outputsDeletedEventAbi.ID}
+	outputsDeletedEvents, err := db.ContractEvents.L1ContractEventsWithFilter(contractEventFilter, fromHeight, toHeight)
+	if err != nil {
+		return nil, err
+	}
+	deletions := make([]L2OutputsDeleted, len(outputsDeletedEvents))
+	for i := range outputsDeletedEvents {
+		outputsDeleted := bindings.L2OutputOracleOutputsDeleted{Raw: *outputsDeletedEvents[i].RLPLog}
+		err = UnpackLog(&outputsDeleted, outputsDeletedEvents[i].RLPLog, outputsDeletedEventAbi.Name, l2OutputAbi)
+		if err != nil {
+			return nil, err
+		}
+		deletions[i] = L2OutputsDeleted{
+			Event:               &outputsDeletedEvents[i].ContractEvent,
+			PrevNextOutputIndex: outputsDeleted.PrevNextOutputIndex,
+			NewNextOutputIndex:  outputsDeleted.NewNextOutputIndex,
+		}
+	}
+	return deletions, nil
+}
@@ -35,6 +35,7 @@ func LegacySCCBatchAppendedEvent(contractAddress common.Address, db *database.DB
 			BatchSize:         stateBatchAppended.BatchSize,
 			OutputIndex:       stateBatchAppended.BatchIndex,
 			PrevTotalElements: stateBatchAppended.PrevTotalElements,
+			Canonical:         true,
 			Timestamp:         stateBatchAppendedEvents[i].Timestamp,
 		}
 	}
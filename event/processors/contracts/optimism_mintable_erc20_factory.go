@@ -0,0 +1,48 @@
+package contracts
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/mantlenetworkio/lithosphere/database"
+	"github.com/mantlenetworkio/lithosphere/database/event"
+	"github.com/mantlenetworkio/lithosphere/event/op-bindings/bindings"
+)
+
+type OptimismMintableERC20CreatedEvent struct {
+	Event              *event.ContractEvent
+	LocalTokenAddress  common.Address
+	RemoteTokenAddress common.Address
+	Deployer           common.Address
+}
+
+// OptimismMintableERC20CreatedEvents extracts the tokens deployed by the OptimismMintableERC20Factory
+func OptimismMintableERC20CreatedEvents(contractAddress common.Address, db *database.DB, fromHeight, toHeight *big.Int) ([]OptimismMintableERC20CreatedEvent, error) {
+	factoryAbi, err := bindings.OptimismMintableERC20FactoryMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+
+	createdEventAbi := factoryAbi.Events["OptimismMintableERC20Created"]
+	contractEventFilter := event.ContractEvent{ContractAddress: contractAddress, EventSignature: createdEventAbi.ID}
+	createdEvents, err := db.ContractEvents.L2ContractEventsWithFilter(contractEventFilter, fromHeight, toHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	created := make([]OptimismMintableERC20CreatedEvent, len(createdEvents))
+	for i := range createdEvents {
+		var createdToken bindings.OptimismMintableERC20FactoryOptimismMintableERC20Created
+		if err := UnpackLog(&createdToken, createdEvents[i].RLPLog, createdEventAbi.Name, factoryAbi); err != nil {
+			return nil, err
+		}
+		created[i] = OptimismMintableERC20CreatedEvent{
+			Event:              &createdEvents[i].ContractEvent,
+			LocalTokenAddress:  createdToken.LocalToken,
+			RemoteTokenAddress: createdToken.RemoteToken,
+			Deployer:           createdToken.Deployer,
+		}
+	}
+	return created, nil
+}
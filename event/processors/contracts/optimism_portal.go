@@ -18,6 +18,7 @@ import (
 type OptimismPortalTransactionDepositEvent struct {
 	Event       *event.ContractEvent
 	DepositTx   *common2.DepositTx
+	L2TxHash    common.Hash
 	FromAddress common.Address
 	ToAddress   common.Address
 	ETHAmount   *big.Int
@@ -64,6 +65,10 @@ func OptimismPortalTransactionDepositEvents(contractAddress common.Address, db *
 		if err != nil {
 			return nil, err
 		}
+		l2TxHash, err := depositTx.Hash()
+		if err != nil {
+			return nil, err
+		}
 
 		txDeposit := bindings.OptimismPortalTransactionDeposited{Raw: *transactionDepositEvents[i].RLPLog}
 		err = UnpackLog(&txDeposit, transactionDepositEvents[i].RLPLog, transactionDepositedEventAbi.Name, optimismPortalAbi)
@@ -83,6 +88,7 @@ func OptimismPortalTransactionDepositEvents(contractAddress common.Address, db *
 		optimismPortalTxDeposits[i] = OptimismPortalTransactionDepositEvent{
 			Event:       &transactionDepositEvents[i].ContractEvent,
 			DepositTx:   depositTx,
+			L2TxHash:    l2TxHash,
 			GasLimit:    new(big.Int).SetUint64(depositTx.Gas),
 			FromAddress: txDeposit.From,
 			ToAddress:   txDeposit.To,
@@ -0,0 +1,83 @@
+package contracts
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/google/uuid"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/mantlenetworkio/lithosphere/database"
+	"github.com/mantlenetworkio/lithosphere/database/business"
+	"github.com/mantlenetworkio/lithosphere/database/event"
+	"github.com/mantlenetworkio/lithosphere/event/op-bindings/bindings"
+)
+
+// SystemConfigUpdateEvents extracts the ConfigUpdate events of the SystemConfig. Their data is the abi
+// encoding of the updated values, which is decoded into the fields of the update type.
+func SystemConfigUpdateEvents(contractAddress common.Address, db *database.DB, fromHeight, toHeight *big.Int) ([]business.SystemConfigUpdate, error) {
+	systemConfigAbi, err := bindings.SystemConfigMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	configUpdateEventAbi := systemConfigAbi.Events["ConfigUpdate"]
+	contractEventFilter := event.ContractEvent{ContractAddress: contractAddress, EventSignature: configUpdateEventAbi.ID}
+	configUpdateEvents, err := db.ContractEvents.L1ContractEventsWithFilter(contractEventFilter, fromHeight, toHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make([]business.SystemConfigUpdate, 0, len(configUpdateEvents))
+	for i := range configUpdateEvents {
+		configUpdate := bindings.SystemConfigConfigUpdate{Raw: *configUpdateEvents[i].RLPLog}
+		err = UnpackLog(&configUpdate, configUpdateEvents[i].RLPLog, configUpdateEventAbi.Name, systemConfigAbi)
+		if err != nil {
+			return nil, err
+		}
+		l1BlockNumber, err := db.L1ToL2.GetBlockNumberFromHash(configUpdateEvents[i].BlockHash)
+		if err != nil {
+			return nil, err
+		}
+		update := business.SystemConfigUpdate{
+			GUID:            uuid.New(),
+			L1BlockNumber:   l1BlockNumber,
+			TransactionHash: configUpdateEvents[i].TransactionHash,
+			LogIndex:        configUpdateEvents[i].LogIndex,
+			Version:         configUpdate.Version,
+			UpdateType:      configUpdate.UpdateType,
+			Timestamp:       int64(configUpdateEvents[i].Timestamp),
+		}
+
+		data := configUpdate.Data
+		switch configUpdate.UpdateType {
+		case business.SystemConfigUpdateBatcher:
+			if len(data) != 32 {
+				return nil, fmt.Errorf("invalid batcher update data length %d. tx_hash = %s", len(data), update.TransactionHash)
+			}
+			update.BatcherHash = common.BytesToHash(data)
+		case business.SystemConfigUpdateGasConfig:
+			if len(data) != 64 {
+				return nil, fmt.Errorf("invalid gas config update data length %d. tx_hash = %s", len(data), update.TransactionHash)
+			}
+			update.Overhead = new(big.Int).SetBytes(data[:32])
+			update.Scalar = new(big.Int).SetBytes(data[32:])
+		case business.SystemConfigUpdateGasLimit:
+			if len(data) != 32 {
+				return nil, fmt.Errorf("invalid gas limit update data length %d. tx_hash = %s", len(data), update.TransactionHash)
+			}
+			update.GasLimit = new(big.Int).SetBytes(data)
+		case business.SystemConfigUpdateUnsafeBlockSigner:
+			if len(data) != 32 {
+				return nil, fmt.Errorf("invalid unsafe block signer update data length %d. tx_hash = %s", len(data), update.TransactionHash)
+			}
+			update.UnsafeBlockSigner = common.BytesToAddress(data)
+		default:
+			log.Warn("skipping unknown system config update type", "update_type", configUpdate.UpdateType, "tx_hash", update.TransactionHash)
+			continue
+		}
+		updates = append(updates, update)
+	}
+	return updates, nil
+}
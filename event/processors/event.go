@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"math/big"
 	"time"
 
@@ -15,14 +16,21 @@ import (
 	"github.com/mantlenetworkio/lithosphere/config"
 	"github.com/mantlenetworkio/lithosphere/database"
 	common2 "github.com/mantlenetworkio/lithosphere/database/common"
+	"github.com/mantlenetworkio/lithosphere/event/handlers"
 	"github.com/mantlenetworkio/lithosphere/event/processors/bridge"
 	mantle_da "github.com/mantlenetworkio/lithosphere/event/processors/mantle-da"
 	"github.com/mantlenetworkio/lithosphere/event/processors/stateroot"
+	"github.com/mantlenetworkio/lithosphere/event/processors/systemconfig"
 	"github.com/mantlenetworkio/lithosphere/synchronizer"
 )
 
 var blocksLimit = 10_000
 
+// processFallbackInterval is the interval the stages run at when no synchronizer notification arrives
+const processFallbackInterval = 5 * time.Second
+
+var errReorgedHeader = errors.New("header has been rolled back by a reorg")
+
 type EventProcessor struct {
 	log                         log.Logger
 	db                          *database.DB
@@ -41,12 +49,17 @@ type EventProcessor struct {
 	LatestL2L1InitL2Header      *common2.L2BlockHeader
 	LatestProvenL1Header        *common2.L1BlockHeader
 	LatestFinalizedL1Header     *common2.L1BlockHeader
+	LatestSystemConfigL1Header  *common2.L1BlockHeader
+	notifier                    tasks.Notifier
+	handlerMetrics              handlers.Metricer
+	l1Handlers                  []*handlerStage
+	l2Handlers                  []*handlerStage
 }
 
 func NewBridgeProcessor(log log.Logger, db *database.DB, metrics bridge.Metricer, l1Sync *synchronizer.L1Sync, l2Sync *synchronizer.L2Sync,
-	chainConfig config.ChainConfig, shutdown context.CancelCauseFunc) (*EventProcessor, error) {
+	chainConfig config.ChainConfig, registry *handlers.Registry, handlerMetrics handlers.Metricer, shutdown context.CancelCauseFunc) (*EventProcessor, error) {
 	log = log.New("processor", "bridge")
-	latestL1L2InitL1Header, err := db.L1ToL2.L1L2LatestL1BlockHeader()
+	latestL1L2InitL1Header, err := l1StageCursor(db, common2.L1BridgeInitiatedCursor)
 	if err != nil {
 		return nil, err
 	}
@@ -54,27 +67,39 @@ func NewBridgeProcessor(log log.Logger, db *database.DB, metrics bridge.Metricer
 	if err != nil {
 		return nil, err
 	}
-	latestL1L2FinalizedL2Header, err := db.L1ToL2.L1L2LatestFinalizedL2BlockHeader()
+	latestL1L2FinalizedL2Header, err := l2StageCursor(db, common2.L2BridgeFinalizedCursor)
+	if err != nil {
+		return nil, err
+	}
+	latestStateRootL1Header, err := l1StageCursor(db, common2.L1StateRootCursor)
+	if err != nil {
+		return nil, err
+	}
+	latestMantleDAL1Header, err := l1StageCursor(db, common2.L1MantleDACursor)
+	if err != nil {
+		return nil, err
+	}
+	latestL2L1InitL2Header, err := l2StageCursor(db, common2.L2BridgeInitiatedCursor)
 	if err != nil {
 		return nil, err
 	}
-	latestStateRootL1Header, err := db.StateRoots.StateRootL1BlockHeader()
+	latestProvenL1Header, err := l1StageCursor(db, common2.L1WithdrawProvenCursor)
 	if err != nil {
 		return nil, err
 	}
-	latestMantleDAL1Header, err := db.DataStore.DataStoreL1BlockHeader()
+	latestFinalizedL1Header, err := l1StageCursor(db, common2.L1WithdrawFinalizedCursor)
 	if err != nil {
 		return nil, err
 	}
-	latestL2L1InitL2Header, err := db.L2ToL1.L2L1LatestBlockL2Header()
+	latestSystemConfigL1Header, err := l1StageCursor(db, common2.L1SystemConfigCursor)
 	if err != nil {
 		return nil, err
 	}
-	latestProvenL1Header, err := db.WithdrawProven.WithdrawProvenL1BlockHeader()
+	l1Handlers, err := newHandlerStages(log, db, registry, common2.SyncCursorLayerL1)
 	if err != nil {
 		return nil, err
 	}
-	latestFinalizedL1Header, err := db.WithdrawFinalized.WithdrawFinalizedL1BlockHeader()
+	l2Handlers, err := newHandlerStages(log, db, registry, common2.SyncCursorLayerL2)
 	if err != nil {
 		return nil, err
 	}
@@ -99,31 +124,48 @@ func NewBridgeProcessor(log log.Logger, db *database.DB, metrics bridge.Metricer
 		LatestL2L1InitL2Header:      latestL2L1InitL2Header,
 		LatestProvenL1Header:        latestProvenL1Header,
 		LatestFinalizedL1Header:     latestFinalizedL1Header,
+		LatestSystemConfigL1Header:  latestSystemConfigL1Header,
+		handlerMetrics:              handlerMetrics,
+		l1Handlers:                  l1Handlers,
+		l2Handlers:                  l2Handlers,
 	}, nil
 }
 
 func (ep *EventProcessor) Start() error {
 	ep.log.Info("starting bridge processor...")
-	tickerL1Worker := time.NewTicker(time.Second * 5)
+	var l1Updates, l2Updates <-chan struct{}
+	if ep.l1Sync != nil {
+		l1Updates = ep.l1Sync.Subscribe()
+	}
+	if ep.l2Sync != nil {
+		l2Updates = ep.l2Sync.Subscribe()
+	}
+
 	ep.tasks.Go(func() error {
-		for range tickerL1Worker.C {
+		tasks.Loop(ep.resourceCtx, processFallbackInterval, l1Updates, func() {
 			done := ep.metrics.RecordL1Interval()
 			done(ep.onL1Data())
-		}
+			ep.notifier.Publish()
+		})
 		return nil
 	})
 
-	tickerL2Worker := time.NewTicker(time.Second * 5)
 	ep.tasks.Go(func() error {
-		for range tickerL2Worker.C {
+		tasks.Loop(ep.resourceCtx, processFallbackInterval, l2Updates, func() {
 			done := ep.metrics.RecordL2Interval()
 			done(ep.onL2Data())
-		}
+			ep.notifier.Publish()
+		})
 		return nil
 	})
 	return nil
 }
 
+// Subscribe returns a channel notified every time the L1 or L2 stages have run
+func (ep *EventProcessor) Subscribe() <-chan struct{} {
+	return ep.notifier.Subscribe()
+}
+
 func (ep *EventProcessor) Close() error {
 	ep.resourceCancel()
 	return ep.tasks.Wait()
@@ -151,6 +193,13 @@ func (ep *EventProcessor) onL1Data() error {
 		ep.log.Error("failed to process rollup events", "err", err)
 		errs = errors.Join(errs, err)
 	}
+
+	if err := ep.processSystemConfig(); err != nil {
+		ep.log.Error("failed to process system config events", "err", err)
+		errs = errors.Join(errs, err)
+	}
+
+	ep.processHandlers(ep.l1Handlers)
 	return errs
 }
 
@@ -172,9 +221,47 @@ func (ep *EventProcessor) onL2Data() error {
 		ep.log.Error("failed to process finalized L1 events", "err", err)
 		errs = errors.Join(errs, err)
 	}
+
+	ep.processHandlers(ep.l2Handlers)
 	return errs
 }
 
+// ReprocessL1 runs the stages deriving state from L1 events over the supplied range of indexed
+// L1 headers within the transaction. The stage cursors are left untouched.
+func (ep *EventProcessor) ReprocessL1(tx *database.DB, fromL1Height, toL1Height *big.Int) error {
+	if err := ep.l1InitiatedEvents(ep.log.New("bridge", "l1", "kind", "initiated"), tx, fromL1Height, toL1Height); err != nil {
+		return fmt.Errorf("failed to process initiated L1 events: %w", err)
+	}
+	if err := ep.l1ProvenEvents(ep.log.New("bridge", "l1", "kind", "proven"), tx, fromL1Height, toL1Height); err != nil {
+		return fmt.Errorf("failed to process proven L1 events: %w", err)
+	}
+	if err := ep.l1FinalizedEvents(ep.log.New("bridge", "l1", "kind", "finalization"), tx, fromL1Height, toL1Height); err != nil {
+		return fmt.Errorf("failed to process finalized L1 events: %w", err)
+	}
+	if err := ep.stateRootEvents(ep.log.New("rollup", "l1", "kind", "state root"), tx, fromL1Height, toL1Height); err != nil {
+		return fmt.Errorf("failed to process state root events: %w", err)
+	}
+	if err := ep.mantleDAEvents(ep.log.New("rollup", "l1", "kind", "mantleDa"), tx, fromL1Height, toL1Height); err != nil {
+		return fmt.Errorf("failed to process mantle da events: %w", err)
+	}
+	if err := ep.systemConfigEvents(ep.log.New("rollup", "l1", "kind", "system config"), tx, fromL1Height, toL1Height); err != nil {
+		return fmt.Errorf("failed to process system config events: %w", err)
+	}
+	return nil
+}
+
+// ReprocessL2 runs the stages deriving state from L2 events over the supplied range of indexed
+// L2 headers within the transaction. The stage cursors are left untouched.
+func (ep *EventProcessor) ReprocessL2(tx *database.DB, fromL2Height, toL2Height *big.Int) error {
+	if err := ep.l2InitiatedEvents(ep.log.New("bridge", "l2", "kind", "initiated"), tx, fromL2Height, toL2Height); err != nil {
+		return fmt.Errorf("failed to process initiated L2 events: %w", err)
+	}
+	if err := ep.l2FinalizedEvents(ep.log.New("bridge", "l2", "kind", "finalization"), tx, fromL2Height, toL2Height); err != nil {
+		return fmt.Errorf("failed to process finalized L2 events: %w", err)
+	}
+	return nil
+}
+
 func (ep *EventProcessor) processInitiatedL1Events() error {
 	l1BridgeLog := ep.log.New("bridge", "l1", "kind", "initiated")
 	lastL1BlockNumber := big.NewInt(int64(ep.chainConfig.L1StartingHeight))
@@ -196,28 +283,18 @@ func (ep *EventProcessor) processInitiatedL1Events() error {
 	}
 	fromL1Height, toL1Height := new(big.Int).Add(lastL1BlockNumber, bigint.One), latestL1Header.Number
 	if err := ep.db.Transaction(func(tx *database.DB) error {
-		l1BedrockStartingHeight := big.NewInt(int64(ep.chainConfig.L1BedrockStartingHeight))
-		if l1BedrockStartingHeight.Cmp(fromL1Height) > 0 {
-			legacyFromL1Height, legacyToL1Height := fromL1Height, toL1Height
-			if l1BedrockStartingHeight.Cmp(toL1Height) <= 0 {
-				legacyToL1Height = new(big.Int).Sub(l1BedrockStartingHeight, bigint.One)
-			}
-
-			legacyBridgeLog := l1BridgeLog.New("mode", "legacy", "from_block_number", legacyFromL1Height, "to_block_number", legacyToL1Height)
-			legacyBridgeLog.Info("scanning for initiated bridge events")
-			if err := bridge.LegacyL1ProcessInitiatedBridgeEvents(legacyBridgeLog, tx, ep.metrics, ep.chainConfig.L1Contracts, legacyFromL1Height, legacyToL1Height); err != nil {
-				return err
-			} else if legacyToL1Height.Cmp(toL1Height) == 0 {
-				return nil
-			}
-			legacyBridgeLog.Info("detected switch to bedrock", "bedrock_block_number", l1BedrockStartingHeight)
-			fromL1Height = l1BedrockStartingHeight
+		if err := lockL1Headers(tx, ep.LatestL1L2InitL1Header, latestL1Header); err != nil {
+			return err
 		}
-		l1BridgeLog = l1BridgeLog.New("from_block_number", fromL1Height, "to_block_number", toL1Height)
-		l1BridgeLog.Info("scanning for initiated bridge events")
-		return bridge.L1ProcessInitiatedBridgeEvents(l1BridgeLog, tx, ep.metrics, ep.chainConfig.L1Contracts, fromL1Height, toL1Height)
+		if err := tx.SyncCursors.StoreSyncCursor(common2.L1BridgeInitiatedCursor, common2.SyncCursorLayerL1, latestL1Header.Number, latestL1Header.Hash, latestL1Header.Timestamp); err != nil {
+			return err
+		}
+		return ep.l1InitiatedEvents(l1BridgeLog, tx, fromL1Height, toL1Height)
 	}); err != nil {
-		return err
+		return ep.rewindOnReorg(err, func() (err error) {
+			ep.LatestL1L2InitL1Header, err = l1StageCursor(ep.db, common2.L1BridgeInitiatedCursor)
+			return err
+		})
 	}
 	ep.LatestL1L2InitL1Header = latestL1Header
 	ep.metrics.RecordL1LatestHeight(latestL1Header.Number)
@@ -244,27 +321,18 @@ func (ep *EventProcessor) processInitiatedL2Events() error {
 	}
 	fromL2Height, toL2Height := new(big.Int).Add(lastL2BlockNumber, bigint.One), latestL2Header.Number
 	if err := ep.db.Transaction(func(tx *database.DB) error {
-		l2BedrockStartingHeight := big.NewInt(int64(ep.chainConfig.L2BedrockStartingHeight))
-		if l2BedrockStartingHeight.Cmp(fromL2Height) > 0 { // OP Mainnet & OP Goerli Only
-			legacyFromL2Height, legacyToL2Height := fromL2Height, toL2Height
-			if l2BedrockStartingHeight.Cmp(toL2Height) <= 0 {
-				legacyToL2Height = new(big.Int).Sub(l2BedrockStartingHeight, bigint.One)
-			}
-			legacyBridgeLog := l2BridgeLog.New("mode", "legacy", "from_block_number", legacyFromL2Height, "to_block_number", legacyToL2Height)
-			legacyBridgeLog.Info("scanning for initiated bridge events")
-			if err := bridge.LegacyL2ProcessInitiatedBridgeEvents(legacyBridgeLog, tx, ep.metrics, ep.chainConfig.L2Contracts, legacyFromL2Height, legacyToL2Height); err != nil {
-				return err
-			} else if legacyToL2Height.Cmp(toL2Height) == 0 {
-				return nil
-			}
-			legacyBridgeLog.Info("detected switch to bedrock")
-			fromL2Height = l2BedrockStartingHeight
+		if err := lockL2Headers(tx, ep.LatestL2L1InitL2Header, latestL2Header); err != nil {
+			return err
 		}
-		l2BridgeLog = l2BridgeLog.New("from_block_number", fromL2Height, "to_block_number", toL2Height)
-		l2BridgeLog.Info("scanning for initiated bridge events")
-		return bridge.L2ProcessInitiatedBridgeEvents(l2BridgeLog, tx, ep.metrics, ep.chainConfig.L2Contracts, fromL2Height, toL2Height)
+		if err := tx.SyncCursors.StoreSyncCursor(common2.L2BridgeInitiatedCursor, common2.SyncCursorLayerL2, latestL2Header.Number, latestL2Header.Hash, latestL2Header.Timestamp); err != nil {
+			return err
+		}
+		return ep.l2InitiatedEvents(l2BridgeLog, tx, fromL2Height, toL2Height)
 	}); err != nil {
-		return err
+		return ep.rewindOnReorg(err, func() (err error) {
+			ep.LatestL2L1InitL2Header, err = l2StageCursor(ep.db, common2.L2BridgeInitiatedCursor)
+			return err
+		})
 	}
 	ep.LatestL2L1InitL2Header = latestL2Header
 	ep.metrics.RecordL2LatestHeight(latestL2Header.Number)
@@ -295,15 +363,18 @@ func (ep *EventProcessor) processProvenL1Events() error {
 	}
 	fromL1Height, toL1Height := new(big.Int).Add(lastProvenL1BlockNumber, bigint.One), latestL1Header.Number
 	if err := ep.db.Transaction(func(tx *database.DB) error {
-		l1BridgeLog = l1BridgeLog.New("from_block_number", fromL1Height, "to_block_number", toL1Height)
-		l1BridgeLog.Info("scanning for withdraw proven events")
-		if err := bridge.L1ProcessProvenBridgeEvents(l1BridgeLog, tx, ep.metrics, ep.chainConfig.L1Contracts, fromL1Height, toL1Height); err != nil {
-			ep.log.Error("failed to index withdraw proven events", "err", err)
+		if err := lockL1Headers(tx, ep.LatestProvenL1Header, latestL1Header); err != nil {
 			return err
 		}
-		return nil
+		if err := tx.SyncCursors.StoreSyncCursor(common2.L1WithdrawProvenCursor, common2.SyncCursorLayerL1, latestL1Header.Number, latestL1Header.Hash, latestL1Header.Timestamp); err != nil {
+			return err
+		}
+		return ep.l1ProvenEvents(l1BridgeLog, tx, fromL1Height, toL1Height)
 	}); err != nil {
-		return err
+		return ep.rewindOnReorg(err, func() (err error) {
+			ep.LatestProvenL1Header, err = l1StageCursor(ep.db, common2.L1WithdrawProvenCursor)
+			return err
+		})
 	}
 	ep.LatestProvenL1Header = latestL1Header
 	ep.metrics.RecordL1LatestProvenHeight(lastProvenL1BlockNumber)
@@ -334,27 +405,18 @@ func (ep *EventProcessor) processFinalizedL1Events() error {
 	}
 	fromL1Height, toL1Height := new(big.Int).Add(lastFinalizedL1BlockNumber, bigint.One), latestL1Header.Number
 	if err := ep.db.Transaction(func(tx *database.DB) error {
-		l1BedrockStartingHeight := big.NewInt(int64(ep.chainConfig.L1BedrockStartingHeight))
-		if l1BedrockStartingHeight.Cmp(fromL1Height) > 0 {
-			legacyFromL1Height, legacyToL1Height := fromL1Height, toL1Height
-			if l1BedrockStartingHeight.Cmp(toL1Height) <= 0 {
-				legacyToL1Height = new(big.Int).Sub(l1BedrockStartingHeight, bigint.One)
-			}
-			legacyBridgeLog := l1BridgeLog.New("mode", "legacy", "from_block_number", legacyFromL1Height, "to_block_number", legacyToL1Height)
-			legacyBridgeLog.Info("scanning for finalized bridge events")
-			if err := bridge.LegacyL1ProcessFinalizedBridgeEvents(legacyBridgeLog, tx, ep.metrics, ep.l1Sync.EthClient, ep.chainConfig.L1Contracts, legacyFromL1Height, legacyToL1Height); err != nil {
-				return err
-			} else if legacyToL1Height.Cmp(toL1Height) == 0 {
-				return nil
-			}
-			legacyBridgeLog.Info("detected switch to bedrock")
-			fromL1Height = l1BedrockStartingHeight
+		if err := lockL1Headers(tx, ep.LatestFinalizedL1Header, latestL1Header); err != nil {
+			return err
 		}
-		l1BridgeLog = l1BridgeLog.New("from_block_number", fromL1Height, "to_block_number", toL1Height)
-		l1BridgeLog.Info("scanning for finalized bridge events")
-		return bridge.L1ProcessFinalizedBridgeEvents(l1BridgeLog, tx, ep.metrics, ep.chainConfig.L1Contracts, fromL1Height, toL1Height)
+		if err := tx.SyncCursors.StoreSyncCursor(common2.L1WithdrawFinalizedCursor, common2.SyncCursorLayerL1, latestL1Header.Number, latestL1Header.Hash, latestL1Header.Timestamp); err != nil {
+			return err
+		}
+		return ep.l1FinalizedEvents(l1BridgeLog, tx, fromL1Height, toL1Height)
 	}); err != nil {
-		return err
+		return ep.rewindOnReorg(err, func() (err error) {
+			ep.LatestFinalizedL1Header, err = l1StageCursor(ep.db, common2.L1WithdrawFinalizedCursor)
+			return err
+		})
 	}
 	ep.LatestFinalizedL1Header = latestL1Header
 	ep.metrics.RecordL1LatestFinalizedHeight(lastFinalizedL1BlockNumber)
@@ -394,28 +456,18 @@ func (ep *EventProcessor) processFinalizedL2Events() error {
 	log.Info("latest l2 header", "latestL2Header", latestL2Header.Number)
 	fromL2Height, toL2Height := new(big.Int).Add(lastFinalizedL2BlockNumber, bigint.One), latestL2Header.Number
 	if err := ep.db.Transaction(func(tx *database.DB) error {
-		l2BedrockStartingHeight := big.NewInt(int64(ep.chainConfig.L2BedrockStartingHeight))
-		if l2BedrockStartingHeight.Cmp(fromL2Height) > 0 {
-			legacyFromL2Height, legacyToL2Height := fromL2Height, toL2Height
-			if l2BedrockStartingHeight.Cmp(toL2Height) <= 0 {
-				legacyToL2Height = new(big.Int).Sub(l2BedrockStartingHeight, bigint.One)
-			}
-			legacyBridgeLog := l2BridgeLog.New("mode", "legacy", "from_block_number", legacyFromL2Height, "to_block_number", legacyToL2Height)
-			legacyBridgeLog.Info("scanning for finalized bridge events")
-			if err := bridge.LegacyL2ProcessFinalizedBridgeEvents(legacyBridgeLog, tx, ep.metrics, ep.chainConfig.L2Contracts, legacyFromL2Height, legacyToL2Height); err != nil {
-				return err
-			} else if legacyToL2Height.Cmp(toL2Height) == 0 {
-				return nil
-			}
-			legacyBridgeLog.Info("detected switch to bedrock", "bedrock_block_number", l2BedrockStartingHeight)
-			fromL2Height = l2BedrockStartingHeight
+		if err := lockL2Headers(tx, ep.LatestL1L2FinalizedL2Header, latestL2Header); err != nil {
+			return err
 		}
-
-		l2BridgeLog = l2BridgeLog.New("from_block_number", fromL2Height, "to_block_number", toL2Height)
-		l2BridgeLog.Info("scanning for finalized bridge events")
-		return bridge.L2ProcessFinalizedBridgeEvents(l2BridgeLog, tx, ep.metrics, ep.chainConfig.L2Contracts, fromL2Height, toL2Height)
+		if err := tx.SyncCursors.StoreSyncCursor(common2.L2BridgeFinalizedCursor, common2.SyncCursorLayerL2, latestL2Header.Number, latestL2Header.Hash, latestL2Header.Timestamp); err != nil {
+			return err
+		}
+		return ep.l2FinalizedEvents(l2BridgeLog, tx, fromL2Height, toL2Height)
 	}); err != nil {
-		return err
+		return ep.rewindOnReorg(err, func() (err error) {
+			ep.LatestL1L2FinalizedL2Header, err = l2StageCursor(ep.db, common2.L2BridgeFinalizedCursor)
+			return err
+		})
 	}
 	ep.LatestL1L2FinalizedL2Header = latestL2Header
 	ep.metrics.RecordL2LatestFinalizedHeight(lastFinalizedL2BlockNumber)
@@ -442,15 +494,18 @@ func (ep *EventProcessor) processRollupStateRoot() error {
 	}
 	fromL1Height, toL1Height := new(big.Int).Add(lastStateRootL1BlockNumber, bigint.One), latestL1StateRootHeader.Number
 	if err := ep.db.Transaction(func(tx *database.DB) error {
-		rollupStateRootLog = rollupStateRootLog.New("from_block_number", fromL1Height, "to_block_number", toL1Height)
-		rollupStateRootLog.Info("scanning for state root events")
-		if err := stateroot.L2OutputEvent(rollupStateRootLog, tx, ep.metrics, ep.chainConfig.L1Contracts, fromL1Height, toL1Height); err != nil {
-			ep.log.Error("failed to index l1 l2output proposed events", "err", err)
+		if err := lockL1Headers(tx, ep.LatestStateRootL1Header, latestL1StateRootHeader); err != nil {
 			return err
 		}
-		return nil
+		if err := tx.SyncCursors.StoreSyncCursor(common2.L1StateRootCursor, common2.SyncCursorLayerL1, latestL1StateRootHeader.Number, latestL1StateRootHeader.Hash, latestL1StateRootHeader.Timestamp); err != nil {
+			return err
+		}
+		return ep.stateRootEvents(rollupStateRootLog, tx, fromL1Height, toL1Height)
 	}); err != nil {
-		return err
+		return ep.rewindOnReorg(err, func() (err error) {
+			ep.LatestStateRootL1Header, err = l1StageCursor(ep.db, common2.L1StateRootCursor)
+			return err
+		})
 	}
 	ep.LatestStateRootL1Header = latestL1StateRootHeader
 	ep.metrics.RecordL1LatestRollupSateRootHeight(lastStateRootL1BlockNumber)
@@ -478,17 +533,269 @@ func (ep *EventProcessor) processRollupMantleDA() error {
 	}
 	fromL1Height, toL1Height := new(big.Int).Add(lastRollupMantleDaL1BlockNumber, bigint.One), latestL1RollupMantleDaHeader.Number
 	if err := ep.db.Transaction(func(tx *database.DB) error {
-		rollupMantleDaLog = rollupMantleDaLog.New("from_block_number", fromL1Height, "to_block_number", toL1Height)
-		rollupMantleDaLog.Info("scanning for mantle da events")
-		if err := mantle_da.L1ProcessMantleDAEvents(rollupMantleDaLog, tx, ep.chainConfig.L1Contracts, fromL1Height, toL1Height); err != nil {
-			ep.log.Error("failed to index l1 mantle da events", "err", err)
+		if err := lockL1Headers(tx, ep.LatestMantleDAL1Header, latestL1RollupMantleDaHeader); err != nil {
 			return err
 		}
-		return nil
+		if err := tx.SyncCursors.StoreSyncCursor(common2.L1MantleDACursor, common2.SyncCursorLayerL1, latestL1RollupMantleDaHeader.Number, latestL1RollupMantleDaHeader.Hash, latestL1RollupMantleDaHeader.Timestamp); err != nil {
+			return err
+		}
+		return ep.mantleDAEvents(rollupMantleDaLog, tx, fromL1Height, toL1Height)
 	}); err != nil {
-		return err
+		return ep.rewindOnReorg(err, func() (err error) {
+			ep.LatestMantleDAL1Header, err = l1StageCursor(ep.db, common2.L1MantleDACursor)
+			return err
+		})
 	}
 	ep.LatestMantleDAL1Header = latestL1RollupMantleDaHeader
 	ep.metrics.RecordL1LatestRollupMantleDaHeight(lastRollupMantleDaL1BlockNumber)
 	return nil
 }
+
+func (ep *EventProcessor) processSystemConfig() error {
+	systemConfigLog := ep.log.New("rollup", "l1", "kind", "system config")
+	lastSystemConfigL1BlockNumber := big.NewInt(int64(ep.chainConfig.L1StartingHeight))
+	if ep.LatestSystemConfigL1Header != nil {
+		lastSystemConfigL1BlockNumber = ep.LatestSystemConfigL1Header.Number
+	}
+	latestSystemConfigL1HeaderScope := func(db *gorm.DB) *gorm.DB {
+		newQuery := db.Session(&gorm.Session{NewDB: true})
+		headers := newQuery.Model(common2.L1BlockHeader{}).Where("number > ?", lastSystemConfigL1BlockNumber)
+		return db.Where("number = (?)", newQuery.Table("(?) as block_numbers", headers.Order("number ASC").Limit(blocksLimit)).Select("MAX(number)"))
+	}
+	latestL1SystemConfigHeader, err := ep.db.Blocks.L1BlockHeaderWithScope(latestSystemConfigL1HeaderScope)
+	if err != nil {
+		return fmt.Errorf("failed to query new L1 state: %w", err)
+	} else if latestL1SystemConfigHeader == nil {
+		systemConfigLog.Debug("no new L1 state found for process system config")
+		return nil
+	}
+	fromL1Height, toL1Height := new(big.Int).Add(lastSystemConfigL1BlockNumber, bigint.One), latestL1SystemConfigHeader.Number
+	if err := ep.db.Transaction(func(tx *database.DB) error {
+		if err := lockL1Headers(tx, ep.LatestSystemConfigL1Header, latestL1SystemConfigHeader); err != nil {
+			return err
+		}
+		if err := tx.SyncCursors.StoreSyncCursor(common2.L1SystemConfigCursor, common2.SyncCursorLayerL1, latestL1SystemConfigHeader.Number, latestL1SystemConfigHeader.Hash, latestL1SystemConfigHeader.Timestamp); err != nil {
+			return err
+		}
+		return ep.systemConfigEvents(systemConfigLog, tx, fromL1Height, toL1Height)
+	}); err != nil {
+		return ep.rewindOnReorg(err, func() (err error) {
+			ep.LatestSystemConfigL1Header, err = l1StageCursor(ep.db, common2.L1SystemConfigCursor)
+			return err
+		})
+	}
+	ep.LatestSystemConfigL1Header = latestL1SystemConfigHeader
+	ep.metrics.RecordL1LatestSystemConfigHeight(latestL1SystemConfigHeader.Number)
+	return nil
+}
+
+func (ep *EventProcessor) l1InitiatedEvents(l1BridgeLog log.Logger, tx *database.DB, fromL1Height, toL1Height *big.Int) error {
+	l1BedrockStartingHeight := big.NewInt(int64(ep.chainConfig.L1BedrockStartingHeight))
+	if l1BedrockStartingHeight.Cmp(fromL1Height) > 0 {
+		legacyFromL1Height, legacyToL1Height := fromL1Height, toL1Height
+		if l1BedrockStartingHeight.Cmp(toL1Height) <= 0 {
+			legacyToL1Height = new(big.Int).Sub(l1BedrockStartingHeight, bigint.One)
+		}
+
+		legacyBridgeLog := l1BridgeLog.New("mode", "legacy", "from_block_number", legacyFromL1Height, "to_block_number", legacyToL1Height)
+		legacyBridgeLog.Info("scanning for initiated bridge events")
+		if err := bridge.LegacyL1ProcessInitiatedBridgeEvents(legacyBridgeLog, tx, ep.metrics, ep.chainConfig.L1Contracts, legacyFromL1Height, legacyToL1Height); err != nil {
+			return err
+		} else if legacyToL1Height.Cmp(toL1Height) == 0 {
+			return nil
+		}
+		legacyBridgeLog.Info("detected switch to bedrock", "bedrock_block_number", l1BedrockStartingHeight)
+		fromL1Height = l1BedrockStartingHeight
+	}
+	l1BridgeLog = l1BridgeLog.New("from_block_number", fromL1Height, "to_block_number", toL1Height)
+	l1BridgeLog.Info("scanning for initiated bridge events")
+	return bridge.L1ProcessInitiatedBridgeEvents(l1BridgeLog, tx, ep.metrics, ep.chainConfig.L1Contracts, fromL1Height, toL1Height)
+}
+
+func (ep *EventProcessor) l2InitiatedEvents(l2BridgeLog log.Logger, tx *database.DB, fromL2Height, toL2Height *big.Int) error {
+	l2BedrockStartingHeight := big.NewInt(int64(ep.chainConfig.L2BedrockStartingHeight))
+	if l2BedrockStartingHeight.Cmp(fromL2Height) > 0 { // OP Mainnet & OP Goerli Only
+		legacyFromL2Height, legacyToL2Height := fromL2Height, toL2Height
+		if l2BedrockStartingHeight.Cmp(toL2Height) <= 0 {
+			legacyToL2Height = new(big.Int).Sub(l2BedrockStartingHeight, bigint.One)
+		}
+		legacyBridgeLog := l2BridgeLog.New("mode", "legacy", "from_block_number", legacyFromL2Height, "to_block_number", legacyToL2Height)
+		legacyBridgeLog.Info("scanning for initiated bridge events")
+		if err := bridge.LegacyL2ProcessInitiatedBridgeEvents(legacyBridgeLog, tx, ep.metrics, ep.chainConfig.L2Contracts, legacyFromL2Height, legacyToL2Height); err != nil {
+			return err
+		} else if legacyToL2Height.Cmp(toL2Height) == 0 {
+			return nil
+		}
+		legacyBridgeLog.Info("detected switch to bedrock")
+		fromL2Height = l2BedrockStartingHeight
+	}
+	l2BridgeLog = l2BridgeLog.New("from_block_number", fromL2Height, "to_block_number", toL2Height)
+	l2BridgeLog.Info("scanning for initiated bridge events")
+	return bridge.L2ProcessInitiatedBridgeEvents(l2BridgeLog, tx, ep.metrics, ep.chainConfig.L2Contracts, fromL2Height, toL2Height)
+}
+
+func (ep *EventProcessor) l1ProvenEvents(l1BridgeLog log.Logger, tx *database.DB, fromL1Height, toL1Height *big.Int) error {
+	l1BridgeLog = l1BridgeLog.New("from_block_number", fromL1Height, "to_block_number", toL1Height)
+	l1BridgeLog.Info("scanning for withdraw proven events")
+	if err := bridge.L1ProcessProvenBridgeEvents(l1BridgeLog, tx, ep.metrics, ep.chainConfig.L1Contracts, fromL1Height, toL1Height); err != nil {
+		ep.log.Error("failed to index withdraw proven events", "err", err)
+		return err
+	}
+	return nil
+}
+
+func (ep *EventProcessor) l1FinalizedEvents(l1BridgeLog log.Logger, tx *database.DB, fromL1Height, toL1Height *big.Int) error {
+	l1BedrockStartingHeight := big.NewInt(int64(ep.chainConfig.L1BedrockStartingHeight))
+	if l1BedrockStartingHeight.Cmp(fromL1Height) > 0 {
+		legacyFromL1Height, legacyToL1Height := fromL1Height, toL1Height
+		if l1BedrockStartingHeight.Cmp(toL1Height) <= 0 {
+			legacyToL1Height = new(big.Int).Sub(l1BedrockStartingHeight, bigint.One)
+		}
+		legacyBridgeLog := l1BridgeLog.New("mode", "legacy", "from_block_number", legacyFromL1Height, "to_block_number", legacyToL1Height)
+		legacyBridgeLog.Info("scanning for finalized bridge events")
+		if err := bridge.LegacyL1ProcessFinalizedBridgeEvents(legacyBridgeLog, tx, ep.metrics, ep.chainConfig.L1Contracts, legacyFromL1Height, legacyToL1Height); err != nil {
+			return err
+		} else if legacyToL1Height.Cmp(toL1Height) == 0 {
+			return nil
+		}
+		legacyBridgeLog.Info("detected switch to bedrock")
+		fromL1Height = l1BedrockStartingHeight
+	}
+	l1BridgeLog = l1BridgeLog.New("from_block_number", fromL1Height, "to_block_number", toL1Height)
+	l1BridgeLog.Info("scanning for finalized bridge events")
+	return bridge.L1ProcessFinalizedBridgeEvents(l1BridgeLog, tx, ep.metrics, ep.chainConfig.L1Contracts, fromL1Height, toL1Height)
+}
+
+func (ep *EventProcessor) l2FinalizedEvents(l2BridgeLog log.Logger, tx *database.DB, fromL2Height, toL2Height *big.Int) error {
+	l2BedrockStartingHeight := big.NewInt(int64(ep.chainConfig.L2BedrockStartingHeight))
+	if l2BedrockStartingHeight.Cmp(fromL2Height) > 0 {
+		legacyFromL2Height, legacyToL2Height := fromL2Height, toL2Height
+		if l2BedrockStartingHeight.Cmp(toL2Height) <= 0 {
+			legacyToL2Height = new(big.Int).Sub(l2BedrockStartingHeight, bigint.One)
+		}
+		legacyBridgeLog := l2BridgeLog.New("mode", "legacy", "from_block_number", legacyFromL2Height, "to_block_number", legacyToL2Height)
+		legacyBridgeLog.Info("scanning for finalized bridge events")
+		if err := bridge.LegacyL2ProcessFinalizedBridgeEvents(legacyBridgeLog, tx, ep.metrics, ep.chainConfig.L2Contracts, legacyFromL2Height, legacyToL2Height); err != nil {
+			return err
+		} else if legacyToL2Height.Cmp(toL2Height) == 0 {
+			return nil
+		}
+		legacyBridgeLog.Info("detected switch to bedrock", "bedrock_block_number", l2BedrockStartingHeight)
+		fromL2Height = l2BedrockStartingHeight
+	}
+
+	l2BridgeLog = l2BridgeLog.New("from_block_number", fromL2Height, "to_block_number", toL2Height)
+	l2BridgeLog.Info("scanning for finalized bridge events")
+	return bridge.L2ProcessFinalizedBridgeEvents(l2BridgeLog, tx, ep.metrics, ep.chainConfig.L2Contracts, fromL2Height, toL2Height)
+}
+
+func (ep *EventProcessor) stateRootEvents(rollupStateRootLog log.Logger, tx *database.DB, fromL1Height, toL1Height *big.Int) error {
+	rollupStateRootLog = rollupStateRootLog.New("from_block_number", fromL1Height, "to_block_number", toL1Height)
+	rollupStateRootLog.Info("scanning for state root events")
+	if err := stateroot.L2OutputEvent(rollupStateRootLog, tx, ep.metrics, ep.chainConfig.L1Contracts, fromL1Height, toL1Height); err != nil {
+		ep.log.Error("failed to index l1 l2output proposed events", "err", err)
+		return err
+	}
+	return nil
+}
+
+func (ep *EventProcessor) mantleDAEvents(rollupMantleDaLog log.Logger, tx *database.DB, fromL1Height, toL1Height *big.Int) error {
+	rollupMantleDaLog = rollupMantleDaLog.New("from_block_number", fromL1Height, "to_block_number", toL1Height)
+	rollupMantleDaLog.Info("scanning for mantle da events")
+	if err := mantle_da.L1ProcessMantleDAEvents(rollupMantleDaLog, tx, ep.chainConfig.L1Contracts, fromL1Height, toL1Height); err != nil {
+		ep.log.Error("failed to index l1 mantle da events", "err", err)
+		return err
+	}
+	return nil
+}
+
+func (ep *EventProcessor) systemConfigEvents(systemConfigLog log.Logger, tx *database.DB, fromL1Height, toL1Height *big.Int) error {
+	systemConfigLog = systemConfigLog.New("from_block_number", fromL1Height, "to_block_number", toL1Height)
+	systemConfigLog.Info("scanning for system config events")
+	if err := systemconfig.L1ProcessSystemConfigEvents(systemConfigLog, tx, ep.chainConfig.L1Contracts, fromL1Height, toL1Height); err != nil {
+		ep.log.Error("failed to index l1 system config update events", "err", err)
+		return err
+	}
+	return nil
+}
+
+// rewindOnReorg re-derives a cursor from the indexed state, as done on startup, when processing
+// was aborted because the headers it covers have been rolled back by a reorg.
+func (ep *EventProcessor) rewindOnReorg(err error, rewind func() error) error {
+	if !errors.Is(err, errReorgedHeader) {
+		return err
+	}
+	ep.log.Warn("processed headers rolled back by a reorg, rewinding cursor")
+	return rewind()
+}
+
+// l1StageCursor resumes a stage from its sync cursor, nil when the stage hasn't processed any header yet.
+// The cursors are moved back along with the headers on a reorg, a cursor to a missing header is an error.
+func l1StageCursor(db *database.DB, name string) (*common2.L1BlockHeader, error) {
+	cursor, err := db.SyncCursors.SyncCursor(name)
+	if err != nil || cursor == nil {
+		return nil, err
+	}
+	header, err := db.Blocks.L1BlockHeader(cursor.BlockHash)
+	if err != nil {
+		return nil, err
+	} else if header == nil {
+		return nil, fmt.Errorf("sync cursor %s points to unindexed l1 header %s", name, cursor.BlockHash)
+	}
+	return header, nil
+}
+
+// l2StageCursor is the L2 counterpart of l1StageCursor
+func l2StageCursor(db *database.DB, name string) (*common2.L2BlockHeader, error) {
+	cursor, err := db.SyncCursors.SyncCursor(name)
+	if err != nil || cursor == nil {
+		return nil, err
+	}
+	header, err := db.Blocks.L2BlockHeader(cursor.BlockHash)
+	if err != nil {
+		return nil, err
+	} else if header == nil {
+		return nil, fmt.Errorf("sync cursor %s points to unindexed l2 header %s", name, cursor.BlockHash)
+	}
+	return header, nil
+}
+
+// lockL1Headers takes a share lock on the supplied headers for the remainder of the transaction. A
+// concurrent reorg rollback has to wait for the transaction to complete before removing them along
+// with the state derived from them. If one of them has already been rolled back, errReorgedHeader is returned.
+func lockL1Headers(tx *database.DB, headers ...*common2.L1BlockHeader) error {
+	for _, header := range headers {
+		if header == nil {
+			continue
+		}
+		filter := common2.BlockHeader{Hash: header.Hash}
+		lockedHeader, err := tx.Blocks.L1BlockHeaderWithScope(func(db *gorm.DB) *gorm.DB {
+			return db.Clauses(clause.Locking{Strength: "SHARE"}).Where(&filter)
+		})
+		if err != nil {
+			return err
+		} else if lockedHeader == nil {
+			return errReorgedHeader
+		}
+	}
+	return nil
+}
+
+// lockL2Headers is the L2 counterpart of lockL1Headers
+func lockL2Headers(tx *database.DB, headers ...*common2.L2BlockHeader) error {
+	for _, header := range headers {
+		if header == nil {
+			continue
+		}
+		filter := common2.BlockHeader{Hash: header.Hash}
+		lockedHeader, err := tx.Blocks.L2BlockHeaderWithScope(func(db *gorm.DB) *gorm.DB {
+			return db.Clauses(clause.Locking{Strength: "SHARE"}).Where(&filter)
+		})
+		if err != nil {
+			return err
+		} else if lockedHeader == nil {
+			return errReorgedHeader
+		}
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package processors
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"gorm.io/gorm"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/mantlenetworkio/lithosphere/common/bigint"
+	"github.com/mantlenetworkio/lithosphere/database"
+	common2 "github.com/mantlenetworkio/lithosphere/database/common"
+	"github.com/mantlenetworkio/lithosphere/database/event"
+	"github.com/mantlenetworkio/lithosphere/event/handlers"
+)
+
+// handlerStage runs a registered handler over the indexed headers of its chain, from its own cursor
+type handlerStage struct {
+	handlers.Registration
+	log     log.Logger
+	cursor  string
+	filters []event.ContractEvent
+	latest  *common2.BlockHeader
+}
+
+func newHandlerStages(log log.Logger, db *database.DB, registry *handlers.Registry, chain string) ([]*handlerStage, error) {
+	var stages []*handlerStage
+	for _, registration := range registry.Registrations(chain) {
+		stage := &handlerStage{
+			Registration: registration,
+			log:          log.New("handler", registration.Handler.Name(), "chain", chain),
+			cursor:       handlers.CursorName(registration.Handler),
+		}
+		for _, key := range registration.Keys {
+			stage.filters = append(stage.filters, event.ContractEvent{ContractAddress: key.Contract, EventSignature: key.EventSignature})
+		}
+		latest, err := handlerStageCursor(db, stage)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load cursor of handler %s: %w", registration.Handler.Name(), err)
+		}
+		stage.latest = latest
+		stages = append(stages, stage)
+	}
+	return stages, nil
+}
+
+// handlerStageCursor resumes a handler from its cursor. A handler without one starts from the starting height.
+func handlerStageCursor(db *database.DB, stage *handlerStage) (*common2.BlockHeader, error) {
+	if stage.Chain == common2.SyncCursorLayerL1 {
+		header, err := l1StageCursor(db, stage.cursor)
+		if err != nil || header == nil {
+			return nil, err
+		}
+		return &header.BlockHeader, nil
+	}
+	header, err := l2StageCursor(db, stage.cursor)
+	if err != nil || header == nil {
+		return nil, err
+	}
+	return &header.BlockHeader, nil
+}
+
+// processHandlers runs the handler stages one after the other. A failing handler is retried on the next run
+// without holding back the others, its failures are only reported through its logs and metrics.
+func (ep *EventProcessor) processHandlers(stages []*handlerStage) {
+	for _, stage := range stages {
+		done := ep.handlerMetrics.RecordInterval(stage.Handler.Name())
+		err := ep.processHandler(stage)
+		if err != nil {
+			stage.log.Error("failed to process handler events", "err", err)
+		}
+		done(err)
+	}
+}
+
+func (ep *EventProcessor) processHandler(stage *handlerStage) (err error) {
+	lastBlockNumber := big.NewInt(int64(ep.chainConfig.L1StartingHeight))
+	if stage.Chain == common2.SyncCursorLayerL2 {
+		lastBlockNumber = big.NewInt(int64(ep.chainConfig.L2StartingHeight))
+	}
+	if stage.latest != nil {
+		lastBlockNumber = stage.latest.Number
+	} else {
+		extraction, err := ep.db.SyncCursors.SyncCursor(handlers.ExtractionCursorName(stage.Handler))
+		if err != nil {
+			return fmt.Errorf("failed to query extraction cursor: %w", err)
+		} else if err := checkHandlerExtraction(stage.Chain, lastBlockNumber, extraction); err != nil {
+			return err
+		}
+	}
+	latestHeaderScope := func(db *gorm.DB) *gorm.DB {
+		newQuery := db.Session(&gorm.Session{NewDB: true})
+		headers := newQuery.Table(stage.Chain+"_block_headers").Where("number > ?", lastBlockNumber)
+		return db.Where("number = (?)", newQuery.Table("(?) as block_numbers", headers.Order("number ASC").Limit(blocksLimit)).Select("MAX(number)"))
+	}
+	var latestHeader *common2.BlockHeader
+	if stage.Chain == common2.SyncCursorLayerL1 {
+		header, err := ep.db.Blocks.L1BlockHeaderWithScope(latestHeaderScope)
+		if err != nil {
+			return fmt.Errorf("failed to query new L1 state: %w", err)
+		} else if header != nil {
+			latestHeader = &header.BlockHeader
+		}
+	} else {
+		header, err := ep.db.Blocks.L2BlockHeaderWithScope(latestHeaderScope)
+		if err != nil {
+			return fmt.Errorf("failed to query new L2 state: %w", err)
+		} else if header != nil {
+			latestHeader = &header.BlockHeader
+		}
+	}
+	if latestHeader == nil {
+		stage.log.Debug("no new state found for handler")
+		return nil
+	}
+
+	// a panicking handler fails its own run only
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("handler panicked: %v", r)
+		}
+	}()
+	fromHeight, toHeight := new(big.Int).Add(lastBlockNumber, bigint.One), latestHeader.Number
+	handled := 0
+	if err := ep.db.Transaction(func(tx *database.DB) error {
+		if err := lockHandlerHeaders(tx, stage.Chain, stage.latest, latestHeader); err != nil {
+			return err
+		}
+		if err := tx.SyncCursors.StoreSyncCursor(stage.cursor, stage.Chain, latestHeader.Number, latestHeader.Hash, latestHeader.Timestamp); err != nil {
+			return err
+		}
+		events, err := tx.ContractEvents.ContractEventsWithFilters(stage.filters, stage.Chain, fromHeight, toHeight)
+		if err != nil {
+			return err
+		}
+		handlerLog := stage.log.New("from_block_number", fromHeight, "to_block_number", toHeight)
+		for i := range events {
+			if err := stage.Handler.HandleEvent(handlerLog, tx, events[i]); err != nil {
+				return fmt.Errorf("failed to handle event %s of tx %s: %w", events[i].GUID, events[i].TransactionHash, err)
+			}
+		}
+		handled = len(events)
+		return nil
+	}); err != nil {
+		return ep.rewindOnReorg(err, func() (err error) {
+			stage.latest, err = handlerStageCursor(ep.db, stage)
+			return err
+		})
+	}
+	if handled > 0 {
+		stage.log.Info("handled events", "from_block_number", fromHeight, "to_block_number", toHeight, "size", handled)
+	}
+	stage.latest = latestHeader
+	ep.handlerMetrics.RecordEvents(stage.Handler.Name(), handled)
+	ep.handlerMetrics.RecordLatestHeight(stage.Handler.Name(), latestHeader.Number)
+	return nil
+}
+
+// checkHandlerExtraction refuses to start a new handler from the starting height when its events were only extracted
+// above it, those of the blocks indexed before the handler was registered being missing until they are reindexed
+func checkHandlerExtraction(chain string, start *big.Int, extraction *common2.SyncCursor) error {
+	if extraction == nil {
+		return errors.New("events of the handler are not extracted yet")
+	} else if extraction.BlockNumber.Cmp(start) > 0 {
+		return fmt.Errorf("events of the handler are only extracted above block %s, reindex the %s blocks %s to %s to start it",
+			extraction.BlockNumber, chain, new(big.Int).Add(start, bigint.One), extraction.BlockNumber)
+	}
+	return nil
+}
+
+func lockHandlerHeaders(tx *database.DB, chain string, headers ...*common2.BlockHeader) error {
+	for _, header := range headers {
+		if header == nil {
+			continue
+		}
+		var err error
+		if chain == common2.SyncCursorLayerL1 {
+			err = lockL1Headers(tx, &common2.L1BlockHeader{BlockHeader: *header})
+		} else {
+			err = lockL2Headers(tx, &common2.L2BlockHeader{BlockHeader: *header})
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
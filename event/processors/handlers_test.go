@@ -0,0 +1,27 @@
+package processors
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	common2 "github.com/mantlenetworkio/lithosphere/database/common"
+)
+
+func TestCheckHandlerExtraction(t *testing.T) {
+	start := big.NewInt(100)
+	extraction := func(number int64) *common2.SyncCursor {
+		return &common2.SyncCursor{Name: "extraction_handler_test", Layer: common2.SyncCursorLayerL1, BlockNumber: big.NewInt(number)}
+	}
+
+	// registered on an empty database, or once the blocks indexed beforehand were reindexed
+	require.NoError(t, checkHandlerExtraction(common2.SyncCursorLayerL1, start, extraction(100)))
+	require.NoError(t, checkHandlerExtraction(common2.SyncCursorLayerL1, start, extraction(0)))
+
+	// registered on an indexed database
+	err := checkHandlerExtraction(common2.SyncCursorLayerL1, start, extraction(500))
+	require.ErrorContains(t, err, "reindex the l1 blocks 101 to 500")
+
+	require.Error(t, checkHandlerExtraction(common2.SyncCursorLayerL1, start, nil))
+}
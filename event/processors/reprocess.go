@@ -0,0 +1,210 @@
+package processors
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"gorm.io/gorm"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/mantlenetworkio/lithosphere/common/bigint"
+	"github.com/mantlenetworkio/lithosphere/database"
+	common2 "github.com/mantlenetworkio/lithosphere/database/common"
+)
+
+// ReprocessedTables are the tables rebuilt by Reprocess, derived solely from the indexed contract events
+var ReprocessedTables = []string{
+	"l1_to_l2", "l2_to_l1", "withdraw_proven", "withdraw_finalized", "relay_message", "state_root", "erc721_bridge",
+	"system_config_update", "fee_vault_withdrawal", "token_pair", "relay_attempt", "bridge_status_history", "withdrawal_incident",
+}
+
+// reprocessStage is a stage replayed by Reprocess. Its progress is checkpointed in a cursor of its own,
+// the stage cursor used by the indexer is only moved once every stage has completed.
+type reprocessStage struct {
+	cursor  string
+	layer   string
+	log     log.Logger
+	process func(log log.Logger, tx *database.DB, from, to *big.Int) error
+}
+
+func reprocessCursor(stageCursor string) string {
+	return "reprocess_" + stageCursor
+}
+
+// reprocessStages lists the stages writing to the reprocessed tables. They are replayed one after the
+// other over the whole indexed range, so that the rows a stage updates have all been inserted beforehand.
+func (ep *EventProcessor) reprocessStages() []reprocessStage {
+	return []reprocessStage{
+		{common2.L1BridgeInitiatedCursor, common2.SyncCursorLayerL1, ep.log.New("bridge", "l1", "kind", "initiated"), ep.l1InitiatedEvents},
+		{common2.L2BridgeInitiatedCursor, common2.SyncCursorLayerL2, ep.log.New("bridge", "l2", "kind", "initiated"), ep.l2InitiatedEvents},
+		{common2.L1WithdrawProvenCursor, common2.SyncCursorLayerL1, ep.log.New("bridge", "l1", "kind", "proven"), ep.l1ProvenEvents},
+		{common2.L1WithdrawFinalizedCursor, common2.SyncCursorLayerL1, ep.log.New("bridge", "l1", "kind", "finalization"), ep.l1FinalizedEvents},
+		{common2.L2BridgeFinalizedCursor, common2.SyncCursorLayerL2, ep.log.New("bridge", "l2", "kind", "finalization"), ep.l2FinalizedEvents},
+		{common2.L1StateRootCursor, common2.SyncCursorLayerL1, ep.log.New("rollup", "l1", "kind", "state root"), ep.stateRootEvents},
+		{common2.L1SystemConfigCursor, common2.SyncCursorLayerL1, ep.log.New("rollup", "l1", "kind", "system config"), ep.systemConfigEvents},
+	}
+}
+
+// Reprocess truncates the ReprocessedTables and rebuilds them from the contract events already indexed,
+// replaying the stages up to the latest indexed headers without any RPC traffic. Progress is checkpointed
+// after every batch of headers and an interrupted run resumes where it stopped, unless restart is set.
+// The indexer must be stopped while reprocessing.
+func (ep *EventProcessor) Reprocess(ctx context.Context, restart bool) error {
+	stages := ep.reprocessStages()
+	if restart {
+		for _, stage := range stages {
+			if err := ep.db.SyncCursors.DeleteSyncCursor(reprocessCursor(stage.cursor)); err != nil {
+				return err
+			}
+		}
+	}
+
+	cursors := make([]*common2.SyncCursor, len(stages))
+	for i, stage := range stages {
+		cursor, err := ep.db.SyncCursors.SyncCursor(reprocessCursor(stage.cursor))
+		if err != nil {
+			return err
+		}
+		cursors[i] = cursor
+	}
+	truncate, starts := ep.reprocessPlan(stages, cursors)
+	if truncate {
+		ep.log.Info("reprocessing from the starting heights, truncating tables", "tables", ReprocessedTables)
+	} else {
+		ep.log.Info("resuming interrupted reprocess")
+	}
+
+	l1Target, err := ep.db.Blocks.L1LatestBlockHeader()
+	if err != nil {
+		return fmt.Errorf("failed to query latest L1 header: %w", err)
+	}
+	l2Target, err := ep.db.Blocks.L2LatestBlockHeader()
+	if err != nil {
+		return fmt.Errorf("failed to query latest L2 header: %w", err)
+	}
+	targets := map[string]*common2.BlockHeader{}
+	if l1Target != nil {
+		targets[common2.SyncCursorLayerL1] = &l1Target.BlockHeader
+	}
+	if l2Target != nil {
+		targets[common2.SyncCursorLayerL2] = &l2Target.BlockHeader
+	}
+
+	for i, stage := range stages {
+		target := targets[stage.layer]
+		if target == nil {
+			stage.log.Warn("no indexed headers, skipping stage")
+			continue
+		}
+		start := ep.startingHeight(stage.layer)
+		last := starts[i]
+
+		for last.Cmp(target.Number) < 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			end, err := ep.reprocessBatchEnd(stage.layer, last, target.Number)
+			if err != nil {
+				return err
+			} else if end == nil {
+				break
+			}
+			fromHeight, toHeight := new(big.Int).Add(last, bigint.One), end.Number
+			if err := ep.db.Transaction(func(tx *database.DB) error {
+				if truncate {
+					if err := tx.TruncateTables(ReprocessedTables...); err != nil {
+						return err
+					}
+				}
+				if err := stage.process(stage.log, tx, fromHeight, toHeight); err != nil {
+					return err
+				}
+				return tx.SyncCursors.StoreSyncCursor(reprocessCursor(stage.cursor), stage.layer, end.Number, end.Hash, end.Timestamp)
+			}); err != nil {
+				return fmt.Errorf("failed to reprocess %s [%s, %s]: %w", stage.cursor, fromHeight, toHeight, err)
+			}
+			truncate = false
+			last = end.Number
+			stage.log.Info("reprocessed batch", "to_block_number", toHeight, "target_block_number", target.Number, "progress", reprocessProgress(start, last, target.Number))
+		}
+		stage.log.Info("stage reprocessed")
+	}
+
+	// the stages resume from the rebuilt state once the indexer is restarted
+	return ep.db.Transaction(func(tx *database.DB) error {
+		if truncate {
+			if err := tx.TruncateTables(ReprocessedTables...); err != nil {
+				return err
+			}
+		}
+		for _, stage := range stages {
+			if target := targets[stage.layer]; target != nil {
+				if err := tx.SyncCursors.StoreSyncCursor(stage.cursor, stage.layer, target.Number, target.Hash, target.Timestamp); err != nil {
+					return err
+				}
+			}
+			if err := tx.SyncCursors.DeleteSyncCursor(reprocessCursor(stage.cursor)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// reprocessPlan returns whether the ReprocessedTables have to be truncated, along with the height each stage
+// resumes after. A run is only resumed once a stage has checkpointed its progress, before that nothing
+// has been rebuilt yet and every stage starts over from the starting height of its layer.
+func (ep *EventProcessor) reprocessPlan(stages []reprocessStage, cursors []*common2.SyncCursor) (bool, []*big.Int) {
+	truncate := true
+	starts := make([]*big.Int, len(stages))
+	for i, stage := range stages {
+		starts[i] = ep.startingHeight(stage.layer)
+		if cursors[i] != nil {
+			truncate = false
+			starts[i] = cursors[i].BlockNumber
+		}
+	}
+	return truncate, starts
+}
+
+func (ep *EventProcessor) startingHeight(layer string) *big.Int {
+	if layer == common2.SyncCursorLayerL2 {
+		return big.NewInt(int64(ep.chainConfig.L2StartingHeight))
+	}
+	return big.NewInt(int64(ep.chainConfig.L1StartingHeight))
+}
+
+// reprocessBatchEnd returns the header closing the next batch of at most blocksLimit indexed headers above last
+func (ep *EventProcessor) reprocessBatchEnd(layer string, last, target *big.Int) (*common2.BlockHeader, error) {
+	batchScope := func(model any) func(db *gorm.DB) *gorm.DB {
+		return func(db *gorm.DB) *gorm.DB {
+			newQuery := db.Session(&gorm.Session{NewDB: true})
+			headers := newQuery.Model(model).Where("number > ? AND number <= ?", last, target)
+			return db.Where("number = (?)", newQuery.Table("(?) as block_numbers", headers.Order("number ASC").Limit(blocksLimit)).Select("MAX(number)"))
+		}
+	}
+	if layer == common2.SyncCursorLayerL1 {
+		header, err := ep.db.Blocks.L1BlockHeaderWithScope(batchScope(common2.L1BlockHeader{}))
+		if err != nil || header == nil {
+			return nil, err
+		}
+		return &header.BlockHeader, nil
+	}
+	header, err := ep.db.Blocks.L2BlockHeaderWithScope(batchScope(common2.L2BlockHeader{}))
+	if err != nil || header == nil {
+		return nil, err
+	}
+	return &header.BlockHeader, nil
+}
+
+func reprocessProgress(start, current, target *big.Int) string {
+	total := new(big.Int).Sub(target, start)
+	if total.Sign() <= 0 {
+		return "100.00%"
+	}
+	done := new(big.Float).Quo(new(big.Float).SetInt(new(big.Int).Sub(current, start)), new(big.Float).SetInt(total))
+	percent, _ := done.Float64()
+	return fmt.Sprintf("%.2f%%", percent*100)
+}
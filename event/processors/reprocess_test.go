@@ -0,0 +1,47 @@
+package processors
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/mantlenetworkio/lithosphere/config"
+	common2 "github.com/mantlenetworkio/lithosphere/database/common"
+)
+
+func TestReprocessPlan(t *testing.T) {
+	ep := &EventProcessor{log: log.New(), chainConfig: config.ChainConfig{L1StartingHeight: 100, L2StartingHeight: 200}}
+	stages := ep.reprocessStages()
+	cursors := make([]*common2.SyncCursor, len(stages))
+
+	// nothing checkpointed, every stage starts over from its layer's starting height on truncated tables
+	truncate, starts := ep.reprocessPlan(stages, cursors)
+	require.True(t, truncate)
+	for i, stage := range stages {
+		if stage.layer == common2.SyncCursorLayerL1 {
+			require.Equal(t, big.NewInt(100), starts[i], stage.cursor)
+		} else {
+			require.Equal(t, big.NewInt(200), starts[i], stage.cursor)
+		}
+	}
+
+	// an interrupted run resumes the checkpointed stages, the later ones haven't started yet
+	cursors[0] = &common2.SyncCursor{Name: reprocessCursor(stages[0].cursor), Layer: stages[0].layer, BlockNumber: big.NewInt(150)}
+	cursors[1] = &common2.SyncCursor{Name: reprocessCursor(stages[1].cursor), Layer: stages[1].layer, BlockNumber: big.NewInt(250)}
+	truncate, starts = ep.reprocessPlan(stages, cursors)
+	require.False(t, truncate)
+	require.Equal(t, big.NewInt(150), starts[0])
+	require.Equal(t, big.NewInt(250), starts[1])
+	for i := 2; i < len(stages); i++ {
+		require.Equal(t, ep.startingHeight(stages[i].layer), starts[i], stages[i].cursor)
+	}
+}
+
+func TestReprocessedTablesIncludeDerivedState(t *testing.T) {
+	// the status history and incidents refer to the rebuilt bridge rows and must be rebuilt along with them
+	require.Contains(t, ReprocessedTables, "bridge_status_history")
+	require.Contains(t, ReprocessedTables, "withdrawal_incident")
+}
@@ -1,6 +1,7 @@
 package stateroot
 
 import (
+	"fmt"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/log"
@@ -38,5 +39,27 @@ func L2OutputEvent(log log.Logger, db *database.DB, metrics bridge.L1Metricer, l
 			return err
 		}
 	}
+
+	// the withdrawals proven against the deleted outputs are invalidated by the business processor
+	outputsDeletedEvents, err := contracts.L2OutputsDeletedEvents(l1Contracts.L2OutputOracleProxy, db, fromHeight, toHeight)
+	if err != nil {
+		return err
+	}
+	for i := range outputsDeletedEvents {
+		deleted := outputsDeletedEvents[i]
+		l1BlockNumber, err := db.L1ToL2.GetBlockNumberFromHash(deleted.Event.BlockHash)
+		if err != nil {
+			return err
+		} else if l1BlockNumber == nil {
+			return fmt.Errorf("missing header of deleted outputs. tx_hash = %s", deleted.Event.TransactionHash.String())
+		}
+		size, err := db.StateRoots.MarkStateRootsDeleted(deleted.NewNextOutputIndex, deleted.PrevNextOutputIndex, l1BlockNumber)
+		if err != nil {
+			return err
+		}
+		log.Warn("detected l2 outputs deleted", "new_next_output_index", deleted.NewNextOutputIndex, "prev_next_output_index", deleted.PrevNextOutputIndex,
+			"size", size, "tx_hash", deleted.Event.TransactionHash.String())
+		metrics.RecordL1DeletedOutputs(int(size))
+	}
 	return nil
 }
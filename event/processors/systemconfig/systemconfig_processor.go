@@ -0,0 +1,26 @@
+package systemconfig
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/mantlenetworkio/lithosphere/config"
+	"github.com/mantlenetworkio/lithosphere/database"
+	"github.com/mantlenetworkio/lithosphere/event/processors/contracts"
+)
+
+func L1ProcessSystemConfigEvents(log log.Logger, db *database.DB, l1Contracts config.L1Contracts, fromHeight, toHeight *big.Int) error {
+	configUpdates, err := contracts.SystemConfigUpdateEvents(l1Contracts.SystemConfigProxy, db, fromHeight, toHeight)
+	if err != nil {
+		return err
+	}
+	if len(configUpdates) > 0 {
+		log.Info("detected system config update event", "size", len(configUpdates))
+		if err := db.SystemConfig.StoreSystemConfigUpdates(configUpdates); err != nil {
+			log.Error("Store system config updates fail")
+			return err
+		}
+	}
+	return nil
+}
@@ -20,18 +20,30 @@ var (
 		Usage:   "path to migrations folder",
 		EnvVars: prefixEnvVars("MIGRATIONS_DIR"),
 	}
-	L1EthRpcFlag = &cli.StringFlag{
+	L1EthRpcFlag = &cli.StringSliceFlag{
 		Name:     "l1-eth-rpc",
-		Usage:    "HTTP provider URL for L1",
+		Usage:    "Comma separated provider URLs for L1, calls fail over between them",
 		EnvVars:  prefixEnvVars("L1_RPC"),
 		Required: true,
 	}
-	L2EthRpcFlag = &cli.StringFlag{
+	L2EthRpcFlag = &cli.StringSliceFlag{
 		Name:     "l2-eth-rpc",
-		Usage:    "HTTP provider URL for L2",
+		Usage:    "Comma separated provider URLs for L2, calls fail over between them",
 		EnvVars:  prefixEnvVars("L2_PRC"),
 		Required: true,
 	}
+	L1RpcQuorumFlag = &cli.UintFlag{
+		Name:    "l1-rpc-quorum",
+		Usage:   "Number of L1 providers that must agree on the head hash, 0 or 1 disables the check",
+		EnvVars: prefixEnvVars("L1_RPC_QUORUM"),
+		Value:   0,
+	}
+	L2RpcQuorumFlag = &cli.UintFlag{
+		Name:    "l2-rpc-quorum",
+		Usage:   "Number of L2 providers that must agree on the head hash, 0 or 1 disables the check",
+		EnvVars: prefixEnvVars("L2_RPC_QUORUM"),
+		Value:   0,
+	}
 	HttpHostFlag = &cli.StringFlag{
 		Name:     "http-host",
 		Usage:    "The host of the api",
@@ -119,6 +131,18 @@ var (
 		EnvVars: prefixEnvVars("L1_CONFIRMATION_DEPTH"),
 		Value:   0,
 	}
+	L1TraversalModeFlag = &cli.StringFlag{
+		Name:    "l1-traversal-mode",
+		Usage:   "The head l1 is indexed up to: latest (minus the confirmation depth), safe or finalized",
+		EnvVars: prefixEnvVars("L1_TRAVERSAL_MODE"),
+		Value:   "finalized",
+	}
+	L1SubscribeNewHeadsFlag = &cli.BoolFlag{
+		Name:    "l1-subscribe-new-heads",
+		Usage:   "Subscribe to l1 newHeads to pick up blocks as they arrive, requires a websocket l1 rpc",
+		EnvVars: prefixEnvVars("L1_SUBSCRIBE_NEW_HEADS"),
+		Value:   false,
+	}
 	L1StartingHeightFlag = &cli.IntFlag{
 		Name:    "l1-starting-height",
 		Usage:   "The starting height of l1",
@@ -221,6 +245,18 @@ var (
 		EnvVars: prefixEnvVars("L2_CONFIRMATION_DEPTH"),
 		Value:   0,
 	}
+	L2TraversalModeFlag = &cli.StringFlag{
+		Name:    "l2-traversal-mode",
+		Usage:   "The head l2 is indexed up to: latest (minus the confirmation depth), safe or finalized",
+		EnvVars: prefixEnvVars("L2_TRAVERSAL_MODE"),
+		Value:   "latest",
+	}
+	L2SubscribeNewHeadsFlag = &cli.BoolFlag{
+		Name:    "l2-subscribe-new-heads",
+		Usage:   "Subscribe to l2 newHeads to pick up blocks as they arrive, requires a websocket l2 rpc",
+		EnvVars: prefixEnvVars("L2_SUBSCRIBE_NEW_HEADS"),
+		Value:   false,
+	}
 	RetrieverSocketFlag = &cli.StringFlag{
 		Name:    "retriever-socket",
 		Usage:   "Websocket for MantleDA disperser",
@@ -231,12 +267,6 @@ var (
 		Usage:   "Retriever timeout",
 		EnvVars: prefixEnvVars("RETRIEVER_TIMEOUT"),
 	}
-	FraudProofWindowsFlags = &cli.Uint64Flag{
-		Name:    "fraud-proof-windows",
-		Usage:   "The fraud proof windows",
-		Value:   1800,
-		EnvVars: prefixEnvVars("FRAUD_PROOF_WINDOWS"),
-	}
 	DataStorePollingDurationFlag = &cli.DurationFlag{
 		Name:    "data-store-polling-duration",
 		Usage:   "Duration to store blob",
@@ -404,9 +434,21 @@ var (
 		Value:   "",
 		EnvVars: prefixEnvVars("WITHDRAW_BIG_VALUE_ADDRESS"),
 	}
+	WithdrawalMonitorGracePeriodFlag = &cli.DurationFlag{
+		Name:    "withdrawal-monitor-grace-period",
+		Usage:   "How far the L2 withdrawals must be indexed past a proof or finalization on L1 before its missing initiation is reported as an incident",
+		Value:   time.Hour,
+		EnvVars: prefixEnvVars("WITHDRAWAL_MONITOR_GRACE_PERIOD"),
+	}
+	WithdrawalMonitorProposalLagFlag = &cli.DurationFlag{
+		Name:    "withdrawal-monitor-proposal-lag",
+		Usage:   "The longest delay between an L2 block and the output proposal covering it, the L1 proofs this soon after the first indexed L2 block may prove withdrawals initiated before it",
+		Value:   2 * time.Hour,
+		EnvVars: prefixEnvVars("WITHDRAWAL_MONITOR_PROPOSAL_LAG"),
+	}
 	TokenListUrlFlag = &cli.StringFlag{
 		Name:    "token-list-url",
-		Usage:   "The url of token list.",
+		Usage:   "The url of token list. Its tokens override the metadata read on-chain for the OptimismMintableERC20Factory token pairs.",
 		Value:   "",
 		EnvVars: prefixEnvVars("TOKEN_LIST_URL"),
 	}
@@ -430,14 +472,17 @@ var requiredFlags = []cli.Flag{
 }
 
 var optionalFlags = []cli.Flag{
+	L1RpcQuorumFlag,
+	L2RpcQuorumFlag,
 	L1PollingIntervalFlag,
 	L1HeaderBufferSizeFlag,
 	L1ConfirmationDepthFlag,
+	L1TraversalModeFlag,
+	L1SubscribeNewHeadsFlag,
 	L1StartingHeightFlag,
 	L2StartingHeightFlag,
 	L1BedrockStartingHeightFlag,
 	L2BedrockStartingHeightFlag,
-	FraudProofWindowsFlags,
 	AddressManagerFlag,
 	SystemConfigProxyFlag,
 	OptimismPortalProxyFlag,
@@ -450,6 +495,8 @@ var optionalFlags = []cli.Flag{
 	LegacyStateCommitmentChainFlag,
 	L2PollingIntervalFlag,
 	L2ConfirmationDepthFlag,
+	L2TraversalModeFlag,
+	L2SubscribeNewHeadsFlag,
 	L2HeaderBufferSizeFlag,
 	RetrieverTimeoutFlag,
 	RetrieverSocketFlag,
@@ -483,6 +530,8 @@ var optionalFlags = []cli.Flag{
 	TransferBigValueInMantleFlag,
 	WithdrawBigValueAddressFlag,
 	TokenListUrlFlag,
+	WithdrawalMonitorGracePeriodFlag,
+	WithdrawalMonitorProposalLagFlag,
 }
 
 func init() {
@@ -21,6 +21,7 @@ import (
 	"github.com/mantlenetworkio/lithosphere/business/mantle_da"
 	"github.com/mantlenetworkio/lithosphere/config"
 	"github.com/mantlenetworkio/lithosphere/database"
+	"github.com/mantlenetworkio/lithosphere/event/handlers"
 	"github.com/mantlenetworkio/lithosphere/event/processors"
 	"github.com/mantlenetworkio/lithosphere/event/processors/bridge"
 	"github.com/mantlenetworkio/lithosphere/event/processors/bridge/ovm1"
@@ -186,13 +187,13 @@ func (i *Lithosphere) initFromConfig(ctx context.Context, cfg *config.Config) er
 }
 
 func (i *Lithosphere) initRPCClients(ctx context.Context, rpcsConfig config.RPCsConfig) error {
-	l1EthClient, err := node.DialEthClient(ctx, rpcsConfig.L1RPC, metrics2.NewNodeMetrics(i.metricsRegistry, "l1"))
+	l1EthClient, err := node.DialEthClientPool(ctx, i.log.New("rpc", "l1"), rpcsConfig.L1RPCs, rpcsConfig.L1Quorum, metrics2.NewNodeMetrics(i.metricsRegistry, "l1"))
 	if err != nil {
 		return fmt.Errorf("failed to dial L1 client: %w", err)
 	}
 	i.l1Client = l1EthClient
 
-	l2EthClient, err := node.DialEthClient(ctx, rpcsConfig.L2RPC, metrics2.NewNodeMetrics(i.metricsRegistry, "l2"))
+	l2EthClient, err := node.DialEthClientPool(ctx, i.log.New("rpc", "l2"), rpcsConfig.L2RPCs, rpcsConfig.L2Quorum, metrics2.NewNodeMetrics(i.metricsRegistry, "l2"))
 	if err != nil {
 		return fmt.Errorf("failed to dial L2 client: %w", err)
 	}
@@ -210,11 +211,19 @@ func (i *Lithosphere) initDB(ctx context.Context, cfg config.DBConfig) error {
 }
 
 func (i *Lithosphere) initL1Syncer(cfg config.Config) error {
+	l1TraversalMode, err := node.ParseTraversalMode(cfg.Chain.L1TraversalMode)
+	if err != nil {
+		return fmt.Errorf("invalid L1 traversal mode: %w", err)
+	}
 	l1Cfg := synchronizer.Config{
 		LoopIntervalMsec:  cfg.Chain.L1PollingInterval,
 		HeaderBufferSize:  cfg.Chain.L1HeaderBufferSize,
 		ConfirmationDepth: big.NewInt(int64(cfg.Chain.L1ConfirmationDepth)),
 		StartHeight:       big.NewInt(int64(cfg.Chain.L1StartingHeight)),
+		TraversalMode:     l1TraversalMode,
+		SubscribeNewHeads: cfg.Chain.L1SubscribeNewHeads,
+		ContractEvents:    cfg.Chain.L1ContractEvents,
+		Handlers:          handlers.DefaultRegistry,
 	}
 	l1Sync, err := synchronizer.NewL1Sync(l1Cfg, i.log, i.DB, metrics2.NewMetrics(i.metricsRegistry, "l1"),
 		i.l1Client, cfg.Chain.L1Contracts, i.shutdown, cfg.ExporterConfig.TransferBigValueAddressInEthereum)
@@ -227,11 +236,19 @@ func (i *Lithosphere) initL1Syncer(cfg config.Config) error {
 
 func (i *Lithosphere) initL2ETL(cfg config.Config) error {
 	// L2 (defaults to predeploy contracts)
+	l2TraversalMode, err := node.ParseTraversalMode(cfg.Chain.L2TraversalMode)
+	if err != nil {
+		return fmt.Errorf("invalid L2 traversal mode: %w", err)
+	}
 	l2Cfg := synchronizer.Config{
 		LoopIntervalMsec:  cfg.Chain.L2PollingInterval,
 		HeaderBufferSize:  cfg.Chain.L2HeaderBufferSize,
 		ConfirmationDepth: big.NewInt(int64(cfg.Chain.L2ConfirmationDepth)),
 		StartHeight:       big.NewInt(int64(cfg.Chain.L2StartingHeight)),
+		TraversalMode:     l2TraversalMode,
+		SubscribeNewHeads: cfg.Chain.L2SubscribeNewHeads,
+		ContractEvents:    cfg.Chain.L2ContractEvents,
+		Handlers:          handlers.DefaultRegistry,
 	}
 	l2Sync, err := synchronizer.NewL2Sync(l2Cfg, i.log, i.DB, metrics2.NewMetrics(i.metricsRegistry, "l2"),
 		i.l2Client, cfg.Chain.L2Contracts, i.shutdown, cfg.ExporterConfig.TransferBigValueAddressInMantle)
@@ -244,7 +261,8 @@ func (i *Lithosphere) initL2ETL(cfg config.Config) error {
 
 func (i *Lithosphere) initBridgeProcessor(chainConfig config.ChainConfig) error {
 	bridgeProcessor, err := processors.NewBridgeProcessor(
-		i.log, i.DB, bridge.NewMetrics(i.metricsRegistry), i.L1Sync, i.L2Sync, chainConfig, i.shutdown)
+		i.log, i.DB, bridge.NewMetrics(i.metricsRegistry), i.L1Sync, i.L2Sync, chainConfig,
+		handlers.DefaultRegistry, handlers.NewMetrics(i.metricsRegistry), i.shutdown)
 	if err != nil {
 		return err
 	}
@@ -259,7 +277,7 @@ func (i *Lithosphere) initBusinessProcessor(cfg config.Config) error {
 		return err
 	}
 	businessProcessor := business.NewBusinessProcessor(
-		i.log, i.DB, i.l1Client, i.l2Client, mantleDA, cfg, i.shutdown)
+		i.log, i.DB, business.NewMetrics(i.metricsRegistry), i.l1Client, i.l2Client, mantleDA, cfg, i.BridgeProcessor, i.shutdown)
 
 	i.BusinessProcessor = businessProcessor
 	return nil
@@ -2,6 +2,7 @@ package metrics
 
 import (
 	"math/big"
+	"strconv"
 
 	"github.com/prometheus/client_golang/prometheus"
 
@@ -15,8 +16,13 @@ var (
 type Metricer interface {
 	RecordInterval() (done func(err error))
 
+	// Head Tracking
+	RecordHeadTrackingMode(mode string)
+	RecordHeadTrigger(mode string)
+
 	// Batch Extraction
 	RecordBatchLatestHeight(height *big.Int)
+	RecordBatchTraversalHead(mode string, height *big.Int)
 	RecordBatchHeaders(size int)
 	RecordBatchLog(contractAddress common.Address)
 
@@ -24,20 +30,32 @@ type Metricer interface {
 	RecordIndexedLatestHeight(height *big.Int)
 	RecordIndexedHeaders(size int)
 	RecordIndexedLogs(size int)
+
+	// Reorgs
+	RecordReorg(depth uint64)
+	RecordRejectedTransition(bridge string, from, to int64)
 }
 
 type etlMetrics struct {
 	intervalTick     prometheus.Counter
 	intervalDuration prometheus.Histogram
 
+	headTrackingMode *prometheus.GaugeVec
+	headTriggers     *prometheus.CounterVec
+
 	batchFailures     prometheus.Counter
 	batchLatestHeight prometheus.Gauge
+	batchTraversal    *prometheus.GaugeVec
 	batchHeaders      prometheus.Counter
 	batchLogs         *prometheus.CounterVec
 
 	indexedLatestHeight prometheus.Gauge
 	indexedHeaders      prometheus.Counter
 	indexedLogs         prometheus.Counter
+
+	reorgs              prometheus.Counter
+	reorgDepth          prometheus.Histogram
+	rejectedTransitions *prometheus.CounterVec
 }
 
 func NewMetrics(registry *prometheus.Registry, subsystem string) Metricer {
@@ -55,6 +73,22 @@ func NewMetrics(registry *prometheus.Registry, subsystem string) Metricer {
 			Name:      "interval_seconds",
 			Help:      "duration elapsed for during the processing loop",
 		}),
+		headTrackingMode: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Subsystem: subsystem,
+			Name:      "head_tracking_mode",
+			Help:      "set to 1 for the mode currently used to track new heads (polling or subscription)",
+		}, []string{
+			"mode",
+		}),
+		headTriggers: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Subsystem: subsystem,
+			Name:      "head_triggers_total",
+			Help:      "number of extraction loop runs triggered by each head tracking mode",
+		}, []string{
+			"mode",
+		}),
 		batchFailures: factory.NewCounter(prometheus.CounterOpts{
 			Namespace: MetricsNamespace,
 			Subsystem: subsystem,
@@ -67,6 +101,14 @@ func NewMetrics(registry *prometheus.Registry, subsystem string) Metricer {
 			Name:      "height",
 			Help:      "the latest block height observed by an etl interval",
 		}),
+		batchTraversal: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Subsystem: subsystem,
+			Name:      "traversal_head_height",
+			Help:      "the head block height of the traversal mode followed by the etl",
+		}, []string{
+			"mode",
+		}),
 		batchHeaders: factory.NewCounter(prometheus.CounterOpts{
 			Namespace: MetricsNamespace,
 			Subsystem: subsystem,
@@ -99,6 +141,29 @@ func NewMetrics(registry *prometheus.Registry, subsystem string) Metricer {
 			Name:      "indexed_logs_total",
 			Help:      "number of logs indexed by the etl",
 		}),
+		reorgs: factory.NewCounter(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Subsystem: subsystem,
+			Name:      "reorgs_total",
+			Help:      "number of reorgs rolled back by the etl",
+		}),
+		reorgDepth: factory.NewHistogram(prometheus.HistogramOpts{
+			Namespace: MetricsNamespace,
+			Subsystem: subsystem,
+			Name:      "reorg_depth",
+			Buckets:   []float64{1, 2, 4, 8, 16, 32, 64, 128},
+			Help:      "number of traversed blocks rolled back per reorg",
+		}),
+		rejectedTransitions: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: MetricsNamespace,
+			Subsystem: subsystem,
+			Name:      "rejected_bridge_status_transitions_total",
+			Help:      "number of deposit and withdrawal status transitions rolled back by the etl not allowed by their lifecycle",
+		}, []string{
+			"bridge",
+			"from",
+			"to",
+		}),
 	}
 }
 
@@ -114,10 +179,23 @@ func (m *etlMetrics) RecordInterval() func(error) {
 	}
 }
 
+func (m *etlMetrics) RecordHeadTrackingMode(mode string) {
+	m.headTrackingMode.Reset()
+	m.headTrackingMode.WithLabelValues(mode).Set(1)
+}
+
+func (m *etlMetrics) RecordHeadTrigger(mode string) {
+	m.headTriggers.WithLabelValues(mode).Inc()
+}
+
 func (m *etlMetrics) RecordBatchLatestHeight(height *big.Int) {
 	m.batchLatestHeight.Set(float64(height.Uint64()))
 }
 
+func (m *etlMetrics) RecordBatchTraversalHead(mode string, height *big.Int) {
+	m.batchTraversal.WithLabelValues(mode).Set(float64(height.Uint64()))
+}
+
 func (m *etlMetrics) RecordBatchHeaders(size int) {
 	m.batchHeaders.Add(float64(size))
 }
@@ -137,3 +215,12 @@ func (m *etlMetrics) RecordIndexedHeaders(size int) {
 func (m *etlMetrics) RecordIndexedLogs(size int) {
 	m.indexedLogs.Add(float64(size))
 }
+
+func (m *etlMetrics) RecordReorg(depth uint64) {
+	m.reorgs.Inc()
+	m.reorgDepth.Observe(float64(depth))
+}
+
+func (m *etlMetrics) RecordRejectedTransition(bridge string, from, to int64) {
+	m.rejectedTransitions.WithLabelValues(bridge, strconv.FormatInt(from, 10), strconv.FormatInt(to, 10)).Inc()
+}
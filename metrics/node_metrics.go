@@ -18,12 +18,16 @@ var (
 type NodeMetricer interface {
 	RecordRPCClientRequest(method string) func(err error)
 	RecordRPCClientBatchRequest(b []rpc.BatchElem) func(err error)
+	RecordEndpointHealth(endpoint string, healthy bool, headLag uint64)
 }
 
 type clientMetrics struct {
 	rpcClientRequestsTotal          *prometheus.CounterVec
 	rpcClientRequestDurationSeconds *prometheus.HistogramVec
 	rpcClientResponsesTotal         *prometheus.CounterVec
+
+	endpointHealthy *prometheus.GaugeVec
+	endpointHeadLag *prometheus.GaugeVec
 }
 
 func NewNodeMetrics(registry *prometheus.Registry, subsystem string) NodeMetricer {
@@ -55,6 +59,22 @@ func NewNodeMetrics(registry *prometheus.Registry, subsystem string) NodeMetrice
 			"method",
 			"error",
 		}),
+		endpointHealthy: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: NodeMetricsNamespace,
+			Subsystem: subsystem,
+			Name:      "endpoint_healthy",
+			Help:      "set to 1 while the RPC endpoint is considered healthy by the provider pool",
+		}, []string{
+			"endpoint",
+		}),
+		endpointHeadLag: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: NodeMetricsNamespace,
+			Subsystem: subsystem,
+			Name:      "endpoint_head_lag",
+			Help:      "number of blocks the RPC endpoint trails the best head known to the provider pool",
+		}, []string{
+			"endpoint",
+		}),
 	}
 }
 
@@ -85,6 +105,15 @@ func (m *clientMetrics) RecordRPCClientBatchRequest(b []rpc.BatchElem) func(err
 	}
 }
 
+func (m *clientMetrics) RecordEndpointHealth(endpoint string, healthy bool, headLag uint64) {
+	if healthy {
+		m.endpointHealthy.WithLabelValues(endpoint).Set(1)
+	} else {
+		m.endpointHealthy.WithLabelValues(endpoint).Set(0)
+	}
+	m.endpointHeadLag.WithLabelValues(endpoint).Set(float64(headLag))
+}
+
 func (m *clientMetrics) recordRPCClientResponse(method string, err error) {
 	var errStr string
 	var rpcErr rpc.Error
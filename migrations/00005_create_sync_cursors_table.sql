@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS sync_cursors (
+    name         VARCHAR PRIMARY KEY,
+    layer        VARCHAR NOT NULL,
+    block_number UINT256 NOT NULL,
+    block_hash   VARCHAR NOT NULL,
+    updated_at   INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS sync_cursors_layer ON sync_cursors(layer);
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS erc721_bridge (
+    guid                    VARCHAR PRIMARY KEY,
+    direction               VARCHAR NOT NULL,
+    message_hash            VARCHAR NOT NULL,
+    withdrawal_hash         VARCHAR,
+    l1_block_number         UINT256,
+    l2_block_number         UINT256,
+    l1_transaction_hash     VARCHAR,
+    l2_transaction_hash     VARCHAR,
+    l1_prove_block_number   UINT256,
+    l1_prove_tx_hash        VARCHAR,
+    status                  SMALLINT NOT NULL,
+    from_address            VARCHAR NOT NULL,
+    to_address              VARCHAR NOT NULL,
+    l1_token_address        VARCHAR NOT NULL,
+    l2_token_address        VARCHAR NOT NULL,
+    token_id                UINT256 NOT NULL,
+    timestamp               INTEGER NOT NULL CHECK (timestamp > 0)
+);
+CREATE INDEX IF NOT EXISTS erc721_bridge_direction_timestamp ON erc721_bridge(direction, timestamp);
+CREATE INDEX IF NOT EXISTS erc721_bridge_message_hash ON erc721_bridge(message_hash);
+CREATE INDEX IF NOT EXISTS erc721_bridge_withdrawal_hash ON erc721_bridge(withdrawal_hash);
+CREATE INDEX IF NOT EXISTS erc721_bridge_from_address ON erc721_bridge(from_address);
+CREATE INDEX IF NOT EXISTS erc721_bridge_to_address ON erc721_bridge(to_address);
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS system_config_update (
+    guid                    VARCHAR PRIMARY KEY,
+    l1_block_number         UINT256 NOT NULL,
+    transaction_hash        VARCHAR NOT NULL,
+    log_index               INTEGER NOT NULL,
+    version                 UINT256 NOT NULL,
+    update_type             SMALLINT NOT NULL,
+    batcher_hash            VARCHAR,
+    overhead                UINT256,
+    scalar                  UINT256,
+    gas_limit               UINT256,
+    unsafe_block_signer     VARCHAR,
+    timestamp               INTEGER NOT NULL CHECK (timestamp > 0)
+);
+CREATE INDEX IF NOT EXISTS system_config_update_type_block ON system_config_update(update_type, l1_block_number, log_index);
+CREATE INDEX IF NOT EXISTS system_config_update_timestamp ON system_config_update(timestamp);
@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS fee_vault_withdrawal (
+    guid                    VARCHAR PRIMARY KEY,
+    vault_address           VARCHAR NOT NULL,
+    l2_block_number         UINT256 NOT NULL,
+    l2_transaction_hash     VARCHAR NOT NULL,
+    log_index               INTEGER NOT NULL,
+    withdrawal_hash         VARCHAR,
+    value                   UINT256 NOT NULL,
+    from_address            VARCHAR NOT NULL,
+    to_address              VARCHAR NOT NULL,
+    timestamp               INTEGER NOT NULL CHECK (timestamp > 0)
+);
+CREATE INDEX IF NOT EXISTS fee_vault_withdrawal_vault_timestamp ON fee_vault_withdrawal(vault_address, timestamp);
+CREATE INDEX IF NOT EXISTS fee_vault_withdrawal_l2_block_number ON fee_vault_withdrawal(l2_block_number);
+CREATE INDEX IF NOT EXISTS fee_vault_withdrawal_withdrawal_hash ON fee_vault_withdrawal(withdrawal_hash);
+
+CREATE TABLE IF NOT EXISTS fee_vault_balance (
+    vault_address           VARCHAR NOT NULL,
+    l2_block_number         UINT256 NOT NULL,
+    balance                 UINT256 NOT NULL,
+    timestamp               INTEGER NOT NULL CHECK (timestamp > 0),
+    PRIMARY KEY (vault_address, l2_block_number)
+);
+CREATE INDEX IF NOT EXISTS fee_vault_balance_vault_timestamp ON fee_vault_balance(vault_address, timestamp);
+CREATE INDEX IF NOT EXISTS fee_vault_balance_l2_block_number ON fee_vault_balance(l2_block_number);
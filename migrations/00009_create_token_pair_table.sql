@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS token_pair (
+    l2_token_address        VARCHAR PRIMARY KEY,
+    l1_token_address        VARCHAR NOT NULL,
+    deployer                VARCHAR NOT NULL,
+    l2_block_number         UINT256 NOT NULL,
+    l2_transaction_hash     VARCHAR NOT NULL,
+    l1_name                 VARCHAR,
+    l1_symbol               VARCHAR,
+    l1_decimals             SMALLINT,
+    l2_name                 VARCHAR,
+    l2_symbol               VARCHAR,
+    l2_decimals             SMALLINT,
+    metadata_synced         BOOLEAN NOT NULL DEFAULT FALSE,
+    timestamp               INTEGER NOT NULL CHECK (timestamp > 0)
+);
+CREATE INDEX IF NOT EXISTS token_pair_l1_token_address ON token_pair(l1_token_address);
+CREATE INDEX IF NOT EXISTS token_pair_l2_block_number ON token_pair(l2_block_number);
+CREATE INDEX IF NOT EXISTS token_pair_metadata_synced ON token_pair(metadata_synced) WHERE NOT metadata_synced;
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS relay_attempt (
+    guid                    VARCHAR PRIMARY KEY,
+    chain                   VARCHAR NOT NULL,
+    message_hash            VARCHAR NOT NULL,
+    block_number            UINT256 NOT NULL,
+    transaction_hash        VARCHAR NOT NULL,
+    log_index               INTEGER NOT NULL,
+    success                 BOOLEAN NOT NULL,
+    timestamp               INTEGER NOT NULL CHECK (timestamp > 0)
+);
+CREATE INDEX IF NOT EXISTS relay_attempt_chain_message_hash ON relay_attempt(chain, message_hash);
+CREATE INDEX IF NOT EXISTS relay_attempt_chain_block_number ON relay_attempt(chain, block_number);
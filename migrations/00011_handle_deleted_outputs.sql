@@ -0,0 +1,7 @@
+ALTER TABLE state_root ADD COLUMN IF NOT EXISTS deleted_l1_block_number UINT256;
+-- state roots were stored without their canonical flag, no deletion had been indexed yet
+UPDATE state_root SET canonical = TRUE WHERE NOT canonical AND deleted_l1_block_number IS NULL;
+CREATE INDEX IF NOT EXISTS state_root_output_index ON state_root(output_index);
+
+ALTER TABLE withdraw_proven ADD COLUMN IF NOT EXISTS invalidated BOOLEAN DEFAULT FALSE;
+ALTER TABLE l2_to_l1 ADD COLUMN IF NOT EXISTS reprove_required BOOLEAN DEFAULT FALSE;
@@ -0,0 +1,6 @@
+ALTER TABLE state_root ADD COLUMN IF NOT EXISTS verification_status SMALLINT NOT NULL DEFAULT 0;
+ALTER TABLE state_root ADD COLUMN IF NOT EXISTS l2_state_root VARCHAR;
+ALTER TABLE state_root ADD COLUMN IF NOT EXISTS message_passer_storage_root VARCHAR;
+ALTER TABLE state_root ADD COLUMN IF NOT EXISTS l2_block_hash VARCHAR;
+ALTER TABLE state_root ADD COLUMN IF NOT EXISTS computed_output_root VARCHAR;
+CREATE INDEX IF NOT EXISTS state_root_verification_status ON state_root(verification_status);
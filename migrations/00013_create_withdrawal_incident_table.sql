@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS withdrawal_incident (
+    guid                 VARCHAR PRIMARY KEY,
+    kind                 VARCHAR NOT NULL,
+    withdraw_hash        VARCHAR NOT NULL,
+    l1_transaction_hash  VARCHAR NOT NULL,
+    l1_block_number      UINT256 NOT NULL,
+    l1_eth_amount        UINT256,
+    l1_erc20_amount      UINT256,
+    l2_eth_amount        UINT256,
+    l2_erc20_amount      UINT256,
+    resolved             BOOLEAN DEFAULT FALSE,
+    timestamp            INTEGER NOT NULL CHECK (timestamp > 0),
+    detected_at          INTEGER NOT NULL,
+    UNIQUE (kind, withdraw_hash, l1_transaction_hash)
+);
+CREATE INDEX IF NOT EXISTS withdrawal_incident_l1_block_number ON withdrawal_incident(l1_block_number);
+CREATE INDEX IF NOT EXISTS withdrawal_incident_timestamp ON withdrawal_incident(timestamp);
+
+-- the amounts of the finalizations indexed before were not bound to their withdrawal, they are not compared
+ALTER TABLE withdraw_finalized ADD COLUMN IF NOT EXISTS bridge_finalized BOOLEAN DEFAULT FALSE;
@@ -0,0 +1,6 @@
+ALTER TABLE l1_to_l2 ADD COLUMN IF NOT EXISTS l2_deposit_transaction_hash VARCHAR;
+ALTER TABLE l1_to_l2 ADD COLUMN IF NOT EXISTS l2_deposit_status SMALLINT NOT NULL DEFAULT 0;
+ALTER TABLE l1_to_l2 ADD COLUMN IF NOT EXISTS l2_deposit_block_number UINT256;
+ALTER TABLE l1_to_l2 ADD COLUMN IF NOT EXISTS l2_deposit_gas_used UINT256;
+CREATE INDEX IF NOT EXISTS l1_to_l2_l2_deposit_transaction_hash ON l1_to_l2(l2_deposit_transaction_hash);
+CREATE INDEX IF NOT EXISTS l1_to_l2_l2_deposit_status ON l1_to_l2(l2_deposit_status);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS bridge_status_history (
+    id               SERIAL PRIMARY KEY,
+    bridge           VARCHAR NOT NULL,
+    bridge_guid      VARCHAR NOT NULL,
+    from_status      SMALLINT,
+    to_status        SMALLINT NOT NULL,
+    reason           VARCHAR NOT NULL,
+    chain            VARCHAR,
+    block_number     UINT256,
+    transaction_hash VARCHAR,
+    timestamp        INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS bridge_status_history_bridge_guid ON bridge_status_history(bridge, bridge_guid);
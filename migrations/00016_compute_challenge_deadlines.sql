@@ -0,0 +1,12 @@
+ALTER TABLE l2_to_l1 ADD COLUMN IF NOT EXISTS l1_proven_timestamp INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE l2_to_l1 ADD COLUMN IF NOT EXISTS challenge_deadline INTEGER NOT NULL DEFAULT 0;
+-- the withdrawals ready for claim are computed from their challenge deadline at query time, the ones stored as
+-- ready for claim (3, L2ToL1ReadyForClaim in common/status.go) are moved back in their challenge period
+-- (2, L2ToL1InChallengePeriod)
+UPDATE l2_to_l1 SET status = 2 WHERE status = 3;
+-- the challenge deadlines of the withdrawals proven so far are set by the business processor, once it has read the
+-- finalization period on chain. The proven withdrawals are the ones in their challenge period (2,
+-- L2ToL1InChallengePeriod), claimed (4, L2ToL1Claimed) or whose relay failed (5, L2ToL1RelayFailed).
+UPDATE l2_to_l1 SET l1_proven_timestamp = (SELECT timestamp FROM withdraw_proven WHERE withdraw_proven.proven_transaction_hash = l2_to_l1.l1_prove_tx_hash LIMIT 1)
+WHERE l1_proven_timestamp = 0 AND status IN (2, 4, 5) AND EXISTS (SELECT 1 FROM withdraw_proven WHERE withdraw_proven.proven_transaction_hash = l2_to_l1.l1_prove_tx_hash);
+ALTER TABLE l2_to_l1 DROP COLUMN IF EXISTS time_left;
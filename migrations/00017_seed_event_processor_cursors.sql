@@ -0,0 +1,34 @@
+-- the event processor stages resume from their sync cursors only. Deployments that indexed events before the cursors
+-- were written have them seeded from the latest header of each stage's indexed state
+INSERT INTO sync_cursors (name, layer, block_number, block_hash, updated_at)
+SELECT 'l1_bridge_initiated', 'l1', number, hash, EXTRACT(EPOCH FROM NOW())::INTEGER FROM l1_block_headers
+WHERE number = (SELECT MAX(l1_block_number) FROM l1_to_l2)
+ON CONFLICT (name) DO NOTHING;
+INSERT INTO sync_cursors (name, layer, block_number, block_hash, updated_at)
+SELECT 'l2_bridge_finalized', 'l2', number, hash, EXTRACT(EPOCH FROM NOW())::INTEGER FROM l2_block_headers
+WHERE number = (SELECT MAX(l2_block_number) FROM l1_to_l2 WHERE status = 2)
+ON CONFLICT (name) DO NOTHING;
+INSERT INTO sync_cursors (name, layer, block_number, block_hash, updated_at)
+SELECT 'l1_state_root', 'l1', number, hash, EXTRACT(EPOCH FROM NOW())::INTEGER FROM l1_block_headers
+WHERE number = (SELECT MAX(l1_block_number) FROM state_root)
+ON CONFLICT (name) DO NOTHING;
+INSERT INTO sync_cursors (name, layer, block_number, block_hash, updated_at)
+SELECT 'l1_mantle_da', 'l1', number, hash, EXTRACT(EPOCH FROM NOW())::INTEGER FROM l1_block_headers
+WHERE number = (SELECT MAX(from_store_number) FROM data_store)
+ON CONFLICT (name) DO NOTHING;
+INSERT INTO sync_cursors (name, layer, block_number, block_hash, updated_at)
+SELECT 'l2_bridge_initiated', 'l2', number, hash, EXTRACT(EPOCH FROM NOW())::INTEGER FROM l2_block_headers
+WHERE number = (SELECT MAX(l2_block_number) FROM l2_to_l1)
+ON CONFLICT (name) DO NOTHING;
+INSERT INTO sync_cursors (name, layer, block_number, block_hash, updated_at)
+SELECT 'l1_withdraw_proven', 'l1', number, hash, EXTRACT(EPOCH FROM NOW())::INTEGER FROM l1_block_headers
+WHERE number = (SELECT MAX(block_number) FROM withdraw_proven)
+ON CONFLICT (name) DO NOTHING;
+INSERT INTO sync_cursors (name, layer, block_number, block_hash, updated_at)
+SELECT 'l1_withdraw_finalized', 'l1', number, hash, EXTRACT(EPOCH FROM NOW())::INTEGER FROM l1_block_headers
+WHERE number = (SELECT MAX(block_number) FROM withdraw_finalized)
+ON CONFLICT (name) DO NOTHING;
+INSERT INTO sync_cursors (name, layer, block_number, block_hash, updated_at)
+SELECT 'l1_system_config', 'l1', number, hash, EXTRACT(EPOCH FROM NOW())::INTEGER FROM l1_block_headers
+WHERE number = (SELECT MAX(l1_block_number) FROM system_config_update)
+ON CONFLICT (name) DO NOTHING;
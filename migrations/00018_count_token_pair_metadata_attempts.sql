@@ -0,0 +1 @@
+ALTER TABLE token_pair ADD COLUMN IF NOT EXISTS metadata_attempts INTEGER NOT NULL DEFAULT 0;
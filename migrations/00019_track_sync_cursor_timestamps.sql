@@ -0,0 +1 @@
+ALTER TABLE sync_cursors ADD COLUMN IF NOT EXISTS block_timestamp INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,7 @@
+ALTER TABLE erc721_bridge ADD COLUMN IF NOT EXISTS l1_proven_timestamp INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE erc721_bridge ADD COLUMN IF NOT EXISTS challenge_deadline INTEGER NOT NULL DEFAULT 0;
+-- the challenge deadlines of the NFT withdrawals proven so far are set by the business processor, once it has read
+-- the finalization period on chain. The proven withdrawals are the ones in their challenge period (2,
+-- L2ToL1InChallengePeriod in common/status.go) or claimed (4, L2ToL1Claimed).
+UPDATE erc721_bridge SET l1_proven_timestamp = (SELECT timestamp FROM withdraw_proven WHERE withdraw_proven.proven_transaction_hash = erc721_bridge.l1_prove_tx_hash LIMIT 1)
+WHERE direction = 'withdrawal' AND l1_proven_timestamp = 0 AND status IN (2, 4) AND EXISTS (SELECT 1 FROM withdraw_proven WHERE withdraw_proven.proven_transaction_hash = erc721_bridge.l1_prove_tx_hash);
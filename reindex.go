@@ -0,0 +1,188 @@
+package lithosphere
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/mantlenetworkio/lithosphere/config"
+	"github.com/mantlenetworkio/lithosphere/database"
+	"github.com/mantlenetworkio/lithosphere/database/business"
+	metrics2 "github.com/mantlenetworkio/lithosphere/metrics"
+)
+
+const (
+	ReindexChainL1 = "l1"
+	ReindexChainL2 = "l2"
+)
+
+// errReindexDryRun aborts the reindex transaction once the affected rows have been reported
+var errReindexDryRun = errors.New("reindex dry run")
+
+// reindexStep deletes, or resets, the rows of a table derived from the reindexed range
+type reindexStep struct {
+	table  string
+	action string
+	apply  func(tx *database.DB) (int64, error)
+}
+
+// Reindex re-derives the indexed state of a chain for the [from, to] block range. The headers, contract events
+// and bridge, state root, system config, fee vault and DA rows derived from the range are deleted, then the range is
+// refetched from RPC and replayed through the synchronizer and event processor, all within a single transaction.
+// The indexer must be stopped while reindexing. In dry run mode nothing is changed, the rows that would be
+// deleted are reported.
+func Reindex(ctx context.Context, log log.Logger, cfg *config.Config, chain string, from, to *big.Int, dryRun bool) (result error) {
+	if from.Cmp(to) > 0 {
+		return fmt.Errorf("invalid range, from %s is above to %s", from, to)
+	}
+
+	i := &Lithosphere{log: log, metricsRegistry: metrics2.NewRegistry(), shutdown: func(error) {}}
+	defer func() {
+		result = errors.Join(result, i.Stop(ctx))
+	}()
+	if err := i.initRPCClients(ctx, cfg.RPCs); err != nil {
+		return fmt.Errorf("failed to start RPC clients: %w", err)
+	}
+	if err := i.initDB(ctx, cfg.MasterDB); err != nil {
+		return fmt.Errorf("failed to init DB: %w", err)
+	}
+	if err := i.initL1Syncer(*cfg); err != nil {
+		return fmt.Errorf("failed to init L1 Sync: %w", err)
+	}
+	if err := i.initL2ETL(*cfg); err != nil {
+		return fmt.Errorf("failed to init L2 Sync: %w", err)
+	}
+	if err := i.initBridgeProcessor(cfg.Chain); err != nil {
+		return fmt.Errorf("failed to init Bridge Processor: %w", err)
+	}
+
+	var steps []reindexStep
+	var replay func(tx *database.DB) error
+	switch chain {
+	case ReindexChainL1:
+		if i.L1Sync.LatestHeader == nil || i.L1Sync.LatestHeader.Number.Cmp(to) < 0 {
+			return fmt.Errorf("range ends above the indexed L1 height")
+		}
+		l1Steps, err := i.l1ReindexSteps(from, to)
+		if err != nil {
+			return err
+		}
+		steps = l1Steps
+		replay = func(tx *database.DB) error {
+			if err := i.L1Sync.Reindex(tx, from, to); err != nil {
+				return err
+			}
+			return i.BridgeProcessor.ReprocessL1(tx, from, to)
+		}
+	case ReindexChainL2:
+		if i.L2Sync.LatestHeader == nil || i.L2Sync.LatestHeader.Number.Cmp(to) < 0 {
+			return fmt.Errorf("range ends above the indexed L2 height")
+		}
+		steps = l2ReindexSteps(from, to)
+		replay = func(tx *database.DB) error {
+			if err := i.L2Sync.Reindex(tx, from, to); err != nil {
+				return err
+			}
+			return i.BridgeProcessor.ReprocessL2(tx, from, to)
+		}
+	default:
+		return fmt.Errorf("unknown chain %q, expected %s or %s", chain, ReindexChainL1, ReindexChainL2)
+	}
+
+	reindexLog := log.New("chain", chain, "from", from, "to", to, "dry_run", dryRun)
+	reindexLog.Info("reindexing range")
+	err := i.DB.Transaction(func(tx *database.DB) error {
+		for _, step := range steps {
+			rows, err := step.apply(tx)
+			// the bridges which may not move back are left in their status, as on a rollback
+			rejected := 0
+			err = business.HandleRejectedTransitions(err, func(business.RejectedTransition) { rejected++ })
+			if err != nil {
+				return fmt.Errorf("unable to %s %s rows: %w", step.action, step.table, err)
+			}
+			reindexLog.Info(step.action+" rows", "table", step.table, "rows", rows, "rejected_transitions", rejected)
+		}
+		if dryRun {
+			return errReindexDryRun
+		}
+		return replay(tx)
+	})
+	if errors.Is(err, errReindexDryRun) {
+		reindexLog.Info("dry run complete, nothing was changed")
+		return nil
+	} else if err != nil {
+		return err
+	}
+	reindexLog.Info("reindexed range")
+	return nil
+}
+
+// l1ReindexSteps lists the rows derived from the L1 range. Rows referring to them are reset first.
+func (i *Lithosphere) l1ReindexSteps(from, to *big.Int) ([]reindexStep, error) {
+	// data store events are only keyed by the timestamp of their L1 block
+	fromHeader, err := i.l1Client.BlockHeaderByNumber(from)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch L1 header %s: %w", from, err)
+	}
+	toHeader, err := i.l1Client.BlockHeaderByNumber(to)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch L1 header %s: %w", to, err)
+	}
+
+	return []reindexStep{
+		{"relay_message", "reset", func(tx *database.DB) (int64, error) { return tx.RelayMessage.ResetRelayMessageRelated(from, to) }},
+		{"l2_to_l1", "reset", func(tx *database.DB) (int64, error) { return tx.L2ToL1.ResetL2ToL1Finalized(from, to) }},
+		{"l2_to_l1", "reset", func(tx *database.DB) (int64, error) { return tx.L2ToL1.ResetL2ToL1Proven(from, to) }},
+		{"state_root", "reset", func(tx *database.DB) (int64, error) { return tx.StateRoots.ResetStateRootsDeleted(from, to) }},
+		{"withdraw_proven", "reset", func(tx *database.DB) (int64, error) { return tx.WithdrawProven.RevalidateWithdrawProven() }},
+		{"erc721_bridge", "delete", func(tx *database.DB) (int64, error) { return tx.ERC721Bridge.DeleteL1ERC721Bridges(from, to) }},
+		{"l1_to_l2", "delete", func(tx *database.DB) (int64, error) { return tx.L1ToL2.DeleteL1ToL2Transactions(from, to) }},
+		{"withdraw_finalized", "delete", func(tx *database.DB) (int64, error) { return tx.WithdrawFinalized.DeleteWithdrawFinalized(from, to) }},
+		{"withdrawal_incident", "delete", func(tx *database.DB) (int64, error) {
+			return tx.WithdrawalIncident.DeleteWithdrawalIncidents(from, to)
+		}},
+		{"relay_attempt", "delete", func(tx *database.DB) (int64, error) {
+			return tx.RelayAttempt.DeleteRelayAttempts(ReindexChainL1, from, to)
+		}},
+		{"withdraw_proven", "delete", func(tx *database.DB) (int64, error) { return tx.WithdrawProven.DeleteWithdrawProven(from, to) }},
+		{"state_root", "delete", func(tx *database.DB) (int64, error) { return tx.StateRoots.DeleteStateRoots(from, to) }},
+		{"system_config_update", "delete", func(tx *database.DB) (int64, error) {
+			return tx.SystemConfig.DeleteSystemConfigUpdates(from, to)
+		}},
+		{"data_store", "delete", func(tx *database.DB) (int64, error) {
+			return tx.DataStore.DeleteDataStores(fromHeader.Time, toHeader.Time)
+		}},
+		{"data_store_event", "delete", func(tx *database.DB) (int64, error) {
+			return tx.DataStoreEvent.DeleteDataStoreEvents(fromHeader.Time, toHeader.Time)
+		}},
+		{"l1_contract_events", "delete", func(tx *database.DB) (int64, error) { return tx.ContractEvents.DeleteL1ContractEvents(from, to) }},
+		{"l1_block_headers", "delete", func(tx *database.DB) (int64, error) { return tx.Blocks.DeleteL1BlockHeaders(from, to) }},
+	}, nil
+}
+
+// l2ReindexSteps lists the rows derived from the L2 range. Rows referring to them are reset first.
+func l2ReindexSteps(from, to *big.Int) []reindexStep {
+	return []reindexStep{
+		{"l1_to_l2", "reset", func(tx *database.DB) (int64, error) { return tx.L1ToL2.ResetL1ToL2Relayed(from, to) }},
+		{"l1_to_l2", "reset", func(tx *database.DB) (int64, error) { return tx.L1ToL2.ResetL1ToL2DepositReceipts(from, to) }},
+		{"withdraw_finalized", "reset", func(tx *database.DB) (int64, error) {
+			return tx.WithdrawFinalized.ResetWithdrawFinalizedRelated(from, to)
+		}},
+		{"withdraw_proven", "reset", func(tx *database.DB) (int64, error) { return tx.WithdrawProven.ResetWithdrawProvenRelated(from, to) }},
+		{"relay_message", "delete", func(tx *database.DB) (int64, error) { return tx.RelayMessage.DeleteRelayMessages(from, to) }},
+		{"relay_attempt", "delete", func(tx *database.DB) (int64, error) {
+			return tx.RelayAttempt.DeleteRelayAttempts(ReindexChainL2, from, to)
+		}},
+		{"erc721_bridge", "delete", func(tx *database.DB) (int64, error) { return tx.ERC721Bridge.DeleteL2ERC721Bridges(from, to) }},
+		{"fee_vault_withdrawal", "delete", func(tx *database.DB) (int64, error) { return tx.FeeVault.DeleteFeeVaultWithdrawals(from, to) }},
+		{"fee_vault_balance", "delete", func(tx *database.DB) (int64, error) { return tx.FeeVault.DeleteFeeVaultBalances(from, to) }},
+		{"token_pair", "delete", func(tx *database.DB) (int64, error) { return tx.TokenPair.DeleteTokenPairs(from, to) }},
+		{"l2_to_l1", "delete", func(tx *database.DB) (int64, error) { return tx.L2ToL1.DeleteL2ToL1Transactions(from, to) }},
+		{"transactions", "delete", func(tx *database.DB) (int64, error) { return tx.Transactions.DeleteTransactions(from, to) }},
+		{"l2_contract_events", "delete", func(tx *database.DB) (int64, error) { return tx.ContractEvents.DeleteL2ContractEvents(from, to) }},
+		{"l2_block_headers", "delete", func(tx *database.DB) (int64, error) { return tx.Blocks.DeleteL2BlockHeaders(from, to) }},
+	}
+}
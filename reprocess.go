@@ -0,0 +1,34 @@
+package lithosphere
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/mantlenetworkio/lithosphere/config"
+	metrics2 "github.com/mantlenetworkio/lithosphere/metrics"
+)
+
+// Reprocess rebuilds the tables derived from the indexed contract events by replaying the event processor
+// stages offline. Only the database is opened, no RPC client is dialed. An interrupted run resumes where it
+// stopped the next time it is started, unless restart is set. The indexer must be stopped while reprocessing.
+func Reprocess(ctx context.Context, log log.Logger, cfg *config.Config, restart bool) (result error) {
+	i := &Lithosphere{log: log, metricsRegistry: metrics2.NewRegistry(), shutdown: func(error) {}}
+	defer func() {
+		result = errors.Join(result, i.Stop(ctx))
+	}()
+	if err := i.initDB(ctx, cfg.MasterDB); err != nil {
+		return fmt.Errorf("failed to init DB: %w", err)
+	}
+	if err := i.initBridgeProcessor(cfg.Chain); err != nil {
+		return fmt.Errorf("failed to init Bridge Processor: %w", err)
+	}
+
+	if err := i.BridgeProcessor.Reprocess(ctx, restart); err != nil {
+		return err
+	}
+	log.Info("reprocessed contract events")
+	return nil
+}
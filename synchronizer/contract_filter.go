@@ -0,0 +1,49 @@
+package synchronizer
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// contractFilter restricts log extraction to an allowlist of events per contract. eth_getLogs
+// can only match the union of every contract's events, so logs are matched per contract again
+// once retrieved.
+type contractFilter struct {
+	addresses []common.Address
+	events    map[common.Address]map[common.Hash]bool
+	topics    []common.Hash
+}
+
+func newContractFilter() *contractFilter {
+	return &contractFilter{events: make(map[common.Address]map[common.Hash]bool)}
+}
+
+func (f *contractFilter) add(addr common.Address, events []common.Hash) {
+	if _, ok := f.events[addr]; !ok {
+		f.addresses = append(f.addresses, addr)
+		f.events[addr] = make(map[common.Hash]bool, len(events))
+	}
+
+	topics := make(map[common.Hash]bool, len(f.topics))
+	for _, topic := range f.topics {
+		topics[topic] = true
+	}
+	for _, event := range events {
+		f.events[addr][event] = true
+		if !topics[event] {
+			topics[event] = true
+			f.topics = append(f.topics, event)
+		}
+	}
+}
+
+func (f *contractFilter) query(fromHeight, toHeight *big.Int) ethereum.FilterQuery {
+	return ethereum.FilterQuery{FromBlock: fromHeight, ToBlock: toHeight, Addresses: f.addresses, Topics: [][]common.Hash{f.topics}}
+}
+
+func (f *contractFilter) allowed(log *types.Log) bool {
+	return len(log.Topics) > 0 && f.events[log.Address][log.Topics[0]]
+}
@@ -4,18 +4,21 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/big"
 	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
+	"gorm.io/gorm"
 
 	"github.com/mantlenetworkio/lithosphere/common/tasks"
 	"github.com/mantlenetworkio/lithosphere/config"
 	"github.com/mantlenetworkio/lithosphere/database"
 	common2 "github.com/mantlenetworkio/lithosphere/database/common"
 	"github.com/mantlenetworkio/lithosphere/database/event"
+	"github.com/mantlenetworkio/lithosphere/event/handlers"
 	"github.com/mantlenetworkio/lithosphere/metrics"
 	"github.com/mantlenetworkio/lithosphere/synchronizer/node"
 	"github.com/mantlenetworkio/lithosphere/synchronizer/retry"
@@ -28,36 +31,53 @@ type L1Sync struct {
 	resourceCancel context.CancelFunc
 	tasks          tasks.Group
 	db             *database.DB
+	handlers       *handlers.Registry
 }
 
 func NewL1Sync(cfg Config, log log.Logger, db *database.DB, metrics metrics.Metricer, client node.EthClient,
 	contracts config.L1Contracts, shutdown context.CancelCauseFunc, transferBigValueInEthereum string) (*L1Sync, error) {
 	log = log.New("synchronizer", "l1")
 	zeroAddr := common.Address{}
-	l1Contracts := []common.Address{}
+	l1Contracts := newContractFilter()
 	if err := contracts.ForEach(func(name string, addr common.Address) error {
 		if addr == zeroAddr && !strings.HasPrefix(name, "Legacy") {
 			log.Error("address not configured", "name", name)
 			return errors.New("all L1Contracts must be configured")
 		}
-		log.Info("configured contract", "name", name, "addr", addr)
-		l1Contracts = append(l1Contracts, addr)
+		log.Info("configured contract", "name", name, "addr", addr, "events", len(cfg.ContractEvents[name]))
+		if events, ok := cfg.ContractEvents[name]; ok {
+			l1Contracts.add(addr, events)
+		}
 		return nil
 	}); err != nil {
 		return nil, err
 	}
+	if len(l1Contracts.addresses) == 0 {
+		return nil, errors.New("no L1 contract events configured")
+	}
 	transferBigValueAddresses := strings.Split(transferBigValueInEthereum, " ")
 	for _, bigValueAddress := range transferBigValueAddresses {
 		address := common.HexToAddress(bigValueAddress)
-		l1Contracts = append(l1Contracts, address)
+		l1Contracts.add(address, cfg.ContractEvents[config.TransferBigValueContracts])
+	}
+	for _, registration := range cfg.Handlers.Registrations(common2.SyncCursorLayerL1) {
+		log.Info("configured handler", "name", registration.Handler.Name(), "events", len(registration.Keys))
+		for _, key := range registration.Keys {
+			l1Contracts.add(key.Contract, []common.Hash{key.EventSignature})
+		}
 	}
 
+	fromHeader, err := cursorHeader(log, db, client, common2.L1SynchronizerCursor)
+	if err != nil {
+		return nil, err
+	}
 	latestHeader, err := db.Blocks.L1LatestBlockHeader()
 	if err != nil {
 		return nil, err
 	}
-	var fromHeader *types.Header
-	if latestHeader != nil {
+	if fromHeader != nil {
+		log.Info("l1 sync resuming from sync cursor", "number", fromHeader.Number, "hash", fromHeader.Hash())
+	} else if latestHeader != nil {
 		log.Info("l1 sync detected last indexed block", "number", latestHeader.Number, "hash", latestHeader.Hash)
 		fromHeader = latestHeader.RLPHeader.Header()
 	} else if cfg.StartHeight.BitLen() > 0 {
@@ -70,23 +90,37 @@ func NewL1Sync(cfg Config, log log.Logger, db *database.DB, metrics metrics.Metr
 	} else {
 		log.Info("no l2 sync indexed state, starting from genesis")
 	}
-	synchronizerBatches := make(chan *SynchronizerBatch)
-	synchronizer := Synchronizer{
-		loopInterval:     time.Duration(cfg.LoopIntervalMsec) * time.Millisecond,
-		headerBufferSize: uint64(cfg.HeaderBufferSize),
-		log:              log,
-		metrics:          metrics,
-		headerTraversal:  node.NewHeaderTraversal(client, fromHeader, cfg.ConfirmationDepth),
-		contracts:        l1Contracts,
-		syncerBatches:    synchronizerBatches,
-		EthClient:        client,
+	// the events of the handlers registered since the last run are extracted from here on
+	if err := cfg.Handlers.StoreExtractionCursors(db, common2.SyncCursorLayerL1, fromHeader); err != nil {
+		return nil, fmt.Errorf("failed to store handler extraction cursors: %w", err)
 	}
+	synchronizerBatches := make(chan *SynchronizerBatch)
 
 	resCtx, resCancel := context.WithCancel(context.Background())
 	return &L1Sync{
-		Synchronizer:   synchronizer,
+		Synchronizer: Synchronizer{
+			loopInterval:      time.Duration(cfg.LoopIntervalMsec) * time.Millisecond,
+			headerBufferSize:  uint64(cfg.HeaderBufferSize),
+			subscribeNewHeads: cfg.SubscribeNewHeads,
+			log:               log,
+			metrics:           metrics,
+			headerTraversal:   node.NewHeaderTraversal(client, fromHeader, cfg.ConfirmationDepth, cfg.TraversalMode),
+			contracts:         l1Contracts,
+			syncerBatches:     synchronizerBatches,
+			EthClient:         client,
+			lastIndexedHeaderBelow: func(number *big.Int) (*types.Header, error) {
+				header, err := db.Blocks.L1BlockHeaderWithScope(func(db *gorm.DB) *gorm.DB {
+					return db.Where("number < ?", number).Order("number DESC")
+				})
+				if err != nil || header == nil {
+					return nil, err
+				}
+				return header.RLPHeader.Header(), nil
+			},
+		},
 		LatestHeader:   fromHeader,
 		db:             db,
+		handlers:       cfg.Handlers,
 		resourceCtx:    resCtx,
 		resourceCancel: resCancel,
 		tasks: tasks.Group{HandleCrit: func(err error) {
@@ -115,8 +149,9 @@ func (l1Sync *L1Sync) Start() error {
 	l1Sync.tasks.Go(func() error {
 		for batch := range l1Sync.syncerBatches {
 			if err := l1Sync.handleBatch(batch); err != nil {
-				return fmt.Errorf("failed to handle batch, stopping L2 Synchronizer: %w", err)
+				return fmt.Errorf("failed to handle batch, stopping L1 Synchronizer: %w", err)
 			}
+			l1Sync.notifier.Publish()
 		}
 		return nil
 	})
@@ -124,16 +159,64 @@ func (l1Sync *L1Sync) Start() error {
 }
 
 func (l1Sync *L1Sync) handleBatch(batch *SynchronizerBatch) error {
-	l1BlockHeaders := make([]common2.L1BlockHeader, 0, len(batch.Headers))
-	for i := range batch.Headers {
-		if _, ok := batch.HeadersWithLog[batch.Headers[i].Hash()]; ok {
-			l1BlockHeaders = append(l1BlockHeaders, common2.L1BlockHeader{BlockHeader: common2.BlockHeaderFromHeader(&batch.Headers[i])})
-		}
+	if batch.CommonAncestor != nil {
+		return l1Sync.rollback(batch)
 	}
 
+	l1BlockHeaders, l1ContractEvents := l1Sync.batchRows(batch)
 	if len(l1BlockHeaders) == 0 {
 		batch.Logger.Info("no l1 blocks with logs in batch")
+	}
+
+	lastHeader := batch.Headers[len(batch.Headers)-1]
+	retryStrategy := &retry.ExponentialStrategy{Min: 1000, Max: 20_000, MaxJitter: 250}
+	if _, err := retry.Do[interface{}](l1Sync.resourceCtx, 10, retryStrategy, func() (interface{}, error) {
+		if err := l1Sync.db.Transaction(func(tx *database.DB) error {
+			// the cursor tracks the last traversed header, which is only stored when it has logs
+			if err := tx.SyncCursors.StoreSyncCursor(common2.L1SynchronizerCursor, common2.SyncCursorLayerL1, lastHeader.Number, lastHeader.Hash(), lastHeader.Time); err != nil {
+				return err
+			}
+			return storeL1Batch(tx, l1BlockHeaders, l1ContractEvents)
+		}); err != nil {
+			batch.Logger.Error("unable to persist batch", "err", err)
+			return nil, fmt.Errorf("unable to persist batch: %w", err)
+		}
+		if len(l1BlockHeaders) > 0 {
+			l1Sync.Synchronizer.metrics.RecordIndexedHeaders(len(l1BlockHeaders))
+			l1Sync.Synchronizer.metrics.RecordIndexedLatestHeight(l1BlockHeaders[len(l1BlockHeaders)-1].Number)
+		}
+		return nil, nil
+	}); err != nil {
+		return err
+	}
+	batch.Logger.Info("indexed l1 batch")
+	return nil
+}
+
+// Reindex refetches the headers and logs of the [from, to] range and stores them within the supplied
+// transaction, without moving the synchronizer cursor. The range must have been deleted beforehand.
+// The extraction cursors of the handlers it covers are moved below it.
+func (l1Sync *L1Sync) Reindex(tx *database.DB, from, to *big.Int) error {
+	if err := l1Sync.reindexBatches(from, to, func(batch *SynchronizerBatch) error {
+		l1BlockHeaders, l1ContractEvents := l1Sync.batchRows(batch)
+		if err := storeL1Batch(tx, l1BlockHeaders, l1ContractEvents); err != nil {
+			return fmt.Errorf("unable to persist batch: %w", err)
+		}
+		batch.Logger.Info("reindexed l1 batch", "headers", len(l1BlockHeaders), "events", len(l1ContractEvents))
 		return nil
+	}); err != nil {
+		return err
+	}
+	return l1Sync.handlers.ReindexExtractionCursors(tx, common2.SyncCursorLayerL1, from, to)
+}
+
+// batchRows returns the headers with logs of the batch along with their contract events
+func (l1Sync *L1Sync) batchRows(batch *SynchronizerBatch) ([]common2.L1BlockHeader, []event.L1ContractEvent) {
+	l1BlockHeaders := make([]common2.L1BlockHeader, 0, len(batch.Headers))
+	for i := range batch.Headers {
+		if _, ok := batch.HeadersWithLog[batch.Headers[i].Hash()]; ok {
+			l1BlockHeaders = append(l1BlockHeaders, common2.L1BlockHeader{BlockHeader: common2.BlockHeaderFromHeader(&batch.Headers[i])})
+		}
 	}
 
 	l1ContractEvents := make([]event.L1ContractEvent, len(batch.Logs))
@@ -142,27 +225,89 @@ func (l1Sync *L1Sync) handleBatch(batch *SynchronizerBatch) error {
 		l1ContractEvents[i] = event.L1ContractEvent{ContractEvent: event.ContractEventFromLog(&batch.Logs[i], timestamp)}
 		l1Sync.Synchronizer.metrics.RecordBatchLog(batch.Logs[i].Address)
 	}
+	return l1BlockHeaders, l1ContractEvents
+}
 
+func storeL1Batch(tx *database.DB, l1BlockHeaders []common2.L1BlockHeader, l1ContractEvents []event.L1ContractEvent) error {
+	if len(l1BlockHeaders) == 0 {
+		return nil
+	}
+	if err := tx.Blocks.StoreL1BlockHeaders(l1BlockHeaders); err != nil {
+		return err
+	}
+	return tx.ContractEvents.StoreL1ContractEvents(l1ContractEvents)
+}
+
+// rollback removes every l1 header above the batch's common ancestor, along with all
+// the state derived from them, in a single transaction.
+func (l1Sync *L1Sync) rollback(batch *SynchronizerBatch) error {
+	height := batch.CommonAncestor.Number
 	retryStrategy := &retry.ExponentialStrategy{Min: 1000, Max: 20_000, MaxJitter: 250}
 	if _, err := retry.Do[interface{}](l1Sync.resourceCtx, 10, retryStrategy, func() (interface{}, error) {
 		if err := l1Sync.db.Transaction(func(tx *database.DB) error {
-			if err := tx.Blocks.StoreL1BlockHeaders(l1BlockHeaders); err != nil {
+			// headers go first so that the rollback waits on processors still holding on to them
+			if err := tx.Blocks.RollbackL1BlockHeaders(height); err != nil {
+				return err
+			}
+			if err := tx.L1ToL2.RollbackL1ToL2Transactions(height); err != nil {
+				return err
+			}
+			if err := l1Sync.rejectedTransitions(tx.L2ToL1.RollbackL2ToL1Finalized(height)); err != nil {
+				return err
+			}
+			if err := l1Sync.rejectedTransitions(tx.L2ToL1.RollbackL2ToL1Proven(height)); err != nil {
+				return err
+			}
+			if err := tx.RelayAttempt.RollbackRelayAttempts(common2.SyncCursorLayerL1, height); err != nil {
+				return err
+			}
+			if err := tx.WithdrawFinalized.RollbackWithdrawFinalized(height); err != nil {
+				return err
+			}
+			if err := tx.WithdrawalIncident.RollbackWithdrawalIncidents(height); err != nil {
 				return err
 			}
-			if err := tx.ContractEvents.StoreL1ContractEvents(l1ContractEvents); err != nil {
+			if err := tx.WithdrawProven.RollbackWithdrawProven(height); err != nil {
 				return err
 			}
-			return nil
+			if err := tx.StateRoots.RollbackStateRoots(height); err != nil {
+				return err
+			}
+			if _, err := tx.WithdrawProven.RevalidateWithdrawProven(); err != nil {
+				return err
+			}
+			if err := tx.ERC721Bridge.RollbackL1ERC721Bridges(height); err != nil {
+				return err
+			}
+			if err := tx.SystemConfig.RollbackSystemConfigUpdates(height); err != nil {
+				return err
+			}
+			latestStateRootL2BlockNumber, err := tx.StateRoots.GetLatestStateRootL2BlockNumber()
+			if err != nil {
+				return err
+			}
+			if err := l1Sync.rejectedTransitions(tx.L2ToL1.RollbackL2ToL1ReadyForProved(latestStateRootL2BlockNumber)); err != nil {
+				return err
+			}
+			if err := tx.DataStore.RollbackDataStores(batch.CommonAncestor.Time); err != nil {
+				return err
+			}
+			if err := tx.DataStoreEvent.RollbackDataStoreEvents(batch.CommonAncestor.Time); err != nil {
+				return err
+			}
+			if err := l1Sync.handlers.Rollback(tx, common2.SyncCursorLayerL1, height); err != nil {
+				return err
+			}
+			return tx.SyncCursors.RollbackSyncCursors(common2.SyncCursorLayerL1, height)
 		}); err != nil {
-			batch.Logger.Error("unable to persist batch", "err", err)
-			return nil, fmt.Errorf("unable to persist batch: %w", err)
+			batch.Logger.Error("unable to rollback l1 state", "err", err)
+			return nil, fmt.Errorf("unable to rollback l1 state: %w", err)
 		}
-		l1Sync.Synchronizer.metrics.RecordIndexedHeaders(len(l1BlockHeaders))
-		l1Sync.Synchronizer.metrics.RecordIndexedLatestHeight(l1BlockHeaders[len(l1BlockHeaders)-1].Number)
+		l1Sync.Synchronizer.metrics.RecordIndexedLatestHeight(height)
 		return nil, nil
 	}); err != nil {
 		return err
 	}
-	batch.Logger.Info("indexed l1 batch")
+	batch.Logger.Info("rolled back l1 state")
 	return nil
 }
@@ -4,23 +4,30 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/big"
 	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
+	"golang.org/x/sync/errgroup"
+	"gorm.io/gorm"
 
 	"github.com/mantlenetworkio/lithosphere/common/tasks"
 	"github.com/mantlenetworkio/lithosphere/config"
 	"github.com/mantlenetworkio/lithosphere/database"
 	common1 "github.com/mantlenetworkio/lithosphere/database/common"
 	"github.com/mantlenetworkio/lithosphere/database/event"
+	"github.com/mantlenetworkio/lithosphere/event/handlers"
 	"github.com/mantlenetworkio/lithosphere/metrics"
 	"github.com/mantlenetworkio/lithosphere/synchronizer/node"
 	"github.com/mantlenetworkio/lithosphere/synchronizer/retry"
 )
 
+// l2BlockFetchConcurrency bounds the number of blocks whose transactions are fetched in parallel
+const l2BlockFetchConcurrency = 8
+
 type L2Sync struct {
 	Synchronizer
 	LatestHeader   *types.Header
@@ -28,37 +35,53 @@ type L2Sync struct {
 	resourceCancel context.CancelFunc
 	tasks          tasks.Group
 	db             *database.DB
+	handlers       *handlers.Registry
 }
 
 func NewL2Sync(cfg Config, log log.Logger, db *database.DB, metrics metrics.Metricer, client node.EthClient,
 	contracts config.L2Contracts, shutdown context.CancelCauseFunc, transferBigValueInMantle string) (*L2Sync, error) {
 	log = log.New("syncer", "l2")
 	zeroAddr := common.Address{}
-	l2Contracts := []common.Address{}
+	l2Contracts := newContractFilter()
 	if err := contracts.ForEach(func(name string, addr common.Address) error {
 		if addr == zeroAddr {
 			log.Error("address not configured", "name", name)
 			return errors.New("all L2Contracts must be configured")
 		}
-		log.Info("configured l2 contract", "name", name, "addr", addr)
-		l2Contracts = append(l2Contracts, addr)
+		log.Info("configured l2 contract", "name", name, "addr", addr, "events", len(cfg.ContractEvents[name]))
+		if events, ok := cfg.ContractEvents[name]; ok {
+			l2Contracts.add(addr, events)
+		}
 		return nil
 	}); err != nil {
 		return nil, err
 	}
+	if len(l2Contracts.addresses) == 0 {
+		return nil, errors.New("no L2 contract events configured")
+	}
 	transferBigValueAddresses := strings.Split(transferBigValueInMantle, " ")
 	for _, bigValueAddress := range transferBigValueAddresses {
 		address := common.HexToAddress(bigValueAddress)
-		l2Contracts = append(l2Contracts, address)
+		l2Contracts.add(address, cfg.ContractEvents[config.TransferBigValueContracts])
+	}
+	for _, registration := range cfg.Handlers.Registrations(common1.SyncCursorLayerL2) {
+		log.Info("configured handler", "name", registration.Handler.Name(), "events", len(registration.Keys))
+		for _, key := range registration.Keys {
+			l2Contracts.add(key.Contract, []common.Hash{key.EventSignature})
+		}
 	}
 
+	fromHeader, err := cursorHeader(log, db, client, common1.L2SynchronizerCursor)
+	if err != nil {
+		return nil, err
+	}
 	latestHeader, err := db.Blocks.L2LatestBlockHeader()
 	if err != nil {
 		return nil, err
 	}
-
-	var fromHeader *types.Header
-	if latestHeader != nil {
+	if fromHeader != nil {
+		log.Info("l2 sync resuming from sync cursor", "number", fromHeader.Number, "hash", fromHeader.Hash())
+	} else if latestHeader != nil {
 		log.Info("l2 sync detected last indexed block", "number", latestHeader.Number, "hash", latestHeader.Hash)
 		fromHeader = latestHeader.RLPHeader.Header()
 	} else if cfg.StartHeight.BitLen() > 0 {
@@ -71,26 +94,40 @@ func NewL2Sync(cfg Config, log log.Logger, db *database.DB, metrics metrics.Metr
 	} else {
 		log.Info("no l2 indexed state")
 	}
+	// the events of the handlers registered since the last run are extracted from here on
+	if err := cfg.Handlers.StoreExtractionCursors(db, common1.SyncCursorLayerL2, fromHeader); err != nil {
+		return nil, fmt.Errorf("failed to store handler extraction cursors: %w", err)
+	}
 
 	syncerBatches := make(chan *SynchronizerBatch)
-	syncer := Synchronizer{
-		loopInterval:     time.Duration(cfg.LoopIntervalMsec) * time.Millisecond,
-		headerBufferSize: uint64(cfg.HeaderBufferSize),
-		log:              log,
-		metrics:          metrics,
-		headerTraversal:  node.NewHeaderTraversal(client, fromHeader, cfg.ConfirmationDepth),
-		contracts:        l2Contracts,
-		syncerBatches:    syncerBatches,
-		EthClient:        client,
-	}
 
 	resCtx, resCancel := context.WithCancel(context.Background())
 	return &L2Sync{
-		Synchronizer:   syncer,
+		Synchronizer: Synchronizer{
+			loopInterval:      time.Duration(cfg.LoopIntervalMsec) * time.Millisecond,
+			headerBufferSize:  uint64(cfg.HeaderBufferSize),
+			subscribeNewHeads: cfg.SubscribeNewHeads,
+			log:               log,
+			metrics:           metrics,
+			headerTraversal:   node.NewHeaderTraversal(client, fromHeader, cfg.ConfirmationDepth, cfg.TraversalMode),
+			contracts:         l2Contracts,
+			syncerBatches:     syncerBatches,
+			EthClient:         client,
+			lastIndexedHeaderBelow: func(number *big.Int) (*types.Header, error) {
+				header, err := db.Blocks.L2BlockHeaderWithScope(func(db *gorm.DB) *gorm.DB {
+					return db.Where("number < ?", number).Order("number DESC")
+				})
+				if err != nil || header == nil {
+					return nil, err
+				}
+				return header.RLPHeader.Header(), nil
+			},
+		},
 		LatestHeader:   fromHeader,
 		resourceCtx:    resCtx,
 		resourceCancel: resCancel,
 		db:             db,
+		handlers:       cfg.Handlers,
 		tasks: tasks.Group{HandleCrit: func(err error) {
 			shutdown(fmt.Errorf("critical error in L2 Synchronizer: %w", err))
 		}},
@@ -119,6 +156,7 @@ func (l2Sync *L2Sync) Start() error {
 			if err := l2Sync.handleBatch(batch); err != nil {
 				return fmt.Errorf("failed to handle batch, stopping L2 Synchronizer: %w", err)
 			}
+			l2Sync.notifier.Publish()
 		}
 		return nil
 	})
@@ -126,64 +164,179 @@ func (l2Sync *L2Sync) Start() error {
 }
 
 func (l2Sync *L2Sync) handleBatch(batch *SynchronizerBatch) error {
-	l2BlockHeaders := make([]common1.L2BlockHeader, len(batch.Headers))
-	var txList []common1.Transactions
-	for i := range batch.Headers {
-		l2BlockHeaders[i] = common1.L2BlockHeader{BlockHeader: common1.BlockHeaderFromHeader(&batch.Headers[i])}
-		tansactionList, err := l2Sync.EthClient.TxsByHash(l2BlockHeaders[i].Hash)
+	if batch.CommonAncestor != nil {
+		return l2Sync.rollback(batch)
+	}
+
+	l2BlockHeaders, l2ContractEvents := l2Sync.batchRows(batch)
+	txList, err := l2Sync.fetchTransactions(batch)
+	if err != nil {
+		return err
+	}
+	retryStrategy := &retry.ExponentialStrategy{Min: 1000, Max: 20_000, MaxJitter: 250}
+	if _, err := retry.Do[interface{}](l2Sync.resourceCtx, 10, retryStrategy, func() (interface{}, error) {
+		if err := l2Sync.db.Transaction(func(tx *database.DB) error {
+			if err := storeL2Batch(tx, l2BlockHeaders, l2ContractEvents, txList); err != nil {
+				return err
+			}
+			lastHeader := l2BlockHeaders[len(l2BlockHeaders)-1]
+			return tx.SyncCursors.StoreSyncCursor(common1.L2SynchronizerCursor, common1.SyncCursorLayerL2, lastHeader.Number, lastHeader.Hash, lastHeader.Timestamp)
+		}); err != nil {
+			batch.Logger.Error("unable to persist l2 batch", "err", err)
+			return nil, err
+		}
+		l2Sync.Synchronizer.metrics.RecordIndexedHeaders(len(l2BlockHeaders))
+		l2Sync.Synchronizer.metrics.RecordIndexedLatestHeight(l2BlockHeaders[len(l2BlockHeaders)-1].Number)
+		return nil, nil
+	}); err != nil {
+		return err
+	}
+	batch.Logger.Info("indexed l2 batch")
+	return nil
+}
+
+// Reindex refetches the headers, logs and transactions of the [from, to] range and stores them within the
+// supplied transaction, without moving the synchronizer cursor. The range must have been deleted beforehand.
+// The extraction cursors of the handlers it covers are moved below it.
+func (l2Sync *L2Sync) Reindex(tx *database.DB, from, to *big.Int) error {
+	if err := l2Sync.reindexBatches(from, to, func(batch *SynchronizerBatch) error {
+		l2BlockHeaders, l2ContractEvents := l2Sync.batchRows(batch)
+		txList, err := l2Sync.fetchTransactions(batch)
 		if err != nil {
 			return err
 		}
-		batch.Logger.Info("start l2 handle batch transaction", "transactions len", tansactionList.Len())
-		for j := range tansactionList {
-			tx, err := l2Sync.EthClient.TxDetailByHash(tansactionList[j].Hash())
-			if err != nil {
-				return err
-			}
-			txReceipt, err := l2Sync.EthClient.TxReceiptDetailByHash(tansactionList[j].Hash())
-			if err != nil {
-				return err
-			}
-			transaction, err := l2Sync.db.Transactions.BuildTransactions(tx, txReceipt)
-			if err != nil {
-				return err
-			}
-			txList = append(txList, transaction)
+		if err := storeL2Batch(tx, l2BlockHeaders, l2ContractEvents, txList); err != nil {
+			return fmt.Errorf("unable to persist l2 batch: %w", err)
 		}
+		batch.Logger.Info("reindexed l2 batch", "headers", len(l2BlockHeaders), "events", len(l2ContractEvents))
+		return nil
+	}); err != nil {
+		return err
+	}
+	return l2Sync.handlers.ReindexExtractionCursors(tx, common1.SyncCursorLayerL2, from, to)
+}
+
+// batchRows returns the headers of the batch along with their contract events
+func (l2Sync *L2Sync) batchRows(batch *SynchronizerBatch) ([]common1.L2BlockHeader, []event.L2ContractEvent) {
+	l2BlockHeaders := make([]common1.L2BlockHeader, len(batch.Headers))
+	for i := range batch.Headers {
+		l2BlockHeaders[i] = common1.L2BlockHeader{BlockHeader: common1.BlockHeaderFromHeader(&batch.Headers[i])}
 	}
+
 	l2ContractEvents := make([]event.L2ContractEvent, len(batch.Logs))
 	for i := range batch.Logs {
 		timestamp := batch.HeaderMap[batch.Logs[i].BlockHash].Time
 		l2ContractEvents[i] = event.L2ContractEvent{ContractEvent: event.ContractEventFromLog(&batch.Logs[i], timestamp)}
 		l2Sync.Synchronizer.metrics.RecordBatchLog(batch.Logs[i].Address)
 	}
+	return l2BlockHeaders, l2ContractEvents
+}
+
+func storeL2Batch(tx *database.DB, l2BlockHeaders []common1.L2BlockHeader, l2ContractEvents []event.L2ContractEvent, txList []common1.Transactions) error {
+	if err := tx.Blocks.StoreL2BlockHeaders(l2BlockHeaders); err != nil {
+		return err
+	}
+	if len(l2ContractEvents) > 0 {
+		if err := tx.ContractEvents.StoreL2ContractEvents(l2ContractEvents); err != nil {
+			return err
+		}
+	}
+	if len(txList) > 0 {
+		if err := tx.Transactions.StoreTransactions(txList); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fetchTransactions retrieves the transactions and receipts of every header in the batch,
+// with up to l2BlockFetchConcurrency blocks in flight. Transactions are returned in block order.
+func (l2Sync *L2Sync) fetchTransactions(batch *SynchronizerBatch) ([]common1.Transactions, error) {
+	blockTxs := make([][]common1.Transactions, len(batch.Headers))
+	group, _ := errgroup.WithContext(l2Sync.resourceCtx)
+	group.SetLimit(l2BlockFetchConcurrency)
+	for i := range batch.Headers {
+		i := i
+		group.Go(func() error {
+			blockHash := batch.Headers[i].Hash()
+			txs, receipts, err := l2Sync.EthClient.TxsWithReceiptsByHash(blockHash)
+			if err != nil {
+				return fmt.Errorf("unable to fetch transactions of block %s: %w", blockHash, err)
+			}
+			blockTxs[i] = make([]common1.Transactions, len(txs))
+			for j := range txs {
+				transaction, err := l2Sync.db.Transactions.BuildTransactions(txs[j], receipts[j])
+				if err != nil {
+					return err
+				}
+				blockTxs[i][j] = transaction
+			}
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+
+	var txList []common1.Transactions
+	for i := range blockTxs {
+		txList = append(txList, blockTxs[i]...)
+	}
+	batch.Logger.Info("fetched l2 batch transactions", "transactions len", len(txList))
+	return txList, nil
+}
+
+// rollback removes every l2 header above the batch's common ancestor, along with all
+// the state derived from them, in a single transaction.
+func (l2Sync *L2Sync) rollback(batch *SynchronizerBatch) error {
+	height := batch.CommonAncestor.Number
 	retryStrategy := &retry.ExponentialStrategy{Min: 1000, Max: 20_000, MaxJitter: 250}
 	if _, err := retry.Do[interface{}](l2Sync.resourceCtx, 10, retryStrategy, func() (interface{}, error) {
 		if err := l2Sync.db.Transaction(func(tx *database.DB) error {
-			if err := tx.Blocks.StoreL2BlockHeaders(l2BlockHeaders); err != nil {
+			// headers go first so that the rollback waits on processors still holding on to them
+			if err := tx.Blocks.RollbackL2BlockHeaders(height); err != nil {
 				return err
 			}
-			if len(l2ContractEvents) > 0 {
-				if err := tx.ContractEvents.StoreL2ContractEvents(l2ContractEvents); err != nil {
-					return err
-				}
+			if err := tx.Transactions.RollbackTransactions(height); err != nil {
+				return err
 			}
-			if len(txList) > 0 {
-				if err := tx.Transactions.StoreTransactions(txList); err != nil {
-					return err
-				}
+			if err := l2Sync.rejectedTransitions(tx.L1ToL2.RollbackL1ToL2Relayed(height)); err != nil {
+				return err
 			}
-			return nil
+			if err := l2Sync.rejectedTransitions(tx.L1ToL2.RollbackL1ToL2DepositReceipts(height)); err != nil {
+				return err
+			}
+			if err := tx.RelayAttempt.RollbackRelayAttempts(common1.SyncCursorLayerL2, height); err != nil {
+				return err
+			}
+			if err := tx.RelayMessage.RollbackRelayMessage(height); err != nil {
+				return err
+			}
+			if err := tx.L2ToL1.RollbackL2ToL1Transactions(height); err != nil {
+				return err
+			}
+			if err := tx.ERC721Bridge.RollbackL2ERC721Bridges(height); err != nil {
+				return err
+			}
+			if err := tx.FeeVault.RollbackFeeVaults(height); err != nil {
+				return err
+			}
+			if err := tx.TokenPair.RollbackTokenPairs(height); err != nil {
+				return err
+			}
+			if err := l2Sync.handlers.Rollback(tx, common1.SyncCursorLayerL2, height); err != nil {
+				return err
+			}
+			return tx.SyncCursors.RollbackSyncCursors(common1.SyncCursorLayerL2, height)
 		}); err != nil {
-			batch.Logger.Error("unable to persist l2 batch", "err", err)
-			return nil, err
+			batch.Logger.Error("unable to rollback l2 state", "err", err)
+			return nil, fmt.Errorf("unable to rollback l2 state: %w", err)
 		}
-		l2Sync.Synchronizer.metrics.RecordIndexedHeaders(len(l2BlockHeaders))
-		l2Sync.Synchronizer.metrics.RecordIndexedLatestHeight(l2BlockHeaders[len(l2BlockHeaders)-1].Number)
+		l2Sync.Synchronizer.metrics.RecordIndexedLatestHeight(height)
 		return nil, nil
 	}); err != nil {
 		return err
 	}
-	batch.Logger.Info("indexed l2 batch")
+	batch.Logger.Info("rolled back l2 state")
 	return nil
 }
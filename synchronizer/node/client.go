@@ -1,22 +1,27 @@
 package node
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"math/big"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/rpc"
 
 	"github.com/ethereum-optimism/optimism/op-service/client"
 	"github.com/ethereum-optimism/optimism/op-service/eth"
 
+	"github.com/mantlenetworkio/lithosphere/common/bigint"
 	"github.com/mantlenetworkio/lithosphere/metrics"
 	"github.com/mantlenetworkio/lithosphere/synchronizer/retry"
 )
@@ -25,8 +30,36 @@ const (
 	defaultDialTimeout    = 5 * time.Second
 	defaultDialAttempts   = 5
 	defaultRequestTimeout = 10 * time.Second
+
+	// defaultReceiptBatchSize bounds the number of receipts requested in a single batch
+	// when the provider does not support `eth_getBlockReceipts`
+	defaultReceiptBatchSize = 100
+
+	// errCodeMethodNotFound is the JSON-RPC error code returned for unsupported methods
+	errCodeMethodNotFound = -32601
+
+	// errCodeExecutionReverted is the JSON-RPC error code returned along with the revert data of a call
+	errCodeExecutionReverted = 3
+
+	// logsRangeGrowthStreak is the number of chunks served at the current `eth_getLogs`
+	// range before attempting to double it
+	logsRangeGrowthStreak = 16
 )
 
+// logsLimitErrors are the error messages hosted providers reply with when an `eth_getLogs`
+// request spans too many blocks or would return too many results
+var logsLimitErrors = []string{
+	"query returned more than",
+	"block range too large",
+	"block range is too large",
+	"exceed maximum block range",
+	"range limit exceeded",
+	"response size exceeded",
+	"response size should not greater than",
+	"too many blocks",
+	"query timeout exceeded",
+}
+
 type rpcBlock struct {
 	types.Header
 	Transactions []*types.Transaction `json:"transactions"`
@@ -68,13 +101,21 @@ type EthClient interface {
 	TxsByNumber(number uint64) (types.Transactions, error)
 	TxDetailByHash(common.Hash) (*types.Transaction, error)
 	TxReceiptDetailByHash(common.Hash) (*types.Receipt, error)
+	TxsWithReceiptsByHash(common.Hash) (types.Transactions, types.Receipts, error)
 
 	StorageHash(common.Address, *big.Int) (common.Hash, error)
+	StorageProof(address common.Address, slot common.Hash, blockNumber *big.Int) (*StorageProof, error)
 	FilterLogs(ethereum.FilterQuery) (Logs, error)
 
 	GetBalanceByBlockNumber(address string, blockNumber *big.Int) (*big.Int, error)
 	GetERC20Balance(contractAddress common.Address, ownerAddress common.Address, blocknumber *big.Int) (*big.Int, error)
 	GetERC20TotalSupply(contract string, blocknumber *big.Int) (*big.Int, error)
+	GetERC20Metadata(contract common.Address) (*ERC20Metadata, error)
+	FinalizationPeriodSeconds(optimismPortal common.Address) (uint64, error)
+
+	// SubscribeNewHead subscribes to notifications about new heads of the chain. This is
+	// only supported when connected over websocket or IPC.
+	SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error)
 
 	// Close closes the underlying RPC connection.
 	// RPC close does not return any errors, but does shut down e.g. a websocket connection.
@@ -83,6 +124,16 @@ type EthClient interface {
 
 type clnt struct {
 	rpc RPC
+
+	// set once the provider rejects `eth_getBlockReceipts`, after which
+	// receipts are always requested with batched `eth_getTransactionReceipt`
+	blockReceiptsUnsupported atomic.Bool
+
+	// largest `eth_getLogs` block range known to work against the provider, 0 when unbounded.
+	// logsRangeStreak counts the chunks of that size served since, the range doubling every
+	// logsRangeGrowthStreak of them so that a transient rejection doesn't shrink it for good
+	logsRange       atomic.Uint64
+	logsRangeStreak atomic.Uint64
 }
 
 func DialEthClient(ctx context.Context, rpcUrl string, metrics metrics.NodeMetricer) (EthClient, error) {
@@ -245,6 +296,78 @@ func (c *clnt) TxReceiptDetailByHash(hash common.Hash) (*types.Receipt, error) {
 	return txReceipt, nil
 }
 
+// TxsWithReceiptsByHash returns the full transaction body of the block along with the receipt
+// of every transaction, in the same order. Receipts are fetched with `eth_getBlockReceipts`,
+// falling back to batched `eth_getTransactionReceipt` calls when the provider doesn't support it.
+func (c *clnt) TxsWithReceiptsByHash(hash common.Hash) (types.Transactions, types.Receipts, error) {
+	ctxwt, cancel := context.WithTimeout(context.Background(), defaultRequestTimeout)
+	defer cancel()
+
+	txs, err := c.blockCall(ctxwt, "eth_getBlockByHash", hashID(hash))
+	if err != nil {
+		return nil, nil, err
+	} else if len(txs) == 0 {
+		return txs, nil, nil
+	}
+
+	var receipts types.Receipts
+	if !c.blockReceiptsUnsupported.Load() {
+		err = c.rpc.CallContext(ctxwt, &receipts, "eth_getBlockReceipts", hash)
+		var rpcErr rpc.Error
+		if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == errCodeMethodNotFound {
+			c.blockReceiptsUnsupported.Store(true)
+		} else if err != nil {
+			return nil, nil, err
+		}
+	}
+	if c.blockReceiptsUnsupported.Load() {
+		receipts, err = c.batchTxReceipts(ctxwt, txs)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if len(receipts) != len(txs) {
+		return nil, nil, fmt.Errorf("block %s has %d transactions but %d receipts", hash, len(txs), len(receipts))
+	}
+	for i := range receipts {
+		if receipts[i] == nil {
+			return nil, nil, fmt.Errorf("missing receipt for tx %s: %w", txs[i].Hash(), ethereum.NotFound)
+		} else if receipts[i].TxHash != txs[i].Hash() || receipts[i].BlockHash != hash {
+			return nil, nil, fmt.Errorf("receipt %d of block %s does not match tx %s", i, hash, txs[i].Hash())
+		}
+	}
+	return txs, receipts, nil
+}
+
+func (c *clnt) batchTxReceipts(ctx context.Context, txs types.Transactions) (types.Receipts, error) {
+	receipts := make(types.Receipts, len(txs))
+	for start := 0; start < len(txs); start += defaultReceiptBatchSize {
+		end := start + defaultReceiptBatchSize
+		if end > len(txs) {
+			end = len(txs)
+		}
+
+		batchElems := make([]rpc.BatchElem, end-start)
+		for i := start; i < end; i++ {
+			batchElems[i-start] = rpc.BatchElem{
+				Method: "eth_getTransactionReceipt",
+				Args:   []interface{}{txs[i].Hash()},
+				Result: &receipts[i],
+			}
+		}
+		if err := c.rpc.BatchCallContext(ctx, batchElems); err != nil {
+			return nil, err
+		}
+		for _, batchElem := range batchElems {
+			if batchElem.Error != nil {
+				return nil, batchElem.Error
+			}
+		}
+	}
+	return receipts, nil
+}
+
 func (c *clnt) StorageHash(address common.Address, blockNumber *big.Int) (common.Hash, error) {
 	ctxwt, cancel := context.WithTimeout(context.Background(), defaultRequestTimeout)
 	defer cancel()
@@ -256,6 +379,40 @@ func (c *clnt) StorageHash(address common.Address, blockNumber *big.Int) (common
 	return proof.StorageHash, nil
 }
 
+// StorageProof is the `eth_getProof` result of a single storage slot of an account
+type StorageProof struct {
+	StorageHash common.Hash
+	Value       *big.Int
+	Proof       []hexutil.Bytes
+}
+
+func (c *clnt) StorageProof(address common.Address, slot common.Hash, blockNumber *big.Int) (*StorageProof, error) {
+	ctxwt, cancel := context.WithTimeout(context.Background(), defaultRequestTimeout)
+	defer cancel()
+	proof := struct {
+		StorageHash  common.Hash
+		StorageProof []struct {
+			Value *hexutil.Big
+			Proof []hexutil.Bytes
+		}
+	}{}
+	err := c.rpc.CallContext(ctxwt, &proof, "eth_getProof", address, []common.Hash{slot}, toBlockNumArg(blockNumber))
+	if err != nil {
+		return nil, err
+	} else if len(proof.StorageProof) != 1 {
+		return nil, fmt.Errorf("expected the proof of 1 storage slot, got %d", len(proof.StorageProof))
+	}
+	return &StorageProof{StorageHash: proof.StorageHash, Value: (*big.Int)(proof.StorageProof[0].Value), Proof: proof.StorageProof[0].Proof}, nil
+}
+
+func (c *clnt) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	sub, err := c.rpc.EthSubscribe(ctx, ch, "newHeads")
+	if err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
 func (c *clnt) Close() {
 	c.rpc.Close()
 }
@@ -265,7 +422,55 @@ type Logs struct {
 	ToBlockHeader *types.Header
 }
 
+// FilterLogs retrieves the logs matching the query along with the `FilterQuery#ToBlock` header. The
+// block range is split into chunks no larger than the range known to work against the provider, and
+// a chunk is bisected whenever the provider rejects it for exceeding its result or range limits.
 func (c *clnt) FilterLogs(query ethereum.FilterQuery) (Logs, error) {
+	if query.BlockHash != nil || query.FromBlock == nil || query.ToBlock == nil {
+		return c.filterLogs(query)
+	}
+
+	var logs []types.Log
+	fromBlock := query.FromBlock
+	for {
+		toBlock := query.ToBlock
+		logsRange := c.logsRange.Load()
+		if logsRange > 0 {
+			toBlock = bigint.Clamp(fromBlock, query.ToBlock, logsRange)
+		}
+
+		chunkQuery := query
+		chunkQuery.FromBlock, chunkQuery.ToBlock = fromBlock, toBlock
+		chunkLogs, err := c.filterLogs(chunkQuery)
+		chunkSize := new(big.Int).Sub(toBlock, fromBlock).Uint64() + 1
+		if err != nil {
+			if !isLogsLimitError(err) || chunkSize == 1 {
+				return Logs{}, err
+			}
+
+			// remember the reduced range so later queries don't have to bisect again. When
+			// a concurrent query already adjusted it the chunk is retried with its range
+			if c.logsRange.CompareAndSwap(logsRange, chunkSize/2) {
+				c.logsRangeStreak.Store(0)
+			}
+			continue
+		}
+
+		if logsRange > 0 && chunkSize == logsRange && c.logsRangeStreak.Add(1) >= logsRangeGrowthStreak {
+			if c.logsRange.CompareAndSwap(logsRange, logsRange*2) {
+				c.logsRangeStreak.Store(0)
+			}
+		}
+
+		logs = append(logs, chunkLogs.Logs...)
+		if toBlock.Cmp(query.ToBlock) == 0 {
+			return Logs{Logs: logs, ToBlockHeader: chunkLogs.ToBlockHeader}, nil
+		}
+		fromBlock = new(big.Int).Add(toBlock, bigint.One)
+	}
+}
+
+func (c *clnt) filterLogs(query ethereum.FilterQuery) (Logs, error) {
 	arg, err := toFilterArg(query)
 	if err != nil {
 		return Logs{}, err
@@ -296,6 +501,28 @@ func (c *clnt) FilterLogs(query ethereum.FilterQuery) (Logs, error) {
 	return Logs{Logs: logs, ToBlockHeader: &header}, nil
 }
 
+// isLogsLimitError reports whether the provider rejected an `eth_getLogs` request because
+// the block range or the number of results exceeded its limits
+func isLogsLimitError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, limitMsg := range logsLimitErrors {
+		if strings.Contains(msg, limitMsg) {
+			return true
+		}
+	}
+	return false
+}
+
+// isExecutionReverted reports whether the node rejected a call because it reverted. The revert
+// reason, when there is one, is appended to the message, e.g. `execution reverted: not implemented`
+func isExecutionReverted(err error) bool {
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == errCodeExecutionReverted {
+		return true
+	}
+	return errors.Is(err, vm.ErrExecutionReverted) || strings.HasPrefix(err.Error(), vm.ErrExecutionReverted.Error())
+}
+
 func (c *clnt) GetBalanceByBlockNumber(address string, blockNumber *big.Int) (*big.Int, error) {
 	var balance *big.Int
 	var err error
@@ -327,7 +554,7 @@ func (c *clnt) GetERC20Balance(contractAddress common.Address, ownerAddress comm
 	defer cancel()
 
 	res, err := c.CallContract(ctxwt, callMsg, blocknumber)
-	if err != nil && err.Error() != "execution reverted" {
+	if err != nil && !isExecutionReverted(err) {
 		return nil, err
 	}
 	balance := new(big.Int).SetBytes(res)
@@ -360,7 +587,7 @@ func (c *clnt) GetERC20TotalSupply(contract string, blocknumber *big.Int) (*big.
 	defer cancel()
 
 	res, err := c.CallContract(ctxwt, callMsg, blocknumber)
-	if err != nil && err.Error() != "execution reverted" {
+	if err != nil && !isExecutionReverted(err) {
 		return nil, err
 	}
 	supply := new(big.Int)
@@ -368,6 +595,96 @@ func (c *clnt) GetERC20TotalSupply(contract string, blocknumber *big.Int) (*big.
 	return supply, nil
 }
 
+// ERC20Metadata is the name, symbol and decimals of an ERC20 token. The values of the optional
+// metadata functions a token does not implement are left empty.
+type ERC20Metadata struct {
+	Name     string
+	Symbol   string
+	Decimals uint8
+}
+
+// GetERC20Metadata reads the metadata of the token at the latest block
+func (c *clnt) GetERC20Metadata(contract common.Address) (*ERC20Metadata, error) {
+	call := func(signature string) ([]byte, error) {
+		callMsg := ethereum.CallMsg{
+			To:   &contract,
+			Data: crypto.Keccak256([]byte(signature))[:4],
+		}
+		ctxwt, cancel := context.WithTimeout(context.Background(), defaultRequestTimeout)
+		defer cancel()
+		res, err := c.CallContract(ctxwt, callMsg, nil)
+		if err != nil && !isExecutionReverted(err) {
+			return nil, err
+		}
+		return res, nil
+	}
+
+	var metadata ERC20Metadata
+	name, err := call("name()")
+	if err != nil {
+		return nil, err
+	}
+	metadata.Name = decodeERC20String(name)
+	symbol, err := call("symbol()")
+	if err != nil {
+		return nil, err
+	}
+	metadata.Symbol = decodeERC20String(symbol)
+	decimals, err := call("decimals()")
+	if err != nil {
+		return nil, err
+	}
+	if len(decimals) == 32 {
+		metadata.Decimals = decimals[31]
+	}
+	return &metadata, nil
+}
+
+// FinalizationPeriodSeconds reads the challenge period of the withdrawals proven to the OptimismPortal from
+// its L2OutputOracle, at the latest block
+func (c *clnt) FinalizationPeriodSeconds(optimismPortal common.Address) (uint64, error) {
+	call := func(contract common.Address, signature string) ([]byte, error) {
+		callMsg := ethereum.CallMsg{
+			To:   &contract,
+			Data: crypto.Keccak256([]byte(signature))[:4],
+		}
+		ctxwt, cancel := context.WithTimeout(context.Background(), defaultRequestTimeout)
+		defer cancel()
+		res, err := c.CallContract(ctxwt, callMsg, nil)
+		if err != nil {
+			return nil, err
+		} else if len(res) != 32 {
+			return nil, fmt.Errorf("unexpected result of %s on %s: %x", signature, contract, res)
+		}
+		return res, nil
+	}
+
+	oracle, err := call(optimismPortal, "L2_ORACLE()")
+	if err != nil {
+		return 0, err
+	}
+	period, err := call(common.BytesToAddress(oracle), "FINALIZATION_PERIOD_SECONDS()")
+	if err != nil {
+		return 0, err
+	}
+	return new(big.Int).SetBytes(period).Uint64(), nil
+}
+
+// decodeERC20String decodes a string returned by an ERC20 metadata function. Some early tokens
+// return a null padded bytes32 instead of an abi encoded string.
+func decodeERC20String(res []byte) string {
+	if len(res) == 32 {
+		return string(bytes.TrimRight(res, "\x00"))
+	} else if len(res) < 64 {
+		return ""
+	}
+	offset, length := new(big.Int).SetBytes(res[:32]), new(big.Int).SetBytes(res[32:64])
+	if offset.Cmp(big.NewInt(32)) != 0 || length.Cmp(big.NewInt(int64(len(res)-64))) > 0 {
+		return ""
+	}
+	return string(res[64 : 64+length.Int64()])
+}
+
 // Modeled off op-service/client.go. We can refactor this once the client/metrics portion
 // of op-service/client has been generalized
 
@@ -375,6 +692,7 @@ type RPC interface {
 	Close()
 	CallContext(ctx context.Context, result any, method string, args ...any) error
 	BatchCallContext(ctx context.Context, b []rpc.BatchElem) error
+	EthSubscribe(ctx context.Context, channel any, args ...any) (*rpc.ClientSubscription, error)
 }
 
 type rpcClient struct {
@@ -391,15 +709,23 @@ func (c *rpcClient) Close() {
 }
 
 func (c *rpcClient) CallContext(ctx context.Context, result any, method string, args ...any) error {
+	record := c.metrics.RecordRPCClientRequest(method)
 	err := c.rpc.CallContext(ctx, result, method, args...)
+	record(err)
 	return err
 }
 
 func (c *rpcClient) BatchCallContext(ctx context.Context, b []rpc.BatchElem) error {
+	record := c.metrics.RecordRPCClientBatchRequest(b)
 	err := c.rpc.BatchCallContext(ctx, b)
+	record(err)
 	return err
 }
 
+func (c *rpcClient) EthSubscribe(ctx context.Context, channel any, args ...any) (*rpc.ClientSubscription, error) {
+	return c.rpc.EthSubscribe(ctx, channel, args...)
+}
+
 func toBlockNumArg(number *big.Int) string {
 	if number == nil {
 		return "latest"
@@ -0,0 +1,106 @@
+package node
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/mantlenetworkio/lithosphere/metrics"
+)
+
+// rangeLimitedEthService serves a log per block, rejecting `eth_getLogs` requests spanning more than limit blocks
+type rangeLimitedEthService struct {
+	mu     sync.Mutex
+	limit  uint64
+	ranges [][2]uint64
+}
+
+func (s *rangeLimitedEthService) GetBlockByNumber(number string, _ bool) (*types.Header, error) {
+	height, err := hexutil.DecodeUint64(number)
+	if err != nil {
+		return nil, err
+	}
+	return &types.Header{Number: new(big.Int).SetUint64(height), Difficulty: common.Big0}, nil
+}
+
+func (s *rangeLimitedEthService) GetLogs(arg struct{ FromBlock, ToBlock hexutil.Uint64 }) ([]types.Log, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	from, to := uint64(arg.FromBlock), uint64(arg.ToBlock)
+	s.ranges = append(s.ranges, [2]uint64{from, to})
+	if to-from+1 > s.limit {
+		return nil, errors.New("block range too large")
+	}
+
+	logs := make([]types.Log, 0, to-from+1)
+	for height := from; height <= to; height++ {
+		logs = append(logs, types.Log{BlockNumber: height, Topics: []common.Hash{}})
+	}
+	return logs, nil
+}
+
+func (s *rangeLimitedEthService) setLimit(limit uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.limit, s.ranges = limit, nil
+}
+
+func TestFilterLogsBisectsRange(t *testing.T) {
+	service := &rangeLimitedEthService{limit: 10}
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", service))
+	defer server.Stop()
+
+	nodeMetrics := metrics.NewNodeMetrics(prometheus.NewRegistry(), "test")
+	client := &clnt{rpc: NewRPC(rpc.DialInProc(server), nodeMetrics)}
+	defer client.Close()
+
+	filterLogs := func(from, to int64) {
+		logs, err := client.FilterLogs(ethereum.FilterQuery{FromBlock: big.NewInt(from), ToBlock: big.NewInt(to)})
+		require.NoError(t, err)
+		require.Len(t, logs.Logs, int(to-from+1))
+		for i, log := range logs.Logs {
+			require.Equal(t, uint64(from)+uint64(i), log.BlockNumber)
+		}
+		require.Equal(t, big.NewInt(to), logs.ToBlockHeader.Number)
+	}
+
+	// 64 -> 32 -> 16 -> 8 blocks
+	filterLogs(0, 63)
+	require.Equal(t, uint64(8), client.logsRange.Load())
+	require.Equal(t, [][2]uint64{{0, 63}, {0, 31}, {0, 15}, {0, 7}, {8, 15}}, service.ranges[:5])
+
+	// later queries start off with the reduced range
+	service.setLimit(10)
+	filterLogs(100, 115)
+	require.Equal(t, [][2]uint64{{100, 107}, {108, 115}}, service.ranges)
+
+	// the range doubles once the provider kept serving it, 10 chunks have been served so far
+	service.setLimit(1000)
+	filterLogs(200, 200+8*(logsRangeGrowthStreak-10)-1)
+	require.Equal(t, uint64(16), client.logsRange.Load())
+	require.Equal(t, [2]uint64{200 + 8*(logsRangeGrowthStreak-11), 200 + 8*(logsRangeGrowthStreak-10) - 1}, service.ranges[len(service.ranges)-1])
+
+	// a single block rejected by the provider can't be bisected any further
+	service.setLimit(0)
+	_, err := client.FilterLogs(ethereum.FilterQuery{FromBlock: big.NewInt(0), ToBlock: big.NewInt(3)})
+	require.ErrorContains(t, err, "block range too large")
+}
+
+func TestIsExecutionReverted(t *testing.T) {
+	require.True(t, isExecutionReverted(errors.New("execution reverted")))
+	require.True(t, isExecutionReverted(errors.New("execution reverted: ERC20: name not implemented")))
+	require.True(t, isExecutionReverted(&FixtureError{Code: errCodeExecutionReverted, Message: "reverted"}))
+	require.False(t, isExecutionReverted(errors.New("invalid opcode: INVALID")))
+	require.False(t, isExecutionReverted(&FixtureError{Code: -32000, Message: "header not found"}))
+}
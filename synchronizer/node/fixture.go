@@ -0,0 +1,215 @@
+package node
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/mantlenetworkio/lithosphere/metrics"
+)
+
+// Fixture is the set of JSON-RPC requests and responses captured by a Recorder.
+type Fixture struct {
+	Calls []FixtureCall `json:"calls"`
+}
+
+type FixtureCall struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *FixtureError   `json:"error,omitempty"`
+}
+
+// FixtureError is a JSON-RPC error returned by the node, replayed as an rpc.Error
+type FixtureError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *FixtureError) Error() string  { return e.Message }
+func (e *FixtureError) ErrorCode() int { return e.Code }
+
+func fixtureKey(method string, params json.RawMessage) string {
+	return method + string(params)
+}
+
+// Recorder is an RPC decorator capturing every request made through it along with the
+// node's response, so that they can be saved as a fixture and replayed offline.
+type Recorder struct {
+	rpc RPC
+
+	mu      sync.Mutex
+	fixture Fixture
+}
+
+// DialRecordingEthClient dials the endpoint like DialEthClient and returns the Recorder
+// capturing every call made through the returned EthClient.
+func DialRecordingEthClient(ctx context.Context, rpcUrl string, metrics metrics.NodeMetricer) (EthClient, *Recorder, error) {
+	client, err := DialEthClient(ctx, rpcUrl, metrics)
+	if err != nil {
+		return nil, nil, err
+	}
+	recorder := NewRecorder(client.(*clnt).rpc)
+	return &clnt{rpc: recorder}, recorder, nil
+}
+
+func NewRecorder(rpc RPC) *Recorder {
+	return &Recorder{rpc: rpc}
+}
+
+func (r *Recorder) Close() {
+	r.rpc.Close()
+}
+
+func (r *Recorder) CallContext(ctx context.Context, result any, method string, args ...any) error {
+	var raw json.RawMessage
+	err := r.rpc.CallContext(ctx, &raw, method, args...)
+	if recordErr := r.record(method, args, raw, err); recordErr != nil {
+		return recordErr
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, result)
+}
+
+func (r *Recorder) BatchCallContext(ctx context.Context, b []rpc.BatchElem) error {
+	raws := make([]json.RawMessage, len(b))
+	recorded := make([]rpc.BatchElem, len(b))
+	for i := range b {
+		recorded[i] = rpc.BatchElem{Method: b[i].Method, Args: b[i].Args, Result: &raws[i]}
+	}
+	if err := r.rpc.BatchCallContext(ctx, recorded); err != nil {
+		return err
+	}
+
+	for i := range b {
+		if err := r.record(b[i].Method, b[i].Args, raws[i], recorded[i].Error); err != nil {
+			return err
+		}
+		b[i].Error = recorded[i].Error
+		if b[i].Error == nil {
+			b[i].Error = json.Unmarshal(raws[i], b[i].Result)
+		}
+	}
+	return nil
+}
+
+// EthSubscribe is passed through, subscriptions can't be captured in a fixture
+func (r *Recorder) EthSubscribe(ctx context.Context, channel any, args ...any) (*rpc.ClientSubscription, error) {
+	return r.rpc.EthSubscribe(ctx, channel, args...)
+}
+
+func (r *Recorder) record(method string, args []any, raw json.RawMessage, err error) error {
+	params, marshalErr := json.Marshal(args)
+	if marshalErr != nil {
+		return fmt.Errorf("unable to record params of %s: %w", method, marshalErr)
+	}
+
+	call := FixtureCall{Method: method, Params: params}
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		call.Error = &FixtureError{Code: rpcErr.ErrorCode(), Message: rpcErr.Error()}
+	} else if err != nil {
+		// transport errors say nothing about the node's state and are not replayed
+		return nil
+	} else {
+		call.Result = raw
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fixture.Calls = append(r.fixture.Calls, call)
+	return nil
+}
+
+// Save writes every call recorded so far to the fixture file at path
+func (r *Recorder) Save(path string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	data, err := json.MarshalIndent(r.fixture, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// replayRPC serves the responses of a fixture. When the same request was recorded several
+// times the responses are served in the recorded order, repeating the last one once exhausted.
+type replayRPC struct {
+	mu        sync.Mutex
+	responses map[string][]FixtureCall
+}
+
+// NewReplayEthClient returns an EthClient serving the requests recorded in the fixture
+// file at path, without any network access. Requests that weren't recorded fail.
+func NewReplayEthClient(path string) (EthClient, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var fixture Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("unable to decode fixture %s: %w", path, err)
+	}
+
+	replay := &replayRPC{responses: make(map[string][]FixtureCall)}
+	for _, call := range fixture.Calls {
+		// params may have been re-indented when the fixture was saved
+		var params bytes.Buffer
+		if err := json.Compact(&params, call.Params); err != nil {
+			return nil, fmt.Errorf("unable to decode fixture %s: %w", path, err)
+		}
+		key := fixtureKey(call.Method, params.Bytes())
+		replay.responses[key] = append(replay.responses[key], call)
+	}
+	return &clnt{rpc: replay}, nil
+}
+
+func (r *replayRPC) Close() {}
+
+func (r *replayRPC) CallContext(_ context.Context, result any, method string, args ...any) error {
+	call, err := r.next(method, args)
+	if err != nil {
+		return err
+	} else if call.Error != nil {
+		return call.Error
+	}
+	return json.Unmarshal(call.Result, result)
+}
+
+func (r *replayRPC) BatchCallContext(ctx context.Context, b []rpc.BatchElem) error {
+	for i := range b {
+		b[i].Error = r.CallContext(ctx, b[i].Result, b[i].Method, b[i].Args...)
+	}
+	return nil
+}
+
+func (r *replayRPC) EthSubscribe(context.Context, any, ...any) (*rpc.ClientSubscription, error) {
+	return nil, rpc.ErrNotificationsUnsupported
+}
+
+func (r *replayRPC) next(method string, args []any) (FixtureCall, error) {
+	params, err := json.Marshal(args)
+	if err != nil {
+		return FixtureCall{}, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := fixtureKey(method, params)
+	calls := r.responses[key]
+	if len(calls) == 0 {
+		return FixtureCall{}, fmt.Errorf("no recorded response for %s %s", method, params)
+	}
+	if len(calls) > 1 {
+		r.responses[key] = calls[1:]
+	}
+	return calls[0], nil
+}
@@ -0,0 +1,84 @@
+package node
+
+import (
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/mantlenetworkio/lithosphere/metrics"
+)
+
+type fakeEthService struct {
+	headers []*types.Header
+}
+
+func (s *fakeEthService) GetBlockByNumber(number string, _ bool) (*types.Header, error) {
+	if number == "latest" {
+		return s.headers[len(s.headers)-1], nil
+	}
+	height, err := hexutil.DecodeUint64(number)
+	if err != nil {
+		return nil, err
+	} else if height >= uint64(len(s.headers)) {
+		return nil, nil
+	}
+	return s.headers[height], nil
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	headers := make([]*types.Header, 3)
+	for i := range headers {
+		headers[i] = &types.Header{Number: big.NewInt(int64(i)), Difficulty: common.Big0}
+		if i > 0 {
+			headers[i].ParentHash = headers[i-1].Hash()
+		}
+	}
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", &fakeEthService{headers}))
+	defer server.Stop()
+
+	nodeMetrics := metrics.NewNodeMetrics(prometheus.NewRegistry(), "test")
+	recorder := NewRecorder(NewRPC(rpc.DialInProc(server), nodeMetrics))
+	recording := &clnt{rpc: recorder}
+
+	recordedRange, err := recording.BlockHeadersByRange(big.NewInt(0), big.NewInt(2))
+	require.NoError(t, err)
+	require.Len(t, recordedRange, 3)
+	recordedLatest, err := recording.BlockHeaderByNumber(nil)
+	require.NoError(t, err)
+	require.Equal(t, headers[2].Hash(), recordedLatest.Hash())
+	_, err = recording.BlockHeaderByNumber(big.NewInt(10))
+	require.ErrorIs(t, err, ethereum.NotFound)
+
+	fixturePath := filepath.Join(t.TempDir(), "fixture.json")
+	require.NoError(t, recorder.Save(fixturePath))
+	recording.Close()
+
+	replay, err := NewReplayEthClient(fixturePath)
+	require.NoError(t, err)
+
+	replayedRange, err := replay.BlockHeadersByRange(big.NewInt(0), big.NewInt(2))
+	require.NoError(t, err)
+	for i := range replayedRange {
+		require.Equal(t, recordedRange[i].Hash(), replayedRange[i].Hash())
+	}
+	replayedLatest, err := replay.BlockHeaderByNumber(nil)
+	require.NoError(t, err)
+	require.Equal(t, recordedLatest.Hash(), replayedLatest.Hash())
+	_, err = replay.BlockHeaderByNumber(big.NewInt(10))
+	require.ErrorIs(t, err, ethereum.NotFound)
+
+	// requests that weren't recorded can't be served
+	_, err = replay.BlockHeaderByHash(headers[0].Hash())
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ethereum.NotFound)
+}
@@ -12,10 +12,36 @@ import (
 
 var (
 	ErrHeaderTraversalAheadOfProvider = errors.New("the HeaderTraversal's internal state is ahead of the provider")
+	ErrHeaderTraversalReorg           = errors.New("the HeaderTraversal detected a reorg of the last traversed header")
 )
 
+// TraversalMode selects which head of the provider's chain the HeaderTraversal follows.
+type TraversalMode string
+
+const (
+	// TraversalModeLatest follows the latest block minus the configured confirmation depth
+	TraversalModeLatest TraversalMode = "latest"
+	// TraversalModeSafe follows the `safe` block reported by the provider
+	TraversalModeSafe TraversalMode = "safe"
+	// TraversalModeFinalized follows the `finalized` block reported by the provider
+	TraversalModeFinalized TraversalMode = "finalized"
+)
+
+// ParseTraversalMode validates the supplied mode, defaulting to TraversalModeLatest when empty.
+func ParseTraversalMode(mode string) (TraversalMode, error) {
+	switch TraversalMode(mode) {
+	case "", TraversalModeLatest:
+		return TraversalModeLatest, nil
+	case TraversalModeSafe, TraversalModeFinalized:
+		return TraversalMode(mode), nil
+	default:
+		return "", fmt.Errorf("unknown traversal mode %q, expected one of latest, safe or finalized", mode)
+	}
+}
+
 type HeaderTraversal struct {
 	ethClient EthClient
+	mode      TraversalMode
 
 	latestHeader        *types.Header
 	lastTraversedHeader *types.Header
@@ -25,16 +51,26 @@ type HeaderTraversal struct {
 
 // NewHeaderTraversal instantiates a new instance of HeaderTraversal against the supplied rpc client.
 // The HeaderTraversal will start fetching blocks starting from the supplied header unless nil, indicating genesis.
-func NewHeaderTraversal(ethClient EthClient, fromHeader *types.Header, confDepth *big.Int) *HeaderTraversal {
+// The confirmation depth is only applied in TraversalModeLatest, the safe and finalized heads are followed as is.
+func NewHeaderTraversal(ethClient EthClient, fromHeader *types.Header, confDepth *big.Int, mode TraversalMode) *HeaderTraversal {
+	if mode != TraversalModeLatest {
+		confDepth = big.NewInt(0)
+	}
 	return &HeaderTraversal{
 		ethClient:              ethClient,
+		mode:                   mode,
 		lastTraversedHeader:    fromHeader,
 		blockConfirmationDepth: confDepth,
 	}
 }
 
-// LatestHeader returns the latest header reported by underlying eth client
-// as headers are traversed via `NextHeaders`.
+// Mode returns the TraversalMode the HeaderTraversal follows.
+func (f *HeaderTraversal) Mode() TraversalMode {
+	return f.mode
+}
+
+// LatestHeader returns the head reported by underlying eth client for the
+// configured TraversalMode as headers are traversed via `NextHeaders`.
 func (f *HeaderTraversal) LatestHeader() *types.Header {
 	return f.latestHeader
 }
@@ -48,11 +84,13 @@ func (f *HeaderTraversal) LastTraversedHeader() *types.Header {
 }
 
 // NextHeaders retrieves the next set of headers that have been
-// marked as finalized by the connected client, bounded by the supplied size
+// marked as finalized by the connected client, bounded by the supplied size.
+// ErrHeaderTraversalAheadOfProvider is returned while the provider's head is below
+// the last traversed header, the provider either lags behind or reorged to a shorter chain.
 func (f *HeaderTraversal) NextHeaders(maxSize uint64) ([]types.Header, error) {
-	latestHeader, err := f.ethClient.BlockHeaderByNumber(nil)
+	latestHeader, err := f.headHeader()
 	if err != nil {
-		return nil, fmt.Errorf("unable to query latest block: %w", err)
+		return nil, fmt.Errorf("unable to query %s block: %w", f.mode, err)
 	} else if latestHeader == nil {
 		return nil, fmt.Errorf("latest header unreported")
 	} else {
@@ -89,8 +127,30 @@ func (f *HeaderTraversal) NextHeaders(maxSize uint64) ([]types.Header, error) {
 	numHeaders := len(headers)
 	if numHeaders == 0 {
 		return nil, nil
+	} else if f.lastTraversedHeader != nil && headers[0].ParentHash != f.lastTraversedHeader.Hash() {
+		// The provider's canonical chain no longer builds on top of what has been traversed. The
+		// caller is expected to locate the common ancestor and `Rewind` before traversing further
+		return nil, ErrHeaderTraversalReorg
 	}
 
 	f.lastTraversedHeader = &headers[numHeaders-1]
 	return headers, nil
 }
+
+// headHeader queries the head of the chain for the configured TraversalMode
+func (f *HeaderTraversal) headHeader() (*types.Header, error) {
+	switch f.mode {
+	case TraversalModeSafe:
+		return f.ethClient.LatestSafeBlockHeader()
+	case TraversalModeFinalized:
+		return f.ethClient.LatestFinalizedBlockHeader()
+	default:
+		return f.ethClient.BlockHeaderByNumber(nil)
+	}
+}
+
+// Rewind resets the traversal to continue from the supplied header. This is used
+// to resume from the common ancestor after a reorg has been detected.
+func (f *HeaderTraversal) Rewind(header *types.Header) {
+	f.lastTraversedHeader = header
+}
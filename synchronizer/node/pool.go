@@ -0,0 +1,445 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/mantlenetworkio/lithosphere/metrics"
+)
+
+const (
+	endpointHealthCheckInterval = 10 * time.Second
+
+	// endpointMaxHeadLag is the number of blocks an endpoint may trail the best
+	// head known to the pool before it is considered unhealthy
+	endpointMaxHeadLag = 8
+
+	// endpointMaxErrorRate is the decayed error rate above which an endpoint is considered unhealthy
+	endpointMaxErrorRate = 0.5
+
+	// endpointHealthDecay is the weight of the latest observation in the decayed error rate and latency
+	endpointHealthDecay = 0.1
+
+	// endpointLagPenalty is the latency, in seconds, each block of head lag weighs in the endpoint score
+	endpointLagPenalty = 0.5
+
+	// errCodeLimitExceeded is the JSON-RPC error code returned by rate limited providers
+	errCodeLimitExceeded = -32005
+)
+
+// endpointHealth tracks the error rate and latency of the requests made to a single endpoint by
+// decorating its NodeMetricer, along with the head lag observed by the pool's health checks.
+type endpointHealth struct {
+	metrics.NodeMetricer
+
+	mu        sync.Mutex
+	errorRate float64
+	latency   float64
+	headLag   uint64
+	down      bool
+}
+
+func (h *endpointHealth) RecordRPCClientRequest(method string) func(err error) {
+	record := h.NodeMetricer.RecordRPCClientRequest(method)
+	start := time.Now()
+	return func(err error) {
+		record(err)
+		h.observe(time.Since(start), err)
+	}
+}
+
+func (h *endpointHealth) RecordRPCClientBatchRequest(b []rpc.BatchElem) func(err error) {
+	record := h.NodeMetricer.RecordRPCClientBatchRequest(b)
+	start := time.Now()
+	return func(err error) {
+		record(err)
+		h.observe(time.Since(start), err)
+	}
+}
+
+func (h *endpointHealth) observe(elapsed time.Duration, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	failure := 0.0
+	if isEndpointError(err) {
+		failure = 1
+	}
+	h.errorRate = (1-endpointHealthDecay)*h.errorRate + endpointHealthDecay*failure
+	h.latency = (1-endpointHealthDecay)*h.latency + endpointHealthDecay*elapsed.Seconds()
+}
+
+// status returns whether the endpoint is healthy and its score, lower being better
+func (h *endpointHealth) status() (bool, float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	healthy := !h.down && h.errorRate < endpointMaxErrorRate && h.headLag <= endpointMaxHeadLag
+	score := h.latency*(1+10*h.errorRate) + float64(h.headLag)*endpointLagPenalty
+	return healthy, score
+}
+
+type endpoint struct {
+	name   string
+	client EthClient
+	health *endpointHealth
+}
+
+// clientPool is an EthClient routing every call to the healthiest of several endpoints
+// serving the same chain, failing over to the next one when an endpoint fails to respond.
+type clientPool struct {
+	log       log.Logger
+	metrics   metrics.NodeMetricer
+	endpoints []*endpoint
+	quorum    int
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// DialEthClientPool dials every supplied endpoint and returns an EthClient spreading calls across
+// them. Endpoints that cannot be dialed are skipped. When quorum is greater than one, the latest,
+// safe and finalized heads are only reported once that many endpoints agree on the head hash.
+func DialEthClientPool(ctx context.Context, log log.Logger, rpcUrls []string, quorum uint, metrics metrics.NodeMetricer) (EthClient, error) {
+	if len(rpcUrls) == 0 {
+		return nil, errors.New("no rpc endpoints supplied")
+	} else if len(rpcUrls) == 1 && quorum <= 1 {
+		return DialEthClient(ctx, rpcUrls[0], metrics)
+	}
+
+	pool := &clientPool{log: log, metrics: metrics, quorum: int(quorum)}
+	for _, rpcUrl := range rpcUrls {
+		name := endpointName(rpcUrl)
+		health := &endpointHealth{NodeMetricer: metrics}
+		client, err := DialEthClient(ctx, rpcUrl, health)
+		if err != nil {
+			log.Error("unable to dial rpc endpoint, skipping it", "endpoint", name, "err", err)
+			continue
+		}
+		pool.endpoints = append(pool.endpoints, &endpoint{name: name, client: client, health: health})
+	}
+	if len(pool.endpoints) == 0 {
+		return nil, errors.New("unable to dial any of the rpc endpoints")
+	} else if pool.quorum > len(pool.endpoints) {
+		pool.Close()
+		return nil, fmt.Errorf("quorum of %d can't be reached with %d reachable endpoints", pool.quorum, len(pool.endpoints))
+	}
+
+	checkCtx, cancel := context.WithCancel(context.Background())
+	pool.cancel = cancel
+	pool.wg.Add(1)
+	go pool.checkHealth(checkCtx)
+	return pool, nil
+}
+
+// endpointName strips everything but the host from the url so that api keys don't end up in logs or metrics
+func endpointName(rpcUrl string) string {
+	parsed, err := url.Parse(rpcUrl)
+	if err != nil || parsed.Host == "" {
+		return "unknown"
+	}
+	return parsed.Host
+}
+
+// isEndpointError reports whether err was caused by the endpoint rather than being a valid
+// response to the request, such as a missing block or a reverted call.
+func isEndpointError(err error) bool {
+	if err == nil || errors.Is(err, ethereum.NotFound) {
+		return false
+	}
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		return rpcErr.ErrorCode() == errCodeLimitExceeded
+	}
+	return true
+}
+
+// ranked returns the endpoints ordered by preference. Unhealthy endpoints are kept
+// at the back so they are still tried when every other endpoint has failed.
+func (p *clientPool) ranked() []*endpoint {
+	type rankedEndpoint struct {
+		*endpoint
+		healthy bool
+		score   float64
+	}
+	ranking := make([]rankedEndpoint, len(p.endpoints))
+	for i, e := range p.endpoints {
+		healthy, score := e.health.status()
+		ranking[i] = rankedEndpoint{e, healthy, score}
+	}
+	sort.SliceStable(ranking, func(i, j int) bool {
+		if ranking[i].healthy != ranking[j].healthy {
+			return ranking[i].healthy
+		}
+		return ranking[i].score < ranking[j].score
+	})
+
+	endpoints := make([]*endpoint, len(ranking))
+	for i := range ranking {
+		endpoints[i] = ranking[i].endpoint
+	}
+	return endpoints
+}
+
+// poolCall runs fn against the endpoints in order of preference, failing over
+// to the next one whenever an endpoint fails to serve the request
+func poolCall[T any](p *clientPool, fn func(EthClient) (T, error)) (T, error) {
+	var errs error
+	for _, e := range p.ranked() {
+		result, err := fn(e.client)
+		if !isEndpointError(err) {
+			return result, err
+		}
+		p.log.Warn("rpc endpoint failed, failing over", "endpoint", e.name, "err", err)
+		errs = errors.Join(errs, fmt.Errorf("%s: %w", e.name, err))
+	}
+	var empty T
+	return empty, errs
+}
+
+// checkHealth periodically queries the head of every endpoint to detect
+// endpoints that are down or trailing the rest of the pool
+func (p *clientPool) checkHealth(ctx context.Context) {
+	defer p.wg.Done()
+	ticker := time.NewTicker(endpointHealthCheckInterval)
+	defer ticker.Stop()
+	for {
+		p.updateHeadLag()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *clientPool) updateHeadLag() {
+	heads := p.heads(func(client EthClient) (*types.Header, error) {
+		return client.BlockHeaderByNumber(nil)
+	})
+
+	bestHead := uint64(0)
+	for _, head := range heads {
+		if head != nil && head.Number.Uint64() > bestHead {
+			bestHead = head.Number.Uint64()
+		}
+	}
+	for i, e := range p.endpoints {
+		e.health.mu.Lock()
+		e.health.down = heads[i] == nil
+		if heads[i] != nil {
+			e.health.headLag = bestHead - heads[i].Number.Uint64()
+		}
+		headLag := e.health.headLag
+		e.health.mu.Unlock()
+
+		healthy, _ := e.health.status()
+		p.metrics.RecordEndpointHealth(e.name, healthy, headLag)
+	}
+}
+
+// heads queries every endpoint concurrently. The result is indexed like
+// the pool's endpoints, with nil for endpoints that failed to respond.
+func (p *clientPool) heads(head func(EthClient) (*types.Header, error)) []*types.Header {
+	heads := make([]*types.Header, len(p.endpoints))
+	var wg sync.WaitGroup
+	for i := range p.endpoints {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			header, err := head(p.endpoints[i].client)
+			if err != nil {
+				p.log.Warn("unable to query rpc endpoint head", "endpoint", p.endpoints[i].name, "err", err)
+				return
+			}
+			heads[i] = header
+		}(i)
+	}
+	wg.Wait()
+	return heads
+}
+
+// quorumHead returns the highest header reported by enough endpoints to reach the quorum,
+// provided that they agree on its hash.
+func (p *clientPool) quorumHead(head func(EthClient) (*types.Header, error)) (*types.Header, error) {
+	if p.quorum <= 1 {
+		return poolCall(p, head)
+	}
+
+	heads := p.heads(head)
+	var reported []*types.Header
+	for _, header := range heads {
+		if header != nil {
+			reported = append(reported, header)
+		}
+	}
+	if len(reported) < p.quorum {
+		return nil, fmt.Errorf("only %d endpoints reported a head, quorum of %d required", len(reported), p.quorum)
+	}
+
+	// the quorum-th highest head is the highest height enough endpoints have reached
+	sort.Slice(reported, func(i, j int) bool { return reported[i].Number.Cmp(reported[j].Number) > 0 })
+	height := reported[p.quorum-1].Number
+
+	atHeight := p.heads(func(client EthClient) (*types.Header, error) {
+		return client.BlockHeaderByNumber(height)
+	})
+	votes := make(map[common.Hash]int)
+	for _, header := range atHeight {
+		if header == nil {
+			continue
+		}
+		votes[header.Hash()]++
+		if votes[header.Hash()] >= p.quorum {
+			return header, nil
+		}
+	}
+	return nil, fmt.Errorf("endpoints do not agree on the head hash at height %d", height)
+}
+
+func (p *clientPool) BlockHeaderByNumber(number *big.Int) (*types.Header, error) {
+	if number == nil {
+		return p.quorumHead(func(client EthClient) (*types.Header, error) {
+			return client.BlockHeaderByNumber(nil)
+		})
+	}
+	return poolCall(p, func(client EthClient) (*types.Header, error) {
+		return client.BlockHeaderByNumber(number)
+	})
+}
+
+func (p *clientPool) LatestSafeBlockHeader() (*types.Header, error) {
+	return p.quorumHead(func(client EthClient) (*types.Header, error) {
+		return client.LatestSafeBlockHeader()
+	})
+}
+
+func (p *clientPool) LatestFinalizedBlockHeader() (*types.Header, error) {
+	return p.quorumHead(func(client EthClient) (*types.Header, error) {
+		return client.LatestFinalizedBlockHeader()
+	})
+}
+
+func (p *clientPool) BlockHeaderByHash(hash common.Hash) (*types.Header, error) {
+	return poolCall(p, func(client EthClient) (*types.Header, error) {
+		return client.BlockHeaderByHash(hash)
+	})
+}
+
+func (p *clientPool) BlockHeadersByRange(startHeight, endHeight *big.Int) ([]types.Header, error) {
+	return poolCall(p, func(client EthClient) ([]types.Header, error) {
+		return client.BlockHeadersByRange(startHeight, endHeight)
+	})
+}
+
+func (p *clientPool) TxsByHash(hash common.Hash) (types.Transactions, error) {
+	return poolCall(p, func(client EthClient) (types.Transactions, error) {
+		return client.TxsByHash(hash)
+	})
+}
+
+func (p *clientPool) TxsByNumber(number uint64) (types.Transactions, error) {
+	return poolCall(p, func(client EthClient) (types.Transactions, error) {
+		return client.TxsByNumber(number)
+	})
+}
+
+func (p *clientPool) TxDetailByHash(hash common.Hash) (*types.Transaction, error) {
+	return poolCall(p, func(client EthClient) (*types.Transaction, error) {
+		return client.TxDetailByHash(hash)
+	})
+}
+
+func (p *clientPool) TxReceiptDetailByHash(hash common.Hash) (*types.Receipt, error) {
+	return poolCall(p, func(client EthClient) (*types.Receipt, error) {
+		return client.TxReceiptDetailByHash(hash)
+	})
+}
+
+func (p *clientPool) TxsWithReceiptsByHash(hash common.Hash) (types.Transactions, types.Receipts, error) {
+	type txsWithReceipts struct {
+		txs      types.Transactions
+		receipts types.Receipts
+	}
+	result, err := poolCall(p, func(client EthClient) (txsWithReceipts, error) {
+		txs, receipts, err := client.TxsWithReceiptsByHash(hash)
+		return txsWithReceipts{txs, receipts}, err
+	})
+	return result.txs, result.receipts, err
+}
+
+func (p *clientPool) StorageHash(address common.Address, blockNumber *big.Int) (common.Hash, error) {
+	return poolCall(p, func(client EthClient) (common.Hash, error) {
+		return client.StorageHash(address, blockNumber)
+	})
+}
+
+func (p *clientPool) StorageProof(address common.Address, slot common.Hash, blockNumber *big.Int) (*StorageProof, error) {
+	return poolCall(p, func(client EthClient) (*StorageProof, error) {
+		return client.StorageProof(address, slot, blockNumber)
+	})
+}
+
+func (p *clientPool) FilterLogs(query ethereum.FilterQuery) (Logs, error) {
+	return poolCall(p, func(client EthClient) (Logs, error) {
+		return client.FilterLogs(query)
+	})
+}
+
+func (p *clientPool) GetBalanceByBlockNumber(address string, blockNumber *big.Int) (*big.Int, error) {
+	return poolCall(p, func(client EthClient) (*big.Int, error) {
+		return client.GetBalanceByBlockNumber(address, blockNumber)
+	})
+}
+
+func (p *clientPool) GetERC20Balance(contractAddress common.Address, ownerAddress common.Address, blocknumber *big.Int) (*big.Int, error) {
+	return poolCall(p, func(client EthClient) (*big.Int, error) {
+		return client.GetERC20Balance(contractAddress, ownerAddress, blocknumber)
+	})
+}
+
+func (p *clientPool) GetERC20TotalSupply(contract string, blocknumber *big.Int) (*big.Int, error) {
+	return poolCall(p, func(client EthClient) (*big.Int, error) {
+		return client.GetERC20TotalSupply(contract, blocknumber)
+	})
+}
+
+func (p *clientPool) GetERC20Metadata(contract common.Address) (*ERC20Metadata, error) {
+	return poolCall(p, func(client EthClient) (*ERC20Metadata, error) {
+		return client.GetERC20Metadata(contract)
+	})
+}
+
+func (p *clientPool) FinalizationPeriodSeconds(optimismPortal common.Address) (uint64, error) {
+	return poolCall(p, func(client EthClient) (uint64, error) {
+		return client.FinalizationPeriodSeconds(optimismPortal)
+	})
+}
+
+// SubscribeNewHead subscribes through the healthiest endpoint. When that endpoint dies the
+// subscription errors and the caller re-subscribing is routed to the next healthy endpoint.
+func (p *clientPool) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	return poolCall(p, func(client EthClient) (ethereum.Subscription, error) {
+		return client.SubscribeNewHead(ctx, ch)
+	})
+}
+
+func (p *clientPool) Close() {
+	if p.cancel != nil {
+		p.cancel()
+		p.wg.Wait()
+	}
+	for _, e := range p.endpoints {
+		e.client.Close()
+	}
+}
@@ -0,0 +1,221 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/mantlenetworkio/lithosphere/metrics"
+)
+
+// testPoolChain returns the headers of a chain up to the supplied height, tagged with fork
+func testPoolChain(height int, fork byte) []*types.Header {
+	headers := make([]*types.Header, height+1)
+	for i := range headers {
+		headers[i] = &types.Header{Number: big.NewInt(int64(i)), Difficulty: common.Big0, Extra: []byte{fork}}
+		if i > 0 {
+			headers[i].ParentHash = headers[i-1].Hash()
+		}
+	}
+	return headers
+}
+
+// testPoolEndpoint is an EthClient serving the headers of a chain. Like the rpc client, its
+// requests are observed by the health of its endpoint.
+type testPoolEndpoint struct {
+	EthClient
+	health *endpointHealth
+
+	mu      sync.Mutex
+	headers []*types.Header
+	err     error
+	delay   time.Duration
+	calls   int
+}
+
+func (c *testPoolEndpoint) BlockHeaderByNumber(number *big.Int) (*types.Header, error) {
+	done := c.health.RecordRPCClientRequest("eth_getBlockByNumber")
+	header, err := c.header(number)
+	done(err)
+	return header, err
+}
+
+func (c *testPoolEndpoint) LatestSafeBlockHeader() (*types.Header, error) {
+	return c.BlockHeaderByNumber(nil)
+}
+
+func (c *testPoolEndpoint) header(number *big.Int) (*types.Header, error) {
+	c.mu.Lock()
+	c.calls++
+	headers, err, delay := c.headers, c.err, c.delay
+	c.mu.Unlock()
+
+	time.Sleep(delay)
+	if err != nil {
+		return nil, err
+	} else if number == nil {
+		return headers[len(headers)-1], nil
+	} else if number.Uint64() >= uint64(len(headers)) {
+		return nil, ethereum.NotFound
+	}
+	return headers[number.Uint64()], nil
+}
+
+func (c *testPoolEndpoint) set(err error, delay time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.err, c.delay = err, delay
+}
+
+func (c *testPoolEndpoint) callCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls
+}
+
+func (c *testPoolEndpoint) Close() {}
+
+func newTestPool(quorum int, chains ...[]*types.Header) (*clientPool, []*testPoolEndpoint) {
+	nodeMetrics := metrics.NewNodeMetrics(prometheus.NewRegistry(), "test")
+	pool := &clientPool{log: log.New(), metrics: nodeMetrics, quorum: quorum}
+	clients := make([]*testPoolEndpoint, len(chains))
+	for i, chain := range chains {
+		health := &endpointHealth{NodeMetricer: nodeMetrics}
+		clients[i] = &testPoolEndpoint{health: health, headers: chain}
+		pool.endpoints = append(pool.endpoints, &endpoint{name: fmt.Sprintf("endpoint-%d", i), client: clients[i], health: health})
+	}
+	return pool, clients
+}
+
+func TestPoolFailsOverOnError(t *testing.T) {
+	chain := testPoolChain(10, 0)
+	pool, clients := newTestPool(1, chain, chain)
+	clients[0].set(errors.New("connection refused"), 0)
+
+	header, err := pool.BlockHeaderByNumber(big.NewInt(5))
+	require.NoError(t, err)
+	require.Equal(t, chain[5].Hash(), header.Hash())
+	require.Equal(t, 1, clients[0].callCount())
+	require.Equal(t, 1, clients[1].callCount())
+
+	// the failing endpoint is ranked last, the next calls go to the healthy one first
+	_, err = pool.BlockHeaderByNumber(big.NewInt(6))
+	require.NoError(t, err)
+	require.Equal(t, 1, clients[0].callCount())
+	require.Equal(t, 2, clients[1].callCount())
+
+	// a missing block is a valid response, it isn't failed over
+	_, err = pool.BlockHeaderByNumber(big.NewInt(11))
+	require.ErrorIs(t, err, ethereum.NotFound)
+	require.Equal(t, 1, clients[0].callCount())
+	require.Equal(t, 3, clients[1].callCount())
+}
+
+func TestPoolFailsOverOnTimeout(t *testing.T) {
+	chain := testPoolChain(10, 0)
+	pool, clients := newTestPool(1, chain, chain)
+	clients[0].set(fmt.Errorf("eth_getBlockByNumber: %w", context.DeadlineExceeded), 10*time.Millisecond)
+
+	header, err := pool.BlockHeaderByNumber(big.NewInt(5))
+	require.NoError(t, err)
+	require.Equal(t, chain[5].Hash(), header.Hash())
+	require.Equal(t, 1, clients[0].callCount())
+
+	_, err = pool.BlockHeaderByNumber(big.NewInt(6))
+	require.NoError(t, err)
+	require.Equal(t, 1, clients[0].callCount())
+	require.Equal(t, 2, clients[1].callCount())
+}
+
+func TestPoolScoreRecoversAfterDecay(t *testing.T) {
+	chain := testPoolChain(10, 0)
+	pool, clients := newTestPool(1, chain, chain)
+	clients[1].set(nil, 2*time.Millisecond)
+
+	// the decayed error rate of the failing endpoint goes above the threshold
+	clients[0].set(errors.New("connection refused"), 0)
+	for i := 0; i < 10; i++ {
+		pool.updateHeadLag()
+	}
+	healthy, _ := pool.endpoints[0].health.status()
+	require.False(t, healthy)
+	require.Equal(t, pool.endpoints[1], pool.ranked()[0])
+
+	// the health checks decay the error rate once the endpoint is back: 1-0.9^10 = 0.65, then 0.53 and 0.47
+	clients[0].set(nil, 0)
+	pool.updateHeadLag()
+	pool.updateHeadLag()
+	healthy, _ = pool.endpoints[0].health.status()
+	require.False(t, healthy)
+	pool.updateHeadLag()
+	healthy, _ = pool.endpoints[0].health.status()
+	require.True(t, healthy)
+
+	// the recovered endpoint answers faster than the other one, it is preferred again
+	require.Equal(t, pool.endpoints[0], pool.ranked()[0])
+	calls := clients[1].callCount()
+	_, err := pool.BlockHeaderByNumber(big.NewInt(5))
+	require.NoError(t, err)
+	require.Equal(t, calls, clients[1].callCount())
+}
+
+func TestPoolQuorumHead(t *testing.T) {
+	chain := testPoolChain(10, 0)
+	// the minority reports a higher head of another chain
+	fork := testPoolChain(12, 1)
+	pool, clients := newTestPool(2, chain, fork, chain)
+
+	header, err := pool.BlockHeaderByNumber(nil)
+	require.NoError(t, err)
+	require.Equal(t, chain[10].Hash(), header.Hash())
+	header, err = pool.LatestSafeBlockHeader()
+	require.NoError(t, err)
+	require.Equal(t, chain[10].Hash(), header.Hash())
+
+	// the quorum can't be reached without the majority
+	clients[2].set(errors.New("connection refused"), 0)
+	_, err = pool.BlockHeaderByNumber(nil)
+	require.ErrorContains(t, err, "do not agree on the head hash")
+
+	clients[0].set(errors.New("connection refused"), 0)
+	_, err = pool.BlockHeaderByNumber(nil)
+	require.ErrorContains(t, err, "only 1 endpoints reported a head")
+}
+
+func TestPoolAllEndpointsUnhealthy(t *testing.T) {
+	chain := testPoolChain(10, 0)
+	pool, clients := newTestPool(1, chain, chain)
+	for _, client := range clients {
+		client.set(errors.New("connection refused"), 0)
+	}
+	for i := 0; i < 10; i++ {
+		pool.updateHeadLag()
+	}
+	for _, e := range pool.endpoints {
+		healthy, _ := e.health.status()
+		require.False(t, healthy)
+	}
+
+	// every endpoint is tried, the errors of all of them are returned
+	_, err := pool.BlockHeaderByNumber(big.NewInt(5))
+	require.ErrorContains(t, err, "endpoint-0: connection refused")
+	require.ErrorContains(t, err, "endpoint-1: connection refused")
+
+	// the unhealthy endpoints are still used, the first one back serves the calls
+	clients[1].set(nil, 0)
+	header, err := pool.BlockHeaderByNumber(big.NewInt(5))
+	require.NoError(t, err)
+	require.Equal(t, chain[5].Hash(), header.Hash())
+}
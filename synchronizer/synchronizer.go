@@ -5,24 +5,49 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
 
 	"github.com/ethereum-optimism/optimism/op-service/clock"
 
+	"github.com/mantlenetworkio/lithosphere/common/bigint"
+	"github.com/mantlenetworkio/lithosphere/common/tasks"
+	"github.com/mantlenetworkio/lithosphere/config"
+	"github.com/mantlenetworkio/lithosphere/database"
+	"github.com/mantlenetworkio/lithosphere/database/business"
+	"github.com/mantlenetworkio/lithosphere/event/handlers"
 	"github.com/mantlenetworkio/lithosphere/metrics"
 	"github.com/mantlenetworkio/lithosphere/synchronizer/node"
 )
 
+var errBatchReorged = errors.New("batch was reorged out while extracting logs")
+
+const (
+	headTrackingPolling      = "polling"
+	headTrackingSubscription = "subscription"
+
+	// newHeadsResubscribeBackoff is the maximum delay between attempts to re-establish a dropped subscription
+	newHeadsResubscribeBackoff = 30 * time.Second
+)
+
 type Config struct {
 	LoopIntervalMsec  uint
 	HeaderBufferSize  uint
 	StartHeight       *big.Int
 	ConfirmationDepth *big.Int
+	TraversalMode     node.TraversalMode
+	SubscribeNewHeads bool
+	ContractEvents    config.ContractEvents
+	// Handlers are the event handlers whose events are extracted along with the ContractEvents
+	Handlers *handlers.Registry
 }
 
 type Synchronizer struct {
@@ -31,11 +56,26 @@ type Synchronizer struct {
 	loopInterval     time.Duration
 	headerBufferSize uint64
 	headerTraversal  *node.HeaderTraversal
-	contracts        []common.Address
+	contracts        *contractFilter
 	syncerBatches    chan *SynchronizerBatch
 	EthClient        node.EthClient
 	headers          []types.Header
 	worker           *clock.LoopFn
+
+	// When subscribed to newHeads the loop is woken up as soon as a new block arrives and
+	// polling is skipped while at head. tickLock serializes the polling and subscription paths.
+	subscribeNewHeads bool
+	subscribed        atomic.Bool
+	atHead            bool
+	tickLock          sync.Mutex
+	stopHeadWatcher   func()
+
+	// lastIndexedHeaderBelow returns the most recent indexed header with a number lower
+	// than the one supplied, or nil if there is none. Used to walk back on a reorg.
+	lastIndexedHeaderBelow func(*big.Int) (*types.Header, error)
+
+	// notifier is published to once a batch has been committed
+	notifier tasks.Notifier
 }
 
 type SynchronizerBatch struct {
@@ -44,13 +84,33 @@ type SynchronizerBatch struct {
 	HeaderMap      map[common.Hash]*types.Header
 	Logs           []types.Log
 	HeadersWithLog map[common.Hash]bool
+
+	// CommonAncestor is only set when a reorg has been detected. The batch carries no
+	// headers or logs and everything indexed above the ancestor must be rolled back.
+	CommonAncestor *types.Header
 }
 
 func (syncer *Synchronizer) Start() error {
 	if syncer.worker != nil {
 		return errors.New("already started")
 	}
-	syncer.worker = clock.NewLoopFn(clock.SystemClock, syncer.tick, func() error {
+	syncer.metrics.RecordHeadTrackingMode(headTrackingPolling)
+	if syncer.subscribeNewHeads {
+		ctx, cancel := context.WithCancel(context.Background())
+		watcherDone := make(chan struct{})
+		go func() {
+			defer close(watcherDone)
+			syncer.watchNewHeads(ctx)
+		}()
+		syncer.stopHeadWatcher = func() {
+			cancel()
+			<-watcherDone
+		}
+	}
+	syncer.worker = clock.NewLoopFn(clock.SystemClock, syncer.pollTick, func() error {
+		if syncer.stopHeadWatcher != nil {
+			syncer.stopHeadWatcher()
+		}
 		syncer.log.Info("shutting down batch producer")
 		close(syncer.syncerBatches)
 		return nil
@@ -65,16 +125,101 @@ func (syncer *Synchronizer) Close() error {
 	return syncer.worker.Close()
 }
 
+// Subscribe returns a channel notified every time a batch, or a rollback, has been committed
+func (syncer *Synchronizer) Subscribe() <-chan struct{} {
+	return syncer.notifier.Subscribe()
+}
+
+// pollTick runs the loop on the polling interval. While new heads are delivered through the
+// subscription and the traversal is at head there is nothing to poll for, so the tick is skipped.
+func (syncer *Synchronizer) pollTick(ctx context.Context) {
+	syncer.tickLock.Lock()
+	defer syncer.tickLock.Unlock()
+	if syncer.subscribed.Load() && syncer.atHead {
+		return
+	}
+	syncer.metrics.RecordHeadTrigger(headTrackingPolling)
+	syncer.tick(ctx)
+}
+
+// watchNewHeads keeps a newHeads subscription alive, running the loop whenever a new head
+// arrives. Whenever the subscription drops, polling takes over until it is re-established.
+func (syncer *Synchronizer) watchNewHeads(ctx context.Context) {
+	heads := make(chan *types.Header, 1)
+	probe, err := syncer.EthClient.SubscribeNewHead(ctx, heads)
+	if errors.Is(err, rpc.ErrNotificationsUnsupported) {
+		syncer.log.Error("provider does not support subscriptions, polling for new heads instead")
+		return
+	} else if err == nil {
+		probe.Unsubscribe()
+	}
+
+	sub := event.ResubscribeErr(newHeadsResubscribeBackoff, func(ctx context.Context, subErr error) (event.Subscription, error) {
+		if subErr != nil {
+			syncer.log.Warn("newHeads subscription dropped, falling back to polling", "err", subErr)
+			syncer.setHeadTracking(false)
+		}
+		headSub, err := syncer.EthClient.SubscribeNewHead(ctx, heads)
+		if err != nil {
+			syncer.log.Warn("unable to subscribe to newHeads", "err", err)
+			return nil, err
+		}
+		syncer.log.Info("subscribed to newHeads")
+		syncer.setHeadTracking(true)
+		return headSub, nil
+	})
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-heads:
+			syncer.tickLock.Lock()
+			syncer.metrics.RecordHeadTrigger(headTrackingSubscription)
+			syncer.tick(ctx)
+			syncer.tickLock.Unlock()
+		}
+	}
+}
+
+func (syncer *Synchronizer) setHeadTracking(subscribed bool) {
+	syncer.subscribed.Store(subscribed)
+	if subscribed {
+		syncer.metrics.RecordHeadTrackingMode(headTrackingSubscription)
+	} else {
+		syncer.metrics.RecordHeadTrackingMode(headTrackingPolling)
+	}
+}
+
 func (syncer *Synchronizer) tick(_ context.Context) {
 	done := syncer.metrics.RecordInterval()
+	syncer.atHead = false
 	if len(syncer.headers) > 0 {
 		syncer.log.Info("retrying previous batch")
 	} else {
 		newHeaders, err := syncer.headerTraversal.NextHeaders(syncer.headerBufferSize)
-		if err != nil {
+		if errors.Is(err, node.ErrHeaderTraversalReorg) {
+			syncer.log.Warn("detected reorg of the last traversed header")
+			done(syncer.handleReorg())
+			return
+		} else if errors.Is(err, node.ErrHeaderTraversalAheadOfProvider) {
+			reorged, reorgErr := syncer.providerReorged()
+			if reorgErr != nil {
+				syncer.log.Error("unable to check the provider's chain", "err", reorgErr)
+				done(reorgErr)
+				return
+			} else if reorged {
+				syncer.log.Warn("detected reorg of the provider's chain below the last traversed header")
+				done(syncer.handleReorg())
+				return
+			}
+			syncer.log.Warn("provider is behind the last traversed header")
+		} else if err != nil {
 			syncer.log.Error("error querying for headers", "err", err)
 		} else if len(newHeaders) == 0 {
 			syncer.log.Warn("no new headers. syncer at head?")
+			syncer.atHead = true
 		} else {
 			syncer.headers = newHeaders
 		}
@@ -82,19 +227,110 @@ func (syncer *Synchronizer) tick(_ context.Context) {
 		latestHeader := syncer.headerTraversal.LatestHeader()
 		if latestHeader != nil {
 			syncer.metrics.RecordBatchLatestHeight(latestHeader.Number)
+			syncer.metrics.RecordBatchTraversalHead(string(syncer.headerTraversal.Mode()), latestHeader.Number)
 		}
 	}
 	err := syncer.processBatch(syncer.headers)
 	if err == nil {
 		syncer.headers = nil
+	} else if errors.Is(err, errBatchReorged) {
+		// retrying the same headers will never succeed
+		syncer.headers = nil
+		if reorgErr := syncer.handleReorg(); reorgErr != nil {
+			err = errors.Join(err, reorgErr)
+		}
 	}
 	done(err)
 }
 
+// handleReorg locates the common ancestor between the traversed headers and the canonical
+// chain, hands it off to the batch handler to roll back any indexed state above it and
+// rewinds the traversal to resume from there.
+func (syncer *Synchronizer) handleReorg() error {
+	reorgedHeader := syncer.headerTraversal.LastTraversedHeader()
+	commonAncestor, err := syncer.findCommonAncestor()
+	if err != nil {
+		syncer.log.Error("unable to locate common ancestor", "err", err)
+		return err
+	}
+
+	depth := new(big.Int).Sub(reorgedHeader.Number, commonAncestor.Number)
+	reorgLog := syncer.log.New("common_ancestor_number", commonAncestor.Number, "common_ancestor_hash", commonAncestor.Hash(), "depth", depth)
+	reorgLog.Warn("rolling back to common ancestor")
+
+	syncer.syncerBatches <- &SynchronizerBatch{Logger: reorgLog, CommonAncestor: commonAncestor}
+	syncer.headerTraversal.Rewind(commonAncestor)
+	syncer.metrics.RecordReorg(depth.Uint64())
+	return nil
+}
+
+// providerReorged reports whether the provider fell behind the last traversed header because of a reorg, when the
+// header is no longer part of its chain. The provider's chain may also be shorter than the traversed headers, it has
+// reorged if its head differs from the indexed header at that height. A provider lagging behind on the traversed chain,
+// or whose head hasn't been indexed yet, is waited for.
+func (syncer *Synchronizer) providerReorged() (bool, error) {
+	lastHeader := syncer.headerTraversal.LastTraversedHeader()
+	canonicalHeader, err := syncer.EthClient.BlockHeaderByNumber(lastHeader.Number)
+	if err != nil && !errors.Is(err, ethereum.NotFound) {
+		return false, fmt.Errorf("unable to query canonical header: %w", err)
+	} else if canonicalHeader != nil {
+		return canonicalHeader.Hash() != lastHeader.Hash(), nil
+	}
+
+	head := syncer.headerTraversal.LatestHeader()
+	if head == nil {
+		return false, nil
+	}
+	indexedHeader, err := syncer.lastIndexedHeaderBelow(new(big.Int).Add(head.Number, bigint.One))
+	if err != nil {
+		return false, fmt.Errorf("unable to query indexed header: %w", err)
+	} else if indexedHeader == nil || indexedHeader.Number.Cmp(head.Number) != 0 {
+		return false, nil
+	}
+	return indexedHeader.Hash() != head.Hash(), nil
+}
+
+// rejectedTransitions records the bridge status transitions rejected by a rollback, which carries on past them
+func (syncer *Synchronizer) rejectedTransitions(err error) error {
+	return business.HandleRejectedTransitions(err, func(transition business.RejectedTransition) {
+		syncer.metrics.RecordRejectedTransition(transition.Bridge, transition.From, transition.To)
+	})
+}
+
+// findCommonAncestor walks back from the last traversed header, through the indexed headers,
+// until it finds one that is still part of the canonical chain reported by the provider.
+func (syncer *Synchronizer) findCommonAncestor() (*types.Header, error) {
+	header := syncer.headerTraversal.LastTraversedHeader()
+	for header != nil {
+		canonicalHeader, err := syncer.EthClient.BlockHeaderByNumber(header.Number)
+		if err != nil && !errors.Is(err, ethereum.NotFound) {
+			return nil, fmt.Errorf("unable to query canonical header: %w", err)
+		} else if canonicalHeader != nil && canonicalHeader.Hash() == header.Hash() {
+			return header, nil
+		}
+
+		header, err = syncer.lastIndexedHeaderBelow(header.Number)
+		if err != nil {
+			return nil, fmt.Errorf("unable to query indexed header: %w", err)
+		}
+	}
+	return nil, errors.New("no indexed header is part of the canonical chain")
+}
+
 func (syncer *Synchronizer) processBatch(headers []types.Header) error {
 	if len(headers) == 0 {
 		return nil
 	}
+	batch, err := syncer.extractBatch(headers)
+	if err != nil {
+		return err
+	}
+	syncer.syncerBatches <- batch
+	return nil
+}
+
+// extractBatch retrieves the logs of the supplied headers, ensuring they are all part of the same chain
+func (syncer *Synchronizer) extractBatch(headers []types.Header) (*SynchronizerBatch, error) {
 
 	firstHeader, lastHeader := headers[0], headers[len(headers)-1]
 	batchLog := syncer.log.New("batch_start_block_number", firstHeader.Number, "batch_end_block_number", lastHeader.Number)
@@ -107,40 +343,90 @@ func (syncer *Synchronizer) processBatch(headers []types.Header) error {
 	}
 
 	headersWithLog := make(map[common.Hash]bool, len(headers))
-	filterQuery := ethereum.FilterQuery{FromBlock: firstHeader.Number, ToBlock: lastHeader.Number, Addresses: syncer.contracts}
-	logs, err := syncer.EthClient.FilterLogs(filterQuery)
+	logs, err := syncer.EthClient.FilterLogs(syncer.contracts.query(firstHeader.Number, lastHeader.Number))
 	if err != nil {
 		batchLog.Info("failed to extract logs", "err", err)
-		return err
+		return nil, err
 	}
 
 	if logs.ToBlockHeader.Number.Cmp(lastHeader.Number) != 0 {
 		// Warn and simply wait for the provider to synchronize state
 		batchLog.Warn("mismatch in FilterLog#ToBlock number", "queried_to_block_number", lastHeader.Number, "reported_to_block_number", logs.ToBlockHeader.Number)
-		return fmt.Errorf("mismatch in FilterLog#ToBlock number")
+		return nil, fmt.Errorf("mismatch in FilterLog#ToBlock number")
 	} else if logs.ToBlockHeader.Hash() != lastHeader.Hash() {
 		batchLog.Error("mismatch in FitlerLog#ToBlock block hash!!!", "queried_to_block_hash", lastHeader.Hash().String(), "reported_to_block_hash", logs.ToBlockHeader.Hash().String())
-		return fmt.Errorf("mismatch in FitlerLog#ToBlock block hash!!!")
-	}
-
-	if len(logs.Logs) > 0 {
-		batchLog.Info("detected logs", "size", len(logs.Logs))
+		return nil, errBatchReorged
 	}
 
+	batchLogs := make([]types.Log, 0, len(logs.Logs))
 	for i := range logs.Logs {
 		log := logs.Logs[i]
-		headersWithLog[log.BlockHash] = true
 		if _, ok := headerMap[log.BlockHash]; !ok {
-			// NOTE. Definitely an error state if the none of the headers were re-orged out in between
-			// the blocks and logs retrieval operations. Unlikely as long as the confirmation depth has
-			// been appropriately set or when we get to natively handling reorgs.
+			// One of the headers was re-orged out in between the blocks and logs retrieval operations
 			batchLog.Error("log found with block hash not in the batch", "block_hash", logs.Logs[i].BlockHash, "log_index", logs.Logs[i].Index)
-			return errors.New("parsed log with a block hash not in the batch")
+			return nil, errBatchReorged
+		}
+		if !syncer.contracts.allowed(&log) {
+			// the event of another contract sharing the signature
+			continue
 		}
+		headersWithLog[log.BlockHash] = true
+		batchLogs = append(batchLogs, log)
+	}
+
+	if len(batchLogs) > 0 {
+		batchLog.Info("detected logs", "size", len(batchLogs))
 	}
 
 	// ensure we use unique downstream references for the syncer batch
 	headersRef := headers
-	syncer.syncerBatches <- &SynchronizerBatch{Logger: batchLog, Headers: headersRef, HeaderMap: headerMap, Logs: logs.Logs, HeadersWithLog: headersWithLog}
+	return &SynchronizerBatch{Logger: batchLog, Headers: headersRef, HeaderMap: headerMap, Logs: batchLogs, HeadersWithLog: headersWithLog}, nil
+}
+
+// reindexBatches refetches the headers and logs of the [from, to] range, handing them over in
+// batches of at most the header buffer size. The range is expected to be below the traversed head.
+func (syncer *Synchronizer) reindexBatches(from, to *big.Int, handle func(*SynchronizerBatch) error) error {
+	var parent *types.Header
+	for start := from; start.Cmp(to) <= 0; {
+		end := bigint.Clamp(start, to, syncer.headerBufferSize)
+		headers, err := syncer.EthClient.BlockHeadersByRange(start, end)
+		if err != nil {
+			return fmt.Errorf("error querying blocks by range: %w", err)
+		} else if len(headers) == 0 {
+			return fmt.Errorf("no headers returned for range %d-%d", start, end)
+		} else if parent != nil && headers[0].ParentHash != parent.Hash() {
+			return errBatchReorged
+		}
+
+		batch, err := syncer.extractBatch(headers)
+		if err != nil {
+			return err
+		}
+		if err := handle(batch); err != nil {
+			return err
+		}
+		parent = &headers[len(headers)-1]
+		start = new(big.Int).Add(parent.Number, bigint.One)
+	}
 	return nil
 }
+
+// cursorHeader returns the header the named sync cursor points to. It is nil when there is no cursor
+// yet or the provider doesn't know the block anymore, in which case traversal resumes from the indexed headers.
+func cursorHeader(log log.Logger, db *database.DB, client node.EthClient, name string) (*types.Header, error) {
+	cursor, err := db.SyncCursors.SyncCursor(name)
+	if err != nil {
+		return nil, err
+	} else if cursor == nil {
+		return nil, nil
+	}
+
+	header, err := client.BlockHeaderByHash(cursor.BlockHash)
+	if errors.Is(err, ethereum.NotFound) {
+		log.Warn("sync cursor header not found by the provider", "number", cursor.BlockNumber, "hash", cursor.BlockHash)
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("could not fetch sync cursor header: %w", err)
+	}
+	return header, nil
+}
@@ -0,0 +1,138 @@
+package synchronizer
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/mantlenetworkio/lithosphere/metrics"
+	"github.com/mantlenetworkio/lithosphere/synchronizer/node"
+)
+
+// testChain extends the shared headers up to the supplied length, tagging the new headers with fork
+func testChain(shared []*types.Header, length int, fork byte) []*types.Header {
+	headers := append([]*types.Header{}, shared...)
+	for i := len(shared); i < length; i++ {
+		header := &types.Header{Number: big.NewInt(int64(i)), Difficulty: common.Big0, Time: uint64(i), Extra: []byte{fork}}
+		if i > 0 {
+			header.ParentHash = headers[i-1].Hash()
+		}
+		headers = append(headers, header)
+	}
+	return headers
+}
+
+// TestReorgRollsBackToCommonAncestor replays testdata/reorg.json, recorded from a node whose
+// canonical chain replaced the indexed headers 3 and 4 and then grew to height 5.
+func TestReorgRollsBackToCommonAncestor(t *testing.T) {
+	indexed := testChain(nil, 5, 0)
+	canonical := testChain(indexed[:3], 6, 1)
+
+	client, err := node.NewReplayEthClient("testdata/reorg.json")
+	require.NoError(t, err)
+
+	batches := make(chan *SynchronizerBatch, 1)
+	syncer := &Synchronizer{
+		log:              log.New(),
+		metrics:          metrics.NewMetrics(prometheus.NewRegistry(), "test"),
+		headerBufferSize: 10,
+		headerTraversal:  node.NewHeaderTraversal(client, indexed[4], big.NewInt(0), node.TraversalModeLatest),
+		syncerBatches:    batches,
+		EthClient:        client,
+		lastIndexedHeaderBelow: func(number *big.Int) (*types.Header, error) {
+			if number.Sign() == 0 {
+				return nil, nil
+			}
+			return indexed[number.Uint64()-1], nil
+		},
+	}
+
+	syncer.tick(context.Background())
+
+	// the new head doesn't build on the last traversed header, the rollback is handed off instead of a batch
+	require.Len(t, batches, 1)
+	batch := <-batches
+	require.Empty(t, batch.Headers)
+	require.NotNil(t, batch.CommonAncestor)
+	require.Equal(t, indexed[2].Hash(), batch.CommonAncestor.Hash())
+	require.Equal(t, canonical[2].Hash(), batch.CommonAncestor.Hash())
+
+	// traversal resumes from the common ancestor
+	require.Equal(t, indexed[2].Hash(), syncer.headerTraversal.LastTraversedHeader().Hash())
+	require.Empty(t, syncer.headers)
+}
+
+// testChainClient serves the headers of the provider's canonical chain
+type testChainClient struct {
+	node.EthClient
+	headers []*types.Header
+}
+
+func (c testChainClient) BlockHeaderByNumber(number *big.Int) (*types.Header, error) {
+	if number == nil {
+		return c.headers[len(c.headers)-1], nil
+	} else if number.Uint64() >= uint64(len(c.headers)) {
+		return nil, ethereum.NotFound
+	}
+	return c.headers[number.Uint64()], nil
+}
+
+func TestProviderBehindLastTraversedHeader(t *testing.T) {
+	indexed := testChain(nil, 5, 0)
+	tests := []struct {
+		name           string
+		canonical      []*types.Header
+		confDepth      int64
+		commonAncestor *types.Header
+	}{
+		// the reorg left the provider's chain shorter than the traversed headers
+		{"shorter reorged chain", testChain(indexed[:3], 4, 1), 0, indexed[2]},
+		// the last traversed header is within the confirmation depth of the reorged chain
+		{"reorged within the confirmation depth", testChain(indexed[:3], 6, 1), 2, indexed[2]},
+		// the provider lags behind on the traversed chain
+		{"lagging provider", indexed[:3], 0, nil},
+		{"lagging within the confirmation depth", indexed, 2, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := testChainClient{headers: test.canonical}
+			batches := make(chan *SynchronizerBatch, 1)
+			syncer := &Synchronizer{
+				log:              log.New(),
+				metrics:          metrics.NewMetrics(prometheus.NewRegistry(), "test"),
+				headerBufferSize: 10,
+				headerTraversal:  node.NewHeaderTraversal(client, indexed[4], big.NewInt(test.confDepth), node.TraversalModeLatest),
+				syncerBatches:    batches,
+				EthClient:        client,
+				lastIndexedHeaderBelow: func(number *big.Int) (*types.Header, error) {
+					if number.Sign() == 0 {
+						return nil, nil
+					}
+					return indexed[min(number.Uint64(), uint64(len(indexed)))-1], nil
+				},
+			}
+
+			syncer.tick(context.Background())
+
+			if test.commonAncestor == nil {
+				// waited for, nothing is rolled back
+				require.Empty(t, batches)
+				require.Equal(t, indexed[4].Hash(), syncer.headerTraversal.LastTraversedHeader().Hash())
+				return
+			}
+			require.Len(t, batches, 1)
+			batch := <-batches
+			require.Empty(t, batch.Headers)
+			require.Equal(t, test.commonAncestor.Hash(), batch.CommonAncestor.Hash())
+			require.Equal(t, test.commonAncestor.Hash(), syncer.headerTraversal.LastTraversedHeader().Hash())
+		})
+	}
+}